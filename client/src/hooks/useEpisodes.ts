@@ -7,13 +7,24 @@ import type { EpisodeData } from '@/types/episode';
 
 const API_BASE_URL = 'https://eventsure-production.up.railway.app';
 
+// Follows nextCursor until the last page, so episodes past the first page are not dropped
 const fetchEpisodeAddresses = async (): Promise<`0x${string}`[]> => {
-  const response = await fetch(`${API_BASE_URL}/api/episodes`);
-  if (!response.ok) {
-    throw new Error('Failed to fetch episodes from API');
-  }
-  const data = await response.json();
-  return (data.episodes as string[]).map(addr => addr.toLowerCase() as `0x${string}`)
+  const addresses: `0x${string}`[] = [];
+  let cursor: string | undefined;
+  do {
+    const params = new URLSearchParams({ limit: '100' });
+    if (cursor) {
+      params.set('cursor', cursor);
+    }
+    const response = await fetch(`${API_BASE_URL}/api/v2/episodes?${params}`);
+    if (!response.ok) {
+      throw new Error('Failed to fetch episodes from API');
+    }
+    const data: { episodes: { address: string }[]; nextCursor?: string } = await response.json();
+    addresses.push(...data.episodes.map(episode => episode.address.toLowerCase() as `0x${string}`));
+    cursor = data.nextCursor;
+  } while (cursor);
+  return addresses;
 };
 
 export const useEpisodes = (useContractDirectly = false) => {
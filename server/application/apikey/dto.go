@@ -0,0 +1,62 @@
+package apikey
+
+import (
+	"time"
+
+	domainapikey "eventsure-server/domain/apikey"
+)
+
+// IssueAPIKeyRequest represents the request to issue an API key to a partner
+type IssueAPIKeyRequest struct {
+	Name string `json:"name"`
+	// Scopes are read, write and admin
+	Scopes []string `json:"scopes"`
+	// RateLimitPerMinute overrides the default limit of API keys when positive
+	RateLimitPerMinute int `json:"rateLimitPerMinute,omitempty"`
+}
+
+// APIKeyDTO represents an issued API key; the secret itself is never returned again
+type APIKeyDTO struct {
+	ID                 int64    `json:"id"`
+	Name               string   `json:"name"`
+	Prefix             string   `json:"prefix"`
+	Scopes             []string `json:"scopes"`
+	RateLimitPerMinute int      `json:"rateLimitPerMinute"`
+	Active             bool     `json:"active"`
+	CreatedAt          string   `json:"createdAt"`
+	RevokedAt          *string  `json:"revokedAt,omitempty"`
+}
+
+// IssueAPIKeyResponse represents a newly issued API key with its secret, shown only once
+type IssueAPIKeyResponse struct {
+	APIKey APIKeyDTO `json:"apiKey"`
+	Key    string    `json:"key"`
+}
+
+// ListAPIKeysResponse represents every issued API key, revoked ones included
+type ListAPIKeysResponse struct {
+	APIKeys []APIKeyDTO `json:"apiKeys"`
+}
+
+// toAPIKeyDTO converts the domain aggregate to its DTO
+func toAPIKeyDTO(key *domainapikey.APIKey) APIKeyDTO {
+	scopes := make([]string, len(key.Scopes()))
+	for i, scope := range key.Scopes() {
+		scopes[i] = string(scope)
+	}
+
+	dto := APIKeyDTO{
+		ID:                 key.ID(),
+		Name:               key.Name(),
+		Prefix:             key.Prefix(),
+		Scopes:             scopes,
+		RateLimitPerMinute: key.RateLimit(),
+		Active:             !key.Revoked(),
+		CreatedAt:          key.CreatedAt().UTC().Format(time.RFC3339),
+	}
+	if key.Revoked() {
+		revokedAt := key.RevokedAt().UTC().Format(time.RFC3339)
+		dto.RevokedAt = &revokedAt
+	}
+	return dto
+}
@@ -0,0 +1,230 @@
+package apikey
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"sync"
+	"time"
+
+	"eventsure-server/application/apperr"
+	domainapikey "eventsure-server/domain/apikey"
+	"eventsure-server/infrastructure/config"
+	"eventsure-server/infrastructure/metrics"
+	"eventsure-server/infrastructure/tracing"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const (
+	// cacheTTL is how long a looked up key, or its absence, is reused before asking the repository again
+	cacheTTL = time.Minute
+	// maxCacheEntries bounds the cache against clients sending random keys
+	maxCacheEntries = 10000
+)
+
+var (
+	// ErrAPIKeyNotFound is returned when no API key has the requested ID
+	ErrAPIKeyNotFound = apperr.NotFound("api key not found")
+	// ErrInvalidAPIKey is returned for a secret that matches no API key
+	ErrInvalidAPIKey = apperr.Unauthorized("invalid API key")
+	// ErrRevokedAPIKey is returned for the secret of a revoked API key
+	ErrRevokedAPIKey = apperr.Unauthorized("API key has been revoked")
+)
+
+// Principal is the client a request was authenticated as
+type Principal struct {
+	// KeyID is the ID of the API key, 0 for the admin key
+	KeyID int64
+	Name  string
+	// Scopes are the scopes granted to the key
+	Scopes []domainapikey.Scope
+	// RateLimit is the requests per minute allowed to the key, 0 for the default
+	RateLimit int
+}
+
+// HasScope reports whether the principal was granted scope; admin grants every scope
+func (p *Principal) HasScope(scope domainapikey.Scope) bool {
+	for _, s := range p.Scopes {
+		if s == scope || s == domainapikey.ScopeAdmin {
+			return true
+		}
+	}
+	return false
+}
+
+// cacheEntry is a looked up key; key is nil when no key has the hash
+type cacheEntry struct {
+	key     *domainapikey.APIKey
+	expires time.Time
+}
+
+// UseCase handles API key use cases: issuing and revoking keys and authenticating requests
+type UseCase struct {
+	repo         domainapikey.Repository
+	adminKeyHash string
+
+	mu    sync.Mutex
+	cache map[string]cacheEntry
+}
+
+// NewUseCase creates a new API key UseCase; auth.AdminAPIKey, when set, authenticates as admin
+func NewUseCase(repo domainapikey.Repository, auth config.Auth) *UseCase {
+	uc := &UseCase{
+		repo:  repo,
+		cache: make(map[string]cacheEntry),
+	}
+	if auth.AdminAPIKey != "" {
+		uc.adminKeyHash = domainapikey.HashSecret(auth.AdminAPIKey)
+	}
+	return uc
+}
+
+// Issue issues a new API key and returns it with its secret, which cannot be retrieved later
+func (uc *UseCase) Issue(ctx context.Context, req IssueAPIKeyRequest) (response *IssueAPIKeyResponse, err error) {
+	ctx, span := tracing.Start(ctx, "domainapikey.Issue", attribute.String("api_key.name", req.Name))
+	defer func() { tracing.End(span, err) }()
+
+	if uc.repo == nil {
+		return nil, apperr.Unavailable("api key repository is not initialized", nil)
+	}
+
+	scopes := make([]domainapikey.Scope, 0, len(req.Scopes))
+	for _, name := range req.Scopes {
+		scope, ok := domainapikey.ParseScope(name)
+		if !ok {
+			return nil, apperr.Validation(fmt.Sprintf("unknown scope %q", name))
+		}
+		scopes = append(scopes, scope)
+	}
+
+	key, secret, err := domainapikey.NewAPIKey(req.Name, scopes, req.RateLimitPerMinute)
+	if err != nil {
+		return nil, apperr.Validation(err.Error())
+	}
+
+	created, err := uc.repo.Create(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &IssueAPIKeyResponse{
+		APIKey: toAPIKeyDTO(created),
+		Key:    secret,
+	}, nil
+}
+
+// List returns every issued API key, revoked ones included, oldest first
+func (uc *UseCase) List(ctx context.Context) (response *ListAPIKeysResponse, err error) {
+	ctx, span := tracing.Start(ctx, "domainapikey.List")
+	defer func() { tracing.End(span, err) }()
+
+	if uc.repo == nil {
+		return nil, apperr.Unavailable("api key repository is not initialized", nil)
+	}
+
+	keys, err := uc.repo.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	dtos := make([]APIKeyDTO, len(keys))
+	for i, key := range keys {
+		dtos[i] = toAPIKeyDTO(key)
+	}
+	return &ListAPIKeysResponse{APIKeys: dtos}, nil
+}
+
+// Revoke revokes the API key with the ID; it stops authenticating immediately on this instance
+// and within a minute on others. Revoking a revoked key is a no-op.
+func (uc *UseCase) Revoke(ctx context.Context, id int64) (response *APIKeyDTO, err error) {
+	ctx, span := tracing.Start(ctx, "domainapikey.Revoke", attribute.Int64("api_key.id", id))
+	defer func() { tracing.End(span, err) }()
+
+	if uc.repo == nil {
+		return nil, apperr.Unavailable("api key repository is not initialized", nil)
+	}
+
+	key, err := uc.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if key == nil {
+		return nil, ErrAPIKeyNotFound
+	}
+
+	if !key.Revoked() {
+		key.Revoke(time.Now())
+		if err := uc.repo.Update(ctx, key); err != nil {
+			return nil, err
+		}
+	}
+
+	uc.mu.Lock()
+	delete(uc.cache, key.Hash())
+	uc.mu.Unlock()
+
+	dto := toAPIKeyDTO(key)
+	return &dto, nil
+}
+
+// Authenticate returns the principal of the secret, or ErrInvalidAPIKey or ErrRevokedAPIKey.
+// Lookups are cached for a minute so that authenticated requests rarely reach the repository.
+func (uc *UseCase) Authenticate(ctx context.Context, secret string) (*Principal, error) {
+	hash := domainapikey.HashSecret(secret)
+	if uc.adminKeyHash != "" && subtle.ConstantTimeCompare([]byte(hash), []byte(uc.adminKeyHash)) == 1 {
+		return &Principal{Name: "admin", Scopes: []domainapikey.Scope{domainapikey.ScopeAdmin}}, nil
+	}
+	if !domainapikey.LooksLikeSecret(secret) {
+		return nil, ErrInvalidAPIKey
+	}
+
+	key, err := uc.lookup(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	if key == nil {
+		return nil, ErrInvalidAPIKey
+	}
+	if key.Revoked() {
+		return nil, ErrRevokedAPIKey
+	}
+
+	return &Principal{
+		KeyID:     key.ID(),
+		Name:      key.Name(),
+		Scopes:    key.Scopes(),
+		RateLimit: key.RateLimit(),
+	}, nil
+}
+
+// lookup finds the key with the hash through the cache; it returns nil when no key has the hash
+func (uc *UseCase) lookup(ctx context.Context, hash string) (*domainapikey.APIKey, error) {
+	now := time.Now()
+
+	uc.mu.Lock()
+	entry, ok := uc.cache[hash]
+	uc.mu.Unlock()
+	hit := ok && now.Before(entry.expires)
+	metrics.ObserveCacheLookup("api_keys", hit)
+	if hit {
+		return entry.key, nil
+	}
+
+	if uc.repo == nil {
+		return nil, apperr.Unavailable("api key repository is not initialized", nil)
+	}
+	key, err := uc.repo.FindByHash(ctx, hash)
+	if err != nil {
+		return nil, apperr.Unavailable("failed to look up API key", err)
+	}
+
+	uc.mu.Lock()
+	if len(uc.cache) >= maxCacheEntries {
+		uc.cache = make(map[string]cacheEntry)
+	}
+	uc.cache[hash] = cacheEntry{key: key, expires: now.Add(cacheTTL)}
+	uc.mu.Unlock()
+
+	return key, nil
+}
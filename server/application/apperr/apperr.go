@@ -0,0 +1,100 @@
+package apperr
+
+import (
+	"errors"
+)
+
+// Kinds of application errors. Match them with errors.Is, e.g. errors.Is(err, apperr.ErrNotFound).
+var (
+	ErrValidation   = errors.New("validation failed")
+	ErrNotFound     = errors.New("not found")
+	ErrConflict     = errors.New("conflict")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrForbidden    = errors.New("forbidden")
+	ErrRateLimited  = errors.New("rate limited")
+	ErrUnavailable  = errors.New("upstream unavailable")
+	ErrTooLarge     = errors.New("request too large")
+)
+
+// Error is an application error of one of the kinds above.
+// Message and Details are safe to return to clients; Cause is the underlying
+// error, which may carry upstream URLs or credentials and is only logged after logging.Redact.
+type Error struct {
+	Kind    error
+	Message string
+	Details map[string]interface{}
+	Cause   error
+}
+
+// Error returns the message followed by the cause, if any
+func (e *Error) Error() string {
+	if e.Cause == nil {
+		return e.Message
+	}
+	return e.Message + ": " + e.Cause.Error()
+}
+
+// Unwrap exposes both the kind and the cause to errors.Is and errors.As
+func (e *Error) Unwrap() []error {
+	if e.Cause == nil {
+		return []error{e.Kind}
+	}
+	return []error{e.Kind, e.Cause}
+}
+
+// WithDetails returns a copy of the error carrying details
+func (e *Error) WithDetails(details map[string]interface{}) *Error {
+	copied := *e
+	copied.Details = details
+	return &copied
+}
+
+// New creates an error of kind with a client-facing message, wrapping cause (may be nil)
+func New(kind error, message string, cause error) *Error {
+	return &Error{
+		Kind:    kind,
+		Message: message,
+		Cause:   cause,
+	}
+}
+
+// Validation creates an error for a malformed or incomplete request
+func Validation(message string) *Error {
+	return New(ErrValidation, message, nil)
+}
+
+// NotFound creates an error for a resource that does not exist
+func NotFound(message string) *Error {
+	return New(ErrNotFound, message, nil)
+}
+
+// Conflict creates an error for a request that conflicts with the current state
+func Conflict(message string) *Error {
+	return New(ErrConflict, message, nil)
+}
+
+// Unauthorized creates an error for a request without valid credentials
+func Unauthorized(message string) *Error {
+	return New(ErrUnauthorized, message, nil)
+}
+
+// Forbidden creates an error for valid credentials that lack a permission
+func Forbidden(message string) *Error {
+	return New(ErrForbidden, message, nil)
+}
+
+// RateLimited creates an error for a client that exceeded its rate limit
+func RateLimited(message string) *Error {
+	return New(ErrRateLimited, message, nil)
+}
+
+// Unavailable creates an error for a failed or unconfigured upstream service
+// (Etherscan, Supabase, a missing dataset)
+func Unavailable(message string, cause error) *Error {
+	return New(ErrUnavailable, message, cause)
+}
+
+// TooLarge creates an error for a request body over the size limit
+func TooLarge(message string) *Error {
+	return New(ErrTooLarge, message, nil)
+}
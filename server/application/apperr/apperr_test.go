@@ -0,0 +1,68 @@
+package apperr
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestConstructorKinds(t *testing.T) {
+	cause := errors.New("connection refused")
+	tests := []struct {
+		name string
+		err  *Error
+		kind error
+	}{
+		{"Validation", Validation("bad"), ErrValidation},
+		{"NotFound", NotFound("missing"), ErrNotFound},
+		{"Conflict", Conflict("taken"), ErrConflict},
+		{"Unauthorized", Unauthorized("no key"), ErrUnauthorized},
+		{"Forbidden", Forbidden("no scope"), ErrForbidden},
+		{"RateLimited", RateLimited("slow down"), ErrRateLimited},
+		{"Unavailable", Unavailable("etherscan failed", cause), ErrUnavailable},
+		{"TooLarge", TooLarge("too big"), ErrTooLarge},
+	}
+	kinds := []error{ErrValidation, ErrNotFound, ErrConflict, ErrUnauthorized,
+		ErrForbidden, ErrRateLimited, ErrUnavailable, ErrTooLarge}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, kind := range kinds {
+				if got := errors.Is(tt.err, kind); got != (kind == tt.kind) {
+					t.Errorf("errors.Is(%v, %v) = %v", tt.err, kind, got)
+				}
+			}
+			// The kind survives wrapping
+			if wrapped := fmt.Errorf("handler: %w", tt.err); !errors.Is(wrapped, tt.kind) {
+				t.Errorf("errors.Is(wrapped, %v) = false", tt.kind)
+			}
+		})
+	}
+}
+
+func TestErrorCause(t *testing.T) {
+	cause := errors.New("dial tcp: connection refused")
+	err := Unavailable("etherscan failed", cause)
+
+	if got, want := err.Error(), "etherscan failed: dial tcp: connection refused"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+	if !errors.Is(err, cause) || !errors.Is(err, ErrUnavailable) {
+		t.Error("errors.Is does not match both the cause and the kind")
+	}
+	if got := NotFound("episode not found").Error(); got != "episode not found" {
+		t.Errorf("Error() without a cause = %q, want the message", got)
+	}
+}
+
+func TestWithDetailsCopies(t *testing.T) {
+	err := Validation("invalid address")
+	detailed := err.WithDetails(map[string]interface{}{"field": "user"})
+
+	if err.Details != nil {
+		t.Errorf("WithDetails changed the original details to %v", err.Details)
+	}
+	if detailed.Details["field"] != "user" || detailed.Message != err.Message || detailed.Kind != err.Kind {
+		t.Errorf("WithDetails() = %+v", detailed)
+	}
+}
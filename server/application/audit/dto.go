@@ -0,0 +1,22 @@
+package audit
+
+// FindingDTO represents a violated invariant rule
+type FindingDTO struct {
+	Invariant       int    `json:"invariant"`
+	Rule            string `json:"rule"`
+	Message         string `json:"message"`
+	TransactionHash string `json:"transactionHash,omitempty"`
+}
+
+// AuditResponse represents the invariant audit of an episode.
+// Amounts are in wei and encoded as decimal strings.
+type AuditResponse struct {
+	Episode      string       `json:"episode"`
+	AuditedAt    string       `json:"auditedAt"`
+	State        string       `json:"state"`
+	TotalPremium string       `json:"totalPremium"`
+	Balance      string       `json:"balance"`
+	Events       int          `json:"events"`
+	Passed       bool         `json:"passed"`
+	Findings     []FindingDTO `json:"findings"`
+}
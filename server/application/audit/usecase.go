@@ -0,0 +1,283 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strconv"
+	"sync"
+	"time"
+
+	"eventsure-server/application/apperr"
+	"eventsure-server/application/indexer"
+	domainaudit "eventsure-server/domain/audit"
+	"eventsure-server/domain/chain"
+	"eventsure-server/domain/episode"
+	"eventsure-server/domain/event"
+	"eventsure-server/infrastructure/config"
+	"eventsure-server/infrastructure/contract"
+	"eventsure-server/infrastructure/etherscan"
+	"eventsure-server/infrastructure/tracing"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// UseCase audits episodes against the core invariants of docs/2_spec.md.
+// Each violation is alerted once, the first time it is found, and again only if it
+// disappears and comes back. The alerted violations are kept in memory, so after a restart
+// the violations still present are alerted once more.
+type UseCase struct {
+	store     event.Store
+	indexer   *indexer.Indexer
+	alerter   domainaudit.Alerter
+	etherscan config.Etherscan
+	contracts config.Contracts
+
+	mu sync.Mutex
+	// reports holds the latest audit of each factory episode
+	reports map[string]*AuditResponse
+	// alerted holds the keys of the alerted findings of each episode still present in its latest audit
+	alerted map[string]map[string]bool
+}
+
+// NewUseCase creates a new audit UseCase.
+// When ix is not nil the episode is indexed up to the latest block before every audit,
+// so the replayed events and the on-chain balance describe the same chain state.
+func NewUseCase(store event.Store, ix *indexer.Indexer, alerter domainaudit.Alerter, etherscanCfg config.Etherscan, contracts config.Contracts) *UseCase {
+	return &UseCase{
+		store:     store,
+		indexer:   ix,
+		alerter:   alerter,
+		etherscan: etherscanCfg,
+		contracts: contracts,
+		reports:   make(map[string]*AuditResponse),
+		alerted:   make(map[string]map[string]bool),
+	}
+}
+
+// LatestAudit returns the latest audit of an episode, made by Run or AuditEpisode.
+// It does not read the chain.
+func (uc *UseCase) LatestAudit(ctx context.Context, episodeAddress chain.Address) (*AuditResponse, error) {
+	address := episodeAddress.String()
+
+	uc.mu.Lock()
+	report, ok := uc.reports[address]
+	uc.mu.Unlock()
+	if !ok {
+		return nil, apperr.NotFound("episode " + address + " has not been audited")
+	}
+	return report, nil
+}
+
+// AuditEpisode audits a single episode and alerts violations not alerted before
+func (uc *UseCase) AuditEpisode(ctx context.Context, episodeAddress chain.Address) (response *AuditResponse, err error) {
+	ctx, span := tracing.Start(ctx, "audit.AuditEpisode", attribute.String("episode.address", episodeAddress.String()))
+	defer func() { tracing.End(span, err) }()
+
+	address := episodeAddress.String()
+
+	factoryAddress := uc.contracts.EpisodeFactory
+	if factoryAddress == "" {
+		return nil, apperr.Unavailable("EPISODE_CONTRACT_FACTORY is not set", nil)
+	}
+
+	etherscanClient, err := etherscan.NewEtherscanClient(uc.etherscan)
+	if err != nil {
+		return nil, apperr.Unavailable("failed to create Etherscan client", err)
+	}
+	etherscanClient = etherscanClient.WithContext(ctx)
+
+	isEpisode, err := contract.NewFactory(etherscanClient, factoryAddress).IsEpisode(address)
+	if err != nil {
+		return nil, apperr.Unavailable("failed to check episode", err)
+	}
+	if !isEpisode {
+		return nil, apperr.New(apperr.ErrNotFound, "episode "+address+" not found", episode.ErrNotEpisode)
+	}
+
+	return uc.auditEpisode(ctx, etherscanClient, address)
+}
+
+// auditEpisode audits an episode known to be created by the factory
+func (uc *UseCase) auditEpisode(ctx context.Context, client *etherscan.EtherscanClient, address string) (response *AuditResponse, err error) {
+	ctx, span := tracing.Start(ctx, "audit.auditEpisode", attribute.String("episode.address", address))
+	defer func() { tracing.End(span, err) }()
+
+	if uc.indexer != nil {
+		latest, err := client.GetBlockNumber()
+		if err != nil {
+			return nil, apperr.Unavailable("failed to get latest block", err)
+		}
+		if err := uc.indexer.IndexEpisode(ctx, client, address, latest); err != nil {
+			return nil, err
+		}
+	}
+
+	snapshot, err := readSnapshot(client, address)
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := uc.store.FindByEpisode(ctx, address)
+	if err != nil {
+		return nil, errors.New("failed to get episode events: " + err.Error())
+	}
+
+	report := domainaudit.Audit(address, *snapshot, events)
+	uc.alert(ctx, report)
+
+	response = &AuditResponse{
+		Episode:      report.Episode,
+		AuditedAt:    report.AuditedAt.Format(time.RFC3339),
+		State:        string(snapshot.State),
+		TotalPremium: snapshot.TotalPremium.String(),
+		Balance:      snapshot.Balance.String(),
+		Events:       report.Events,
+		Passed:       report.Passed(),
+		Findings:     make([]FindingDTO, 0, len(report.Findings)),
+	}
+	for _, f := range report.Findings {
+		response.Findings = append(response.Findings, FindingDTO{
+			Invariant:       f.Rule.Invariant(),
+			Rule:            string(f.Rule),
+			Message:         f.Message,
+			TransactionHash: f.TransactionHash,
+		})
+	}
+
+	uc.mu.Lock()
+	uc.reports[address] = response
+	uc.mu.Unlock()
+
+	span.SetAttributes(attribute.Int("result.count", len(response.Findings)))
+	return response, nil
+}
+
+// alert delivers the findings of report that were not alerted before.
+// Findings no longer reported are forgotten, so the alerted keys stay bounded by the open violations.
+func (uc *UseCase) alert(ctx context.Context, report *domainaudit.Report) {
+	uc.mu.Lock()
+	previous := uc.alerted[report.Episode]
+	current := make(map[string]bool, len(report.Findings))
+	var fresh []domainaudit.Finding
+	for _, f := range report.Findings {
+		if previous[f.Key()] {
+			current[f.Key()] = true
+		} else {
+			fresh = append(fresh, f)
+		}
+	}
+	if len(current) > 0 {
+		uc.alerted[report.Episode] = current
+	} else {
+		delete(uc.alerted, report.Episode)
+	}
+	uc.mu.Unlock()
+
+	if uc.alerter == nil || len(fresh) == 0 {
+		return
+	}
+
+	if err := uc.alerter.Alert(report, fresh); err != nil {
+		slog.ErrorContext(ctx, "failed to deliver audit alert", "episode", report.Episode, "error", err)
+		return
+	}
+
+	uc.mu.Lock()
+	keys := uc.alerted[report.Episode]
+	if keys == nil {
+		keys = make(map[string]bool, len(fresh))
+		uc.alerted[report.Episode] = keys
+	}
+	for _, f := range fresh {
+		keys[f.Key()] = true
+	}
+	uc.mu.Unlock()
+}
+
+// AuditAll audits every episode created by the factory
+func (uc *UseCase) AuditAll(ctx context.Context) (err error) {
+	ctx, span := tracing.Start(ctx, "audit.AuditAll")
+	defer func() { tracing.End(span, err) }()
+
+	factoryAddress := uc.contracts.EpisodeFactory
+	if factoryAddress == "" {
+		return apperr.Unavailable("EPISODE_CONTRACT_FACTORY is not set", nil)
+	}
+
+	etherscanClient, err := etherscan.NewEtherscanClient(uc.etherscan)
+	if err != nil {
+		return apperr.Unavailable("failed to create Etherscan client", err)
+	}
+	etherscanClient = etherscanClient.WithContext(ctx)
+
+	addresses, err := contract.NewFactory(etherscanClient, factoryAddress).AllEpisodes()
+	if err != nil {
+		return apperr.Unavailable("failed to get episodes from factory", err)
+	}
+
+	for _, address := range addresses {
+		if _, err := uc.auditEpisode(ctx, etherscanClient, address); err != nil {
+			slog.ErrorContext(ctx, "failed to audit episode", "episode", address, "error", err)
+		}
+	}
+	return nil
+}
+
+// Run audits all episodes immediately and then every interval until ctx is cancelled
+func (uc *UseCase) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := uc.AuditAll(ctx); err != nil && ctx.Err() == nil {
+			slog.ErrorContext(ctx, "invariant audit failed", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// readSnapshot reads the on-chain state of an episode
+func readSnapshot(client *etherscan.EtherscanClient, address string) (*domainaudit.Snapshot, error) {
+	episodeContract := contract.NewEpisode(client, address)
+
+	stateIndex, err := episodeContract.State()
+	if err != nil {
+		return nil, apperr.Unavailable("failed to get episode state", err)
+	}
+	state, ok := episode.StateFromIndex(stateIndex)
+	if !ok {
+		return nil, errors.New("unknown episode state " + strconv.Itoa(int(stateIndex)) + " for " + address)
+	}
+
+	snapshot := &domainaudit.Snapshot{State: state}
+
+	if snapshot.PremiumAmount, err = episodeContract.PremiumAmount(); err != nil {
+		return nil, apperr.Unavailable("failed to get episode premium amount", err)
+	}
+	if snapshot.PayoutAmount, err = episodeContract.PayoutAmount(); err != nil {
+		return nil, apperr.Unavailable("failed to get episode payout amount", err)
+	}
+	if snapshot.TotalPremium, err = episodeContract.TotalPremium(); err != nil {
+		return nil, apperr.Unavailable("failed to get episode total premium", err)
+	}
+	if snapshot.TotalPayout, err = episodeContract.TotalPayout(); err != nil {
+		return nil, apperr.Unavailable("failed to get episode total payout", err)
+	}
+	if snapshot.Surplus, err = episodeContract.Surplus(); err != nil {
+		return nil, apperr.Unavailable("failed to get episode surplus", err)
+	}
+	if snapshot.EventOccurred, err = episodeContract.EventOccurred(); err != nil {
+		return nil, apperr.Unavailable("failed to get episode outcome", err)
+	}
+	if snapshot.Balance, err = client.GetBalance(address); err != nil {
+		return nil, apperr.Unavailable("failed to get episode balance", err)
+	}
+
+	return snapshot, nil
+}
@@ -0,0 +1,110 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	domainaudit "eventsure-server/domain/audit"
+	"eventsure-server/domain/chain"
+	"eventsure-server/infrastructure/config"
+)
+
+// recordingAlerter records the findings it is asked to alert, failing while err is set
+type recordingAlerter struct {
+	alerted [][]domainaudit.Finding
+	err     error
+}
+
+func (a *recordingAlerter) Alert(_ *domainaudit.Report, findings []domainaudit.Finding) error {
+	if a.err != nil {
+		return a.err
+	}
+	a.alerted = append(a.alerted, findings)
+	return nil
+}
+
+const episodeAddress = "0x1234567890123456789012345678901234567890"
+
+func report(findings ...domainaudit.Finding) *domainaudit.Report {
+	return &domainaudit.Report{Episode: episodeAddress, Findings: findings}
+}
+
+func TestAlertOncePerOpenViolation(t *testing.T) {
+	balance := domainaudit.Finding{Rule: domainaudit.RuleBalanceReconciles, Message: "balance is off"}
+	surplus := domainaudit.Finding{Rule: domainaudit.RuleProRata, Message: "share is off", TransactionHash: "0xabc"}
+
+	alerter := &recordingAlerter{}
+	uc := NewUseCase(nil, nil, alerter, config.Etherscan{}, config.Contracts{})
+	ctx := context.Background()
+
+	steps := []struct {
+		name      string
+		report    *domainaudit.Report
+		wantAlert []domainaudit.Finding
+		wantKeys  int
+	}{
+		{"first violation is alerted", report(balance), []domainaudit.Finding{balance}, 1},
+		{"same violation is not alerted again", report(balance), nil, 1},
+		{"only the new violation is alerted", report(balance, surplus), []domainaudit.Finding{surplus}, 2},
+		{"resolved violations are forgotten", report(surplus), nil, 1},
+		{"passing audit forgets the episode", report(), nil, 0},
+		{"violation coming back is alerted again", report(balance), []domainaudit.Finding{balance}, 1},
+	}
+	for _, step := range steps {
+		before := len(alerter.alerted)
+		uc.alert(ctx, step.report)
+
+		var got []domainaudit.Finding
+		if len(alerter.alerted) > before {
+			got = alerter.alerted[len(alerter.alerted)-1]
+		}
+		if len(got) != len(step.wantAlert) {
+			t.Fatalf("%s: alerted %v, want %v", step.name, got, step.wantAlert)
+		}
+		for i := range got {
+			if got[i].Key() != step.wantAlert[i].Key() {
+				t.Errorf("%s: alerted %v, want %v", step.name, got, step.wantAlert)
+			}
+		}
+		if n := len(uc.alerted[episodeAddress]); n != step.wantKeys {
+			t.Errorf("%s: %d alerted keys kept, want %d", step.name, n, step.wantKeys)
+		}
+	}
+}
+
+func TestAlertRetriedAfterDeliveryFailure(t *testing.T) {
+	balance := domainaudit.Finding{Rule: domainaudit.RuleBalanceReconciles, Message: "balance is off"}
+
+	alerter := &recordingAlerter{err: errors.New("webhook is down")}
+	uc := NewUseCase(nil, nil, alerter, config.Etherscan{}, config.Contracts{})
+	ctx := context.Background()
+
+	uc.alert(ctx, report(balance))
+	if len(uc.alerted) != 0 {
+		t.Fatalf("undelivered finding was marked as alerted")
+	}
+
+	alerter.err = nil
+	uc.alert(ctx, report(balance))
+	if len(alerter.alerted) != 1 {
+		t.Fatalf("finding was not alerted once delivery recovered")
+	}
+}
+
+func TestLatestAuditBeforeFirstAudit(t *testing.T) {
+	uc := NewUseCase(nil, nil, nil, config.Etherscan{}, config.Contracts{})
+	address, err := chain.ParseAddress(episodeAddress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := uc.LatestAudit(context.Background(), address); err == nil {
+		t.Fatal("LatestAudit returned a report before any audit")
+	}
+
+	uc.reports[episodeAddress] = &AuditResponse{Episode: episodeAddress, Passed: true}
+	got, err := uc.LatestAudit(context.Background(), address)
+	if err != nil || !got.Passed {
+		t.Fatalf("LatestAudit = %v, %v; want the stored report", got, err)
+	}
+}
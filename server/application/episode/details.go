@@ -0,0 +1,149 @@
+package episode
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"time"
+
+	"eventsure-server/application/apperr"
+	"eventsure-server/domain/chain"
+	eventsureepisode "eventsure-server/domain/episode"
+	"eventsure-server/domain/event"
+	"eventsure-server/domain/membership"
+	"eventsure-server/infrastructure/tracing"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// GetUserEpisodeDetails lists user episodes of a user, of an episode, or of both,
+// each with the stored summary of its episode. At least one of user and episode is required.
+func (uc *UseCase) GetUserEpisodeDetails(ctx context.Context, user, episode chain.Address, progress membership.Progress) (response *GetUserEpisodeDetailsResponse, err error) {
+	ctx, span := tracing.Start(ctx, "episode.GetUserEpisodeDetails", attribute.String("user.address", user.String()), attribute.String("episode.address", episode.String()))
+	defer func() { tracing.End(span, err) }()
+
+	if uc.userEpisodeRepo == nil {
+		return nil, apperr.Unavailable("user episode repository is not initialized", nil)
+	}
+
+	var userEpisodes []*membership.UserEpisode
+	switch {
+	case !user.IsZero() && !episode.IsZero():
+		var userEpisode *membership.UserEpisode
+		userEpisode, err = uc.userEpisodeRepo.FindByUserAndEpisode(ctx, user.String(), episode.String())
+		if userEpisode != nil {
+			userEpisodes = []*membership.UserEpisode{userEpisode}
+		}
+	case !user.IsZero():
+		userEpisodes, err = uc.userEpisodeRepo.FindByUser(ctx, user.String())
+	case !episode.IsZero():
+		userEpisodes, err = uc.userEpisodeRepo.FindByEpisode(ctx, episode.String())
+	default:
+		return nil, apperr.Validation("user or episode is required")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := make(map[string]*EpisodeSummaryDTO)
+	details := make([]UserEpisodeDetailDTO, 0, len(userEpisodes))
+	for _, u := range filterByProgress(userEpisodes, progress) {
+		summary, ok := summaries[u.Episode()]
+		if !ok && uc.summaryRepo != nil {
+			s, err := uc.summaryRepo.FindByAddress(u.Episode())
+			if err != nil {
+				return nil, errors.New("failed to get episode summary: " + err.Error())
+			}
+			if s != nil {
+				dto := uc.toEpisodeSummaryDTO(s)
+				summary = &dto
+			}
+			summaries[u.Episode()] = summary
+		}
+
+		dto := toUserEpisodeDTO(u)
+		details = append(details, UserEpisodeDetailDTO{
+			ID:        dto.ID,
+			User:      dto.User,
+			Episode:   dto.Episode,
+			Progress:  dto.Progress,
+			CreatedAt: dto.CreatedAt,
+			Summary:   summary,
+		})
+	}
+
+	span.SetAttributes(attribute.Int("result.count", len(details)))
+	return &GetUserEpisodeDetailsResponse{
+		UserEpisodes: details,
+	}, nil
+}
+
+// GetIndexedEpisodeEvents gets the decoded events of an episode from the event store, in chain order.
+// Events appear once the indexer has reached them.
+func (uc *UseCase) GetIndexedEpisodeEvents(ctx context.Context, episodeAddress chain.Address) (response *GetIndexedEpisodeEventsResponse, err error) {
+	ctx, span := tracing.Start(ctx, "episode.GetIndexedEpisodeEvents", attribute.String("episode.address", episodeAddress.String()))
+	defer func() { tracing.End(span, err) }()
+
+	if uc.eventStore == nil {
+		return nil, apperr.Unavailable("event store is not initialized", nil)
+	}
+	if episodeAddress.IsZero() {
+		return nil, apperr.Validation("episode address is required")
+	}
+
+	if err := uc.requireEpisode(ctx, episodeAddress); err != nil {
+		if errors.Is(err, eventsureepisode.ErrNotEpisode) {
+			return nil, apperr.New(apperr.ErrNotFound, "episode "+episodeAddress.String()+" not found", err)
+		}
+		return nil, err
+	}
+
+	events, err := uc.eventStore.FindByEpisode(ctx, episodeAddress.String())
+	if err != nil {
+		return nil, errors.New("failed to get episode events: " + err.Error())
+	}
+
+	dtos := make([]IndexedEventDTO, len(events))
+	for i, e := range events {
+		dtos[i] = toIndexedEventDTO(e)
+	}
+	span.SetAttributes(attribute.Int("result.count", len(dtos)))
+	return &GetIndexedEpisodeEventsResponse{
+		Episode: episodeAddress.String(),
+		Events:  dtos,
+	}, nil
+}
+
+// toIndexedEventDTO converts a decoded event to its DTO, keeping only the fields the event carries
+func toIndexedEventDTO(e *event.Event) IndexedEventDTO {
+	dto := IndexedEventDTO{
+		Sequence:        e.Sequence,
+		Event:           string(e.Name),
+		BlockNumber:     e.BlockNumber,
+		LogIndex:        e.LogIndex,
+		TransactionHash: e.TransactionHash,
+		Timestamp:       e.Timestamp.UTC().Format(time.RFC3339),
+	}
+
+	switch e.Name {
+	case event.NameMemberJoined, event.NamePayoutClaimed, event.NameSurplusClaimed:
+		dto.Member = e.Member
+		dto.Amount = formatAmount(e.Amount)
+	case event.NameEpisodeResolved:
+		occurred := e.EventOccurred
+		dto.EventOccurred = &occurred
+		dto.FinalArrivalTime = e.FinalArrivalTime
+	case event.NameEpisodeSettled:
+		dto.TotalPayout = formatAmount(e.TotalPayout)
+		dto.Surplus = formatAmount(e.Surplus)
+	}
+	return dto
+}
+
+// formatAmount returns the decimal string of v, or "" when it is not set
+func formatAmount(v *big.Int) string {
+	if v == nil {
+		return ""
+	}
+	return v.String()
+}
@@ -1,9 +1,17 @@
 package episode
 
-// CreateUserEpisodeRequest represents request for creating user_episode
+import (
+	"time"
+
+	"eventsure-server/domain/chain"
+	eventsureepisode "eventsure-server/domain/episode"
+)
+
+// CreateUserEpisodeRequest represents request for creating user_episode.
+// Addresses are validated (EIP-55 checksum when mixed-case) while decoding.
 type CreateUserEpisodeRequest struct {
-	User    string `json:"user"`
-	Episode string `json:"episode"`
+	User    chain.Address `json:"user"`
+	Episode chain.Address `json:"episode"`
 }
 
 // CreateUserEpisodeResponse represents response for creating user_episode
@@ -15,6 +23,11 @@ type CreateUserEpisodeResponse struct {
 	CreatedAt string  `json:"created_at"`
 }
 
+// UpdateUserEpisodeRequest represents request for correcting a user_episode's progress
+type UpdateUserEpisodeRequest struct {
+	Progress string `json:"progress"`
+}
+
 // GetUserEpisodesResponse represents response for getting user episodes
 type GetUserEpisodesResponse struct {
 	Episodes []UserEpisodeDTO `json:"episodes"`
@@ -50,3 +63,184 @@ type EpisodeEventDTO struct {
 type GetEpisodeEventsResponse struct {
 	Events []EpisodeEventDTO `json:"events"`
 }
+
+// ListEpisodesRequest represents filter, sort and pagination criteria for listing episodes
+type ListEpisodesRequest struct {
+	State         eventsureepisode.State
+	Category      eventsureepisode.Category
+	FlightName    string
+	DepartureFrom *time.Time
+	DepartureTo   *time.Time
+	// DepartureBefore is an exclusive upper bound, set for a date-only departureTo
+	DepartureBefore *time.Time
+	SortBy          eventsureepisode.SortField
+	Descending      bool
+	Cursor          string
+	Limit           int
+}
+
+// EpisodeSummaryDTO represents an episode summary.
+// Amounts are in wei and encoded as decimal strings.
+type EpisodeSummaryDTO struct {
+	Address              string `json:"address"`
+	State                string `json:"state"`
+	Status               string `json:"status"`
+	Category             string `json:"category"`
+	FlightName           string `json:"flightName"`
+	DepartureTime        string `json:"departureTime"`
+	EstimatedArrivalTime string `json:"estimatedArrivalTime"`
+	PremiumAmount        string `json:"premiumAmount"`
+	PayoutAmount         string `json:"payoutAmount"`
+	MemberCount          int    `json:"memberCount"`
+	TotalPremium         string `json:"totalPremium"`
+	TVL                  string `json:"tvl"`
+	SignupStart          string `json:"signupStart"`
+	SignupEnd            string `json:"signupEnd"`
+	CreationBlock        int64  `json:"creationBlock"`
+	// UpdatedAt is when the on-chain state was last refreshed; Stale is set when that is overdue
+	UpdatedAt string `json:"updatedAt"`
+	Stale     bool   `json:"stale"`
+}
+
+// ListEpisodesResponse represents a page of episode summaries
+type ListEpisodesResponse struct {
+	Episodes   []EpisodeSummaryDTO `json:"episodes"`
+	NextCursor string              `json:"nextCursor,omitempty"`
+	// Stale is set when any summary of the page is stale
+	Stale bool `json:"stale"`
+}
+
+// PortfolioEpisodeDTO represents a user's position in a single episode.
+// Amounts are in wei and encoded as decimal strings.
+type PortfolioEpisodeDTO struct {
+	UserEpisodeID    int64  `json:"userEpisodeId"`
+	Episode          string `json:"episode"`
+	State            string `json:"state"`
+	FlightName       string `json:"flightName"`
+	DepartureTime    string `json:"departureTime"`
+	Joined           bool   `json:"joined"`
+	PremiumPaid      string `json:"premiumPaid"`
+	EventOccurred    *bool  `json:"eventOccurred,omitempty"`
+	PayoutClaimed    bool   `json:"payoutClaimed"`
+	SurplusWithdrawn bool   `json:"surplusWithdrawn"`
+	ClaimablePayout  string `json:"claimablePayout"`
+	ClaimableSurplus string `json:"claimableSurplus"`
+}
+
+// PortfolioTotalsDTO represents lifetime totals across a user's episodes
+type PortfolioTotalsDTO struct {
+	EpisodeCount     int    `json:"episodeCount"`
+	PremiumPaid      string `json:"premiumPaid"`
+	PayoutReceived   string `json:"payoutReceived"`
+	SurplusReceived  string `json:"surplusReceived"`
+	ClaimablePayout  string `json:"claimablePayout"`
+	ClaimableSurplus string `json:"claimableSurplus"`
+}
+
+// GetUserPortfolioResponse represents response for getting a user's portfolio
+type GetUserPortfolioResponse struct {
+	User     string                `json:"user"`
+	Episodes []PortfolioEpisodeDTO `json:"episodes"`
+	Totals   PortfolioTotalsDTO    `json:"totals"`
+}
+
+// UserEpisodeDetailDTO represents a user episode together with its episode summary (v2).
+// Summary is null until the episode summary has been synced.
+type UserEpisodeDetailDTO struct {
+	ID        int64              `json:"id"`
+	User      string             `json:"user"`
+	Episode   string             `json:"episode"`
+	Progress  *string            `json:"progress,omitempty"`
+	CreatedAt string             `json:"createdAt"`
+	Summary   *EpisodeSummaryDTO `json:"summary"`
+}
+
+// GetUserEpisodeDetailsResponse represents response for listing user episodes with their episodes (v2)
+type GetUserEpisodeDetailsResponse struct {
+	UserEpisodes []UserEpisodeDetailDTO `json:"userEpisodes"`
+}
+
+// IndexedEventDTO represents a decoded episode event from the event store (v2).
+// Only the fields carried by the event are set; amounts are in wei and encoded as decimal strings.
+type IndexedEventDTO struct {
+	Sequence         int64  `json:"sequence"`
+	Event            string `json:"event"`
+	BlockNumber      int64  `json:"blockNumber"`
+	LogIndex         int64  `json:"logIndex"`
+	TransactionHash  string `json:"transactionHash"`
+	Timestamp        string `json:"timestamp"`
+	Member           string `json:"member,omitempty"`
+	Amount           string `json:"amount,omitempty"`
+	EventOccurred    *bool  `json:"eventOccurred,omitempty"`
+	FinalArrivalTime uint64 `json:"finalArrivalTime,omitempty"`
+	TotalPayout      string `json:"totalPayout,omitempty"`
+	Surplus          string `json:"surplus,omitempty"`
+}
+
+// EpisodeMemberDTO represents a member of an episode as recorded by its indexed events.
+// Amounts are in wei and encoded as decimal strings.
+type EpisodeMemberDTO struct {
+	Member          string `json:"member"`
+	PremiumPaid     string `json:"premiumPaid"`
+	PayoutReceived  string `json:"payoutReceived"`
+	SurplusReceived string `json:"surplusReceived"`
+	JoinedAt        string `json:"joinedAt"`
+	JoinTransaction string `json:"joinTransaction"`
+}
+
+// GetEpisodeMembersResponse represents response for listing the members of an episode
+type GetEpisodeMembersResponse struct {
+	Episode      string             `json:"episode"`
+	TotalPremium string             `json:"totalPremium"`
+	Members      []EpisodeMemberDTO `json:"members"`
+}
+
+// LedgerEntryDTO represents a money movement of an episode with the running balance after it.
+// Amounts are decimal strings in wei and in ETH with 18 decimals; premiums are positive,
+// payouts and surplus withdrawals negative.
+type LedgerEntryDTO struct {
+	Sequence        int64  `json:"sequence"`
+	Timestamp       string `json:"timestamp"`
+	BlockNumber     int64  `json:"blockNumber"`
+	LogIndex        int64  `json:"logIndex"`
+	TransactionHash string `json:"transactionHash"`
+	Event           string `json:"event"`
+	Type            string `json:"type"`
+	Member          string `json:"member"`
+	AmountWei       string `json:"amountWei"`
+	AmountETH       string `json:"amountEth"`
+	BalanceWei      string `json:"balanceWei"`
+	BalanceETH      string `json:"balanceEth"`
+}
+
+// LedgerReconciliationDTO represents the totals of a ledger reconciled with the on-chain balance.
+// Difference is the on-chain balance minus the ledger balance.
+type LedgerReconciliationDTO struct {
+	PremiumInWei      string `json:"premiumInWei"`
+	PremiumInETH      string `json:"premiumInEth"`
+	PayoutOutWei      string `json:"payoutOutWei"`
+	PayoutOutETH      string `json:"payoutOutEth"`
+	SurplusOutWei     string `json:"surplusOutWei"`
+	SurplusOutETH     string `json:"surplusOutEth"`
+	LedgerBalanceWei  string `json:"ledgerBalanceWei"`
+	LedgerBalanceETH  string `json:"ledgerBalanceEth"`
+	OnChainBalanceWei string `json:"onChainBalanceWei"`
+	OnChainBalanceETH string `json:"onChainBalanceEth"`
+	DifferenceWei     string `json:"differenceWei"`
+	DifferenceETH     string `json:"differenceEth"`
+	Reconciled        bool   `json:"reconciled"`
+}
+
+// GetEpisodeLedgerResponse represents response for the ledger of an episode
+type GetEpisodeLedgerResponse struct {
+	Episode        string                  `json:"episode"`
+	GeneratedAt    string                  `json:"generatedAt"`
+	Entries        []LedgerEntryDTO        `json:"entries"`
+	Reconciliation LedgerReconciliationDTO `json:"reconciliation"`
+}
+
+// GetIndexedEpisodeEventsResponse represents response for getting the decoded events of an episode (v2)
+type GetIndexedEpisodeEventsResponse struct {
+	Episode string            `json:"episode"`
+	Events  []IndexedEventDTO `json:"events"`
+}
@@ -0,0 +1,133 @@
+package episode
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"eventsure-server/application/apperr"
+	"eventsure-server/domain/chain"
+	eventsureepisode "eventsure-server/domain/episode"
+	"eventsure-server/domain/ledger"
+	"eventsure-server/infrastructure/etherscan"
+	"eventsure-server/infrastructure/tracing"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// GetEpisodeLedger builds the ledger of an episode from its indexed MemberJoined, PayoutClaimed
+// and SurplusClaimed events, with the running balance after each entry, and reconciles it with
+// the balance of the contract. Entries appear once the indexer has reached them.
+func (uc *UseCase) GetEpisodeLedger(ctx context.Context, episodeAddress chain.Address) (response *GetEpisodeLedgerResponse, err error) {
+	ctx, span := tracing.Start(ctx, "episode.GetEpisodeLedger", attribute.String("episode.address", episodeAddress.String()))
+	defer func() { tracing.End(span, err) }()
+
+	if uc.eventStore == nil {
+		return nil, apperr.Unavailable("event store is not initialized", nil)
+	}
+	if episodeAddress.IsZero() {
+		return nil, apperr.Validation("episode address is required")
+	}
+
+	if err := uc.requireEpisode(ctx, episodeAddress); err != nil {
+		if errors.Is(err, eventsureepisode.ErrNotEpisode) {
+			return nil, apperr.New(apperr.ErrNotFound, "episode "+episodeAddress.String()+" not found", err)
+		}
+		return nil, err
+	}
+
+	// The balance is read before the events, so events stored meanwhile show as a
+	// difference for this request only instead of being missed by the balance
+	etherscanClient, err := etherscan.NewEtherscanClient(uc.etherscan)
+	if err != nil {
+		return nil, apperr.Unavailable("failed to create Etherscan client", err)
+	}
+	balance, err := etherscanClient.WithContext(ctx).GetBalance(episodeAddress.String())
+	if err != nil {
+		return nil, apperr.Unavailable("failed to get episode balance", err)
+	}
+
+	events, err := uc.eventStore.FindByEpisode(ctx, episodeAddress.String())
+	if err != nil {
+		return nil, errors.New("failed to get episode events: " + err.Error())
+	}
+
+	l := ledger.Build(episodeAddress.String(), events)
+	reconciliation := l.Reconcile(balance)
+
+	response = &GetEpisodeLedgerResponse{
+		Episode:     episodeAddress.String(),
+		GeneratedAt: time.Now().UTC().Format(time.RFC3339),
+		Entries:     make([]LedgerEntryDTO, len(l.Entries)),
+		Reconciliation: LedgerReconciliationDTO{
+			PremiumInWei:      l.PremiumIn.String(),
+			PremiumInETH:      chain.FormatEther(l.PremiumIn),
+			PayoutOutWei:      l.PayoutOut.String(),
+			PayoutOutETH:      chain.FormatEther(l.PayoutOut),
+			SurplusOutWei:     l.SurplusOut.String(),
+			SurplusOutETH:     chain.FormatEther(l.SurplusOut),
+			LedgerBalanceWei:  reconciliation.LedgerBalance.String(),
+			LedgerBalanceETH:  chain.FormatEther(reconciliation.LedgerBalance),
+			OnChainBalanceWei: reconciliation.OnChainBalance.String(),
+			OnChainBalanceETH: chain.FormatEther(reconciliation.OnChainBalance),
+			DifferenceWei:     reconciliation.Difference.String(),
+			DifferenceETH:     chain.FormatEther(reconciliation.Difference),
+			Reconciled:        reconciliation.Reconciled(),
+		},
+	}
+	for i, entry := range l.Entries {
+		response.Entries[i] = toLedgerEntryDTO(entry)
+	}
+
+	span.SetAttributes(attribute.Int("result.count", len(response.Entries)), attribute.Bool("ledger.reconciled", reconciliation.Reconciled()))
+	return response, nil
+}
+
+// toLedgerEntryDTO converts a ledger entry to its DTO
+func toLedgerEntryDTO(entry ledger.Entry) LedgerEntryDTO {
+	e := entry.Event
+	return LedgerEntryDTO{
+		Sequence:        e.Sequence,
+		Timestamp:       e.Timestamp.UTC().Format(time.RFC3339),
+		BlockNumber:     e.BlockNumber,
+		LogIndex:        e.LogIndex,
+		TransactionHash: e.TransactionHash,
+		Event:           string(e.Name),
+		Type:            string(entry.Kind),
+		Member:          e.Member,
+		AmountWei:       entry.Amount.String(),
+		AmountETH:       chain.FormatEther(entry.Amount),
+		BalanceWei:      entry.Balance.String(),
+		BalanceETH:      chain.FormatEther(entry.Balance),
+	}
+}
+
+// LedgerCSVHeader is the header of the CSV form of a ledger
+var LedgerCSVHeader = []string{
+	"sequence", "timestamp", "block_number", "log_index", "transaction_hash", "event", "type", "member",
+	"amount_wei", "amount_eth", "balance_wei", "balance_eth",
+}
+
+// ledgerReconciliationType is the type of the reconciliation line of the CSV form
+const ledgerReconciliationType = "reconciliation"
+
+// CSVRecords returns the rows of the CSV form of the ledger without the header: the entries,
+// then a reconciliation line whose amount is the difference to the on-chain balance and whose
+// balance is the on-chain balance, so the running balance continues into it
+func (r *GetEpisodeLedgerResponse) CSVRecords() [][]string {
+	records := make([][]string, 0, len(r.Entries)+1)
+	for _, e := range r.Entries {
+		records = append(records, []string{
+			strconv.FormatInt(e.Sequence, 10), e.Timestamp, strconv.FormatInt(e.BlockNumber, 10), strconv.FormatInt(e.LogIndex, 10),
+			e.TransactionHash, e.Event, e.Type, e.Member, e.AmountWei, e.AmountETH, e.BalanceWei, e.BalanceETH,
+		})
+	}
+
+	c := r.Reconciliation
+	records = append(records, []string{
+		"", r.GeneratedAt, "", "", "", "", ledgerReconciliationType, "",
+		c.DifferenceWei, c.DifferenceETH, c.OnChainBalanceWei, c.OnChainBalanceETH,
+	})
+	return records
+}
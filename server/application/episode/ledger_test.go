@@ -0,0 +1,93 @@
+package episode
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+
+	"eventsure-server/domain/event"
+	"eventsure-server/domain/ledger"
+)
+
+func TestLedgerCSVHeader(t *testing.T) {
+	want := []string{
+		"sequence", "timestamp", "block_number", "log_index", "transaction_hash", "event", "type", "member",
+		"amount_wei", "amount_eth", "balance_wei", "balance_eth",
+	}
+	if !reflect.DeepEqual(LedgerCSVHeader, want) {
+		t.Errorf("LedgerCSVHeader = %v, want %v", LedgerCSVHeader, want)
+	}
+}
+
+func TestLedgerCSVRecords(t *testing.T) {
+	const (
+		episodeAddress = "0x1111111111111111111111111111111111111111"
+		member         = "0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+		generatedAt    = "2026-10-19T00:00:00Z"
+	)
+	joined := &event.Event{
+		Sequence: 7, Episode: episodeAddress, Name: event.NameMemberJoined,
+		BlockNumber: 100, LogIndex: 3, TransactionHash: "0xjoin",
+		Timestamp: time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC),
+		Member:    member, Amount: big.NewInt(10000000000000000),
+	}
+	claimed := &event.Event{
+		Sequence: 9, Episode: episodeAddress, Name: event.NamePayoutClaimed,
+		BlockNumber: 120, LogIndex: 0, TransactionHash: "0xclaim",
+		Timestamp: time.Date(2026, 10, 2, 12, 0, 0, 0, time.UTC),
+		Member:    member, Amount: big.NewInt(4000000000000000),
+	}
+	l := ledger.Build(episodeAddress, []*event.Event{joined, claimed})
+	reconciliation := l.Reconcile(big.NewInt(5000000000000000))
+
+	response := &GetEpisodeLedgerResponse{
+		Episode:     episodeAddress,
+		GeneratedAt: generatedAt,
+		Reconciliation: LedgerReconciliationDTO{
+			OnChainBalanceWei: reconciliation.OnChainBalance.String(),
+			OnChainBalanceETH: "0.005000000000000000",
+			DifferenceWei:     reconciliation.Difference.String(),
+			DifferenceETH:     "-0.001000000000000000",
+		},
+	}
+	for _, entry := range l.Entries {
+		response.Entries = append(response.Entries, toLedgerEntryDTO(entry))
+	}
+
+	want := []map[string]string{
+		{
+			"sequence": "7", "timestamp": "2026-10-01T12:00:00Z", "block_number": "100", "log_index": "3",
+			"transaction_hash": "0xjoin", "event": "MemberJoined", "type": "premium", "member": member,
+			"amount_wei": "10000000000000000", "amount_eth": "0.010000000000000000",
+			"balance_wei": "10000000000000000", "balance_eth": "0.010000000000000000",
+		},
+		{
+			"sequence": "9", "timestamp": "2026-10-02T12:00:00Z", "block_number": "120", "log_index": "0",
+			"transaction_hash": "0xclaim", "event": "PayoutClaimed", "type": "payout", "member": member,
+			"amount_wei": "-4000000000000000", "amount_eth": "-0.004000000000000000",
+			"balance_wei": "6000000000000000", "balance_eth": "0.006000000000000000",
+		},
+		{
+			"sequence": "", "timestamp": generatedAt, "block_number": "", "log_index": "",
+			"transaction_hash": "", "event": "", "type": "reconciliation", "member": "",
+			"amount_wei": "-1000000000000000", "amount_eth": "-0.001000000000000000",
+			"balance_wei": "5000000000000000", "balance_eth": "0.005000000000000000",
+		},
+	}
+
+	records := response.CSVRecords()
+	if len(records) != len(want) {
+		t.Fatalf("%d records, want %d", len(records), len(want))
+	}
+	for i, record := range records {
+		if len(record) != len(LedgerCSVHeader) {
+			t.Fatalf("record %d has %d columns, header has %d", i, len(record), len(LedgerCSVHeader))
+		}
+		for j, column := range LedgerCSVHeader {
+			if record[j] != want[i][column] {
+				t.Errorf("record %d %s = %q, want %q", i, column, record[j], want[i][column])
+			}
+		}
+	}
+}
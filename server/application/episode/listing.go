@@ -0,0 +1,375 @@
+package episode
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"eventsure-server/application/apperr"
+	"eventsure-server/domain/chain"
+	eventsureepisode "eventsure-server/domain/episode"
+	"eventsure-server/infrastructure/contract"
+	"eventsure-server/infrastructure/etherscan"
+	"eventsure-server/infrastructure/metrics"
+	"eventsure-server/infrastructure/tracing"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const (
+	// DefaultListLimit is the page size used when no limit is given
+	DefaultListLimit = 20
+	// MaxListLimit is the largest accepted page size
+	MaxListLimit = 100
+)
+
+// ErrInvalidCursor is returned when a pagination cursor cannot be decoded,
+// or was issued for another sort or order
+var ErrInvalidCursor = apperr.Validation("invalid cursor")
+
+// staleSyncIntervals is how many sync intervals a summary may go without a refresh before it is marked stale
+const staleSyncIntervals = 3
+
+// ListEpisodes returns episode summaries matching the request filters, sorted and paginated.
+// Summaries are served as last refreshed by SyncSummaries; those not refreshed recently are marked stale.
+func (uc *UseCase) ListEpisodes(ctx context.Context, req ListEpisodesRequest) (response *ListEpisodesResponse, err error) {
+	_, span := tracing.Start(ctx, "episode.ListEpisodes")
+	defer func() { tracing.End(span, err) }()
+
+	limit := req.Limit
+	if limit <= 0 {
+		limit = DefaultListLimit
+	}
+	if limit > MaxListLimit {
+		limit = MaxListLimit
+	}
+
+	sortBy := req.SortBy
+	if sortBy == "" {
+		sortBy = eventsureepisode.SortByCreationBlock
+	}
+
+	query := eventsureepisode.SummaryQuery{
+		State:           req.State,
+		Category:        req.Category,
+		FlightName:      req.FlightName,
+		DepartureFrom:   req.DepartureFrom,
+		DepartureTo:     req.DepartureTo,
+		DepartureBefore: req.DepartureBefore,
+		SortBy:          sortBy,
+		Descending:      req.Descending,
+		Limit:           limit + 1,
+	}
+
+	if req.Cursor != "" {
+		cursor, err := decodeCursor(req.Cursor, sortBy, req.Descending)
+		if err != nil {
+			return nil, err
+		}
+		query.After = cursor
+	}
+
+	summaries, err := uc.summaryRepo.FindByQuery(query)
+	if err != nil {
+		return nil, err
+	}
+
+	response = &ListEpisodesResponse{
+		Episodes: make([]EpisodeSummaryDTO, 0, limit),
+	}
+
+	if len(summaries) > limit {
+		summaries = summaries[:limit]
+		last := summaries[limit-1]
+		response.NextCursor = encodeCursor(&eventsureepisode.SummaryCursor{
+			SortKey: last.SortKey(sortBy),
+			Address: last.Address(),
+		}, sortBy, req.Descending)
+	}
+
+	for _, s := range summaries {
+		dto := uc.toEpisodeSummaryDTO(s)
+		response.Stale = response.Stale || dto.Stale
+		response.Episodes = append(response.Episodes, dto)
+	}
+
+	span.SetAttributes(attribute.Int("result.count", len(response.Episodes)))
+	return response, nil
+}
+
+// ListEpisodeAddresses returns the address of every episode summary, newest first.
+// It adapts the summaries to the address list of the v1 GET /api/episodes.
+func (uc *UseCase) ListEpisodeAddresses(ctx context.Context) (response *GetAllEpisodesResponse, err error) {
+	_, span := tracing.Start(ctx, "episode.ListEpisodeAddresses")
+	defer func() { tracing.End(span, err) }()
+
+	summaries, err := uc.summaryRepo.FindByQuery(eventsureepisode.SummaryQuery{
+		SortBy:     eventsureepisode.SortByCreationBlock,
+		Descending: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response = &GetAllEpisodesResponse{
+		Episodes: make([]string, 0, len(summaries)),
+	}
+	for _, s := range summaries {
+		response.Episodes = append(response.Episodes, s.Address())
+	}
+
+	span.SetAttributes(attribute.Int("result.count", len(response.Episodes)))
+	return response, nil
+}
+
+// GetEpisode returns the summary of one episode, as last refreshed by SyncSummaries like ListEpisodes
+func (uc *UseCase) GetEpisode(ctx context.Context, episodeAddress chain.Address) (response *EpisodeSummaryDTO, err error) {
+	_, span := tracing.Start(ctx, "episode.GetEpisode", attribute.String("episode.address", episodeAddress.String()))
+	defer func() { tracing.End(span, err) }()
+
+	if episodeAddress.IsZero() {
+		return nil, apperr.Validation("episode address is required")
+	}
+
+	summary, err := uc.summaryRepo.FindByAddress(episodeAddress.String())
+	if err != nil {
+		return nil, err
+	}
+	if summary == nil {
+		return nil, apperr.NotFound("episode " + episodeAddress.String() + " not found")
+	}
+
+	dto := uc.toEpisodeSummaryDTO(summary)
+	return &dto, nil
+}
+
+// SyncSummaries re-reads the state of every factory episode from chain and stores it in the summaries.
+// Terms are immutable, so EpisodeFactory.episodes(i) is only read for newly created episodes.
+// An episode that fails to refresh keeps its previous summary; the failures are returned together
+// after the other episodes are refreshed.
+func (uc *UseCase) SyncSummaries(ctx context.Context) (err error) {
+	ctx, span := tracing.Start(ctx, "episode.SyncSummaries")
+	defer func() { tracing.End(span, err) }()
+
+	uc.summarySyncMu.Lock()
+	defer uc.summarySyncMu.Unlock()
+
+	factoryAddress := uc.contracts.EpisodeFactory
+	if factoryAddress == "" {
+		return apperr.Unavailable("EPISODE_CONTRACT_FACTORY is not set", nil)
+	}
+
+	etherscanClient, err := etherscan.NewEtherscanClient(uc.etherscan)
+	if err != nil {
+		return apperr.Unavailable("failed to create Etherscan client", err)
+	}
+	etherscanClient = etherscanClient.WithContext(ctx)
+
+	factory := contract.NewFactory(etherscanClient, factoryAddress)
+	addresses, err := factory.AllEpisodes()
+	if err != nil {
+		return apperr.Unavailable("failed to get episodes from factory", err)
+	}
+	if len(addresses) == 0 {
+		return nil
+	}
+
+	creationBlocks, err := episodeCreationBlocks(etherscanClient, factoryAddress)
+	if err != nil {
+		return err
+	}
+
+	var failed []error
+	for i, address := range addresses {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err := uc.syncSummary(etherscanClient, factory, i, address, creationBlocks[address]); err != nil {
+			slog.WarnContext(ctx, "failed to sync episode summary", "episode", address, "error", err)
+			failed = append(failed, errors.New(address+": "+err.Error()))
+		}
+	}
+	span.SetAttributes(attribute.Int("episode.count", len(addresses)), attribute.Int("episode.failed", len(failed)))
+
+	if len(failed) > 0 {
+		return apperr.Unavailable("failed to sync "+strconv.Itoa(len(failed))+" of "+strconv.Itoa(len(addresses))+" episode summaries", errors.Join(failed...))
+	}
+	return nil
+}
+
+// syncSummary refreshes the summary of the index-th factory episode
+func (uc *UseCase) syncSummary(etherscanClient *etherscan.EtherscanClient, factory *contract.Factory, index int, address string, creationBlock int64) error {
+	summary, err := uc.summaryRepo.FindByAddress(address)
+	if err != nil {
+		return err
+	}
+
+	metrics.ObserveCacheLookup("episode_terms", summary != nil)
+	if summary == nil {
+		info, err := factory.Episodes(index)
+		if err != nil {
+			return errors.New("failed to get episode info: " + err.Error())
+		}
+		summary = eventsureepisode.NewSummary(
+			address,
+			info.FlightName,
+			info.PremiumAmount,
+			info.PayoutAmount,
+			unixTime(info.DepartureTime),
+			unixTime(info.EstimatedArrivalTime),
+			unixTime(info.SignupStart),
+			unixTime(info.SignupEnd),
+			creationBlock,
+		)
+	} else if summary.CreationBlock() == 0 {
+		summary.SetCreationBlock(creationBlock)
+	}
+
+	episodeContract := contract.NewEpisode(etherscanClient, address)
+
+	stateIndex, err := episodeContract.State()
+	if err != nil {
+		return errors.New("failed to get episode state: " + err.Error())
+	}
+	state, ok := eventsureepisode.StateFromIndex(stateIndex)
+	if !ok {
+		return errors.New("unknown episode state " + strconv.Itoa(int(stateIndex)))
+	}
+
+	totalPremium, err := episodeContract.TotalPremium()
+	if err != nil {
+		return errors.New("failed to get episode total premium: " + err.Error())
+	}
+
+	tvl, err := etherscanClient.GetBalance(address)
+	if err != nil {
+		return errors.New("failed to get episode balance: " + err.Error())
+	}
+
+	summary.UpdateOnChainState(state, totalPremium, tvl)
+	return uc.summaryRepo.Save(summary)
+}
+
+// RunSummarySync syncs the summaries immediately and then every interval until ctx is cancelled
+func (uc *UseCase) RunSummarySync(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := uc.SyncSummaries(ctx); err != nil && ctx.Err() == nil {
+			slog.ErrorContext(ctx, "summary sync failed", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// episodeCreationBlocks maps each episode created by the factory to its creation block,
+// using the factory's contract creation internal transactions
+func episodeCreationBlocks(client *etherscan.EtherscanClient, factoryAddress string) (map[string]int64, error) {
+	response, err := client.GetInternalTransactions(etherscan.GetInternalTransactionsParams{
+		Address: factoryAddress,
+		Sort:    "asc",
+	})
+	if err != nil {
+		return nil, apperr.Unavailable("failed to get internal transactions", err)
+	}
+
+	blocks := make(map[string]int64, len(response.Result))
+	for _, tx := range response.Result {
+		if tx.ContractAddress == "" {
+			continue
+		}
+		block, err := strconv.ParseInt(tx.BlockNumber, 10, 64)
+		if err != nil {
+			continue
+		}
+		blocks[strings.ToLower(tx.ContractAddress)] = block
+	}
+	return blocks, nil
+}
+
+// cursorPayload is the JSON form of an opaque pagination cursor.
+// The sort and order it was issued for are kept, since its key means nothing in another order.
+type cursorPayload struct {
+	Key        string `json:"k"`
+	Address    string `json:"a"`
+	SortBy     string `json:"s"`
+	Descending bool   `json:"d"`
+}
+
+// encodeCursor encodes a cursor for the sort and order into an opaque URL-safe string
+func encodeCursor(cursor *eventsureepisode.SummaryCursor, sortBy eventsureepisode.SortField, descending bool) string {
+	payload, _ := json.Marshal(cursorPayload{
+		Key:        cursor.SortKey.String(),
+		Address:    cursor.Address,
+		SortBy:     string(sortBy),
+		Descending: descending,
+	})
+	return base64.RawURLEncoding.EncodeToString(payload)
+}
+
+// decodeCursor decodes a cursor produced by encodeCursor, rejecting one issued for another sort or order
+func decodeCursor(s string, sortBy eventsureepisode.SortField, descending bool) (*eventsureepisode.SummaryCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var payload cursorPayload
+	if err := json.Unmarshal(raw, &payload); err != nil || payload.Address == "" {
+		return nil, ErrInvalidCursor
+	}
+	if eventsureepisode.SortField(payload.SortBy) != sortBy || payload.Descending != descending {
+		return nil, ErrInvalidCursor
+	}
+
+	key, ok := new(big.Int).SetString(payload.Key, 10)
+	if !ok {
+		return nil, ErrInvalidCursor
+	}
+
+	return &eventsureepisode.SummaryCursor{
+		SortKey: key,
+		Address: payload.Address,
+	}, nil
+}
+
+// toEpisodeSummaryDTO converts a Summary to its DTO, marking it stale when the sync has not refreshed it
+// for staleSyncIntervals intervals
+func (uc *UseCase) toEpisodeSummaryDTO(s *eventsureepisode.Summary) EpisodeSummaryDTO {
+	return EpisodeSummaryDTO{
+		Address:              s.Address(),
+		State:                string(s.State()),
+		Status:               string(s.Status()),
+		Category:             string(s.Category()),
+		FlightName:           s.FlightName(),
+		DepartureTime:        s.DepartureTime().UTC().Format(time.RFC3339),
+		EstimatedArrivalTime: s.EstimatedArrivalTime().UTC().Format(time.RFC3339),
+		PremiumAmount:        s.PremiumAmount().String(),
+		PayoutAmount:         s.PayoutAmount().String(),
+		MemberCount:          s.MemberCount(),
+		TotalPremium:         s.TotalPremium().String(),
+		TVL:                  s.TVL().String(),
+		SignupStart:          s.SignupStart().UTC().Format(time.RFC3339),
+		SignupEnd:            s.SignupEnd().UTC().Format(time.RFC3339),
+		CreationBlock:        s.CreationBlock(),
+		UpdatedAt:            s.UpdatedAt().UTC().Format(time.RFC3339),
+		Stale:                time.Since(s.UpdatedAt()) > staleSyncIntervals*uc.summaries.Interval,
+	}
+}
+
+// unixTime converts a contract uint64 timestamp to time.Time
+func unixTime(timestamp uint64) time.Time {
+	return time.Unix(int64(timestamp), 0).UTC()
+}
@@ -0,0 +1,94 @@
+package episode
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"time"
+
+	"eventsure-server/application/apperr"
+	"eventsure-server/domain/chain"
+	eventsureepisode "eventsure-server/domain/episode"
+	"eventsure-server/domain/event"
+	"eventsure-server/infrastructure/tracing"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// GetEpisodeMembers lists the members of an episode in join order with what each paid and
+// received, replayed from the event store. Members appear once the indexer has reached their join.
+func (uc *UseCase) GetEpisodeMembers(ctx context.Context, episodeAddress chain.Address) (response *GetEpisodeMembersResponse, err error) {
+	ctx, span := tracing.Start(ctx, "episode.GetEpisodeMembers", attribute.String("episode.address", episodeAddress.String()))
+	defer func() { tracing.End(span, err) }()
+
+	if uc.eventStore == nil {
+		return nil, apperr.Unavailable("event store is not initialized", nil)
+	}
+	if episodeAddress.IsZero() {
+		return nil, apperr.Validation("episode address is required")
+	}
+
+	if err := uc.requireEpisode(ctx, episodeAddress); err != nil {
+		if errors.Is(err, eventsureepisode.ErrNotEpisode) {
+			return nil, apperr.New(apperr.ErrNotFound, "episode "+episodeAddress.String()+" not found", err)
+		}
+		return nil, err
+	}
+
+	events, err := uc.eventStore.FindByEpisode(ctx, episodeAddress.String())
+	if err != nil {
+		return nil, errors.New("failed to get episode events: " + err.Error())
+	}
+
+	type member struct {
+		dto                      EpisodeMemberDTO
+		premium, payout, surplus big.Int
+	}
+	var order []string
+	members := make(map[string]*member)
+	var totalPremium big.Int
+
+	for _, e := range events {
+		if e.Member == "" || e.Amount == nil {
+			continue
+		}
+		key := strings.ToLower(e.Member)
+		m, ok := members[key]
+		if !ok {
+			m = &member{dto: EpisodeMemberDTO{Member: key}}
+			members[key] = m
+			order = append(order, key)
+		}
+
+		switch e.Name {
+		case event.NameMemberJoined:
+			m.premium.Add(&m.premium, e.Amount)
+			totalPremium.Add(&totalPremium, e.Amount)
+			if m.dto.JoinTransaction == "" {
+				m.dto.JoinedAt = e.Timestamp.UTC().Format(time.RFC3339)
+				m.dto.JoinTransaction = e.TransactionHash
+			}
+		case event.NamePayoutClaimed:
+			m.payout.Add(&m.payout, e.Amount)
+		case event.NameSurplusClaimed:
+			m.surplus.Add(&m.surplus, e.Amount)
+		}
+	}
+
+	response = &GetEpisodeMembersResponse{
+		Episode:      episodeAddress.String(),
+		TotalPremium: totalPremium.String(),
+		Members:      make([]EpisodeMemberDTO, 0, len(order)),
+	}
+	for _, key := range order {
+		m := members[key]
+		m.dto.PremiumPaid = m.premium.String()
+		m.dto.PayoutReceived = m.payout.String()
+		m.dto.SurplusReceived = m.surplus.String()
+		response.Members = append(response.Members, m.dto)
+	}
+
+	span.SetAttributes(attribute.Int("result.count", len(response.Members)))
+	return response, nil
+}
@@ -0,0 +1,170 @@
+package episode
+
+import (
+	"context"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"eventsure-server/application/apperr"
+	"eventsure-server/domain/chain"
+	eventsureepisode "eventsure-server/domain/episode"
+	"eventsure-server/infrastructure/contract"
+	"eventsure-server/infrastructure/etherscan"
+	"eventsure-server/infrastructure/tracing"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// GetUserPortfolio combines a user's user episodes with the on-chain member state
+// of each episode to report what the user paid and can currently claim
+func (uc *UseCase) GetUserPortfolio(ctx context.Context, user chain.Address) (response *GetUserPortfolioResponse, err error) {
+	ctx, span := tracing.Start(ctx, "episode.GetUserPortfolio", attribute.String("user.address", user.String()))
+	defer func() { tracing.End(span, err) }()
+
+	if uc.userEpisodeRepo == nil {
+		return nil, apperr.Unavailable("user episode repository is not initialized", nil)
+	}
+
+	if user.IsZero() {
+		return nil, apperr.Validation("user is required")
+	}
+
+	userEpisodes, err := uc.userEpisodeRepo.FindByUser(ctx, user.String())
+	if err != nil {
+		return nil, err
+	}
+
+	etherscanClient, err := etherscan.NewEtherscanClient(uc.etherscan)
+	if err != nil {
+		return nil, apperr.Unavailable("failed to create Etherscan client", err)
+	}
+	etherscanClient = etherscanClient.WithContext(ctx)
+
+	response = &GetUserPortfolioResponse{
+		User:     user.String(),
+		Episodes: []PortfolioEpisodeDTO{},
+	}
+	totals := struct {
+		premiumPaid, payoutReceived, surplusReceived, claimablePayout, claimableSurplus big.Int
+	}{}
+
+	seen := make(map[string]bool)
+	for _, userEpisode := range userEpisodes {
+		episodeAddress := strings.ToLower(userEpisode.Episode())
+		if episodeAddress == "" || seen[episodeAddress] {
+			continue
+		}
+		seen[episodeAddress] = true
+
+		// Rows pointing at contracts the factory does not know, or the summary sync has not seen yet, are skipped
+		summary, err := uc.summaryRepo.FindByAddress(episodeAddress)
+		if err != nil {
+			return nil, err
+		}
+		if summary == nil {
+			continue
+		}
+
+		position, err := readPosition(etherscanClient, summary, user.String())
+		if err != nil {
+			return nil, err
+		}
+
+		dto := PortfolioEpisodeDTO{
+			UserEpisodeID:    userEpisode.ID(),
+			Episode:          summary.Address(),
+			State:            string(position.State()),
+			FlightName:       summary.FlightName(),
+			DepartureTime:    summary.DepartureTime().UTC().Format(time.RFC3339),
+			Joined:           position.Joined(),
+			PremiumPaid:      position.PremiumPaid().String(),
+			PayoutClaimed:    position.PayoutClaimed(),
+			SurplusWithdrawn: position.SurplusWithdrawn(),
+			ClaimablePayout:  position.ClaimablePayout().String(),
+			ClaimableSurplus: position.ClaimableSurplus().String(),
+		}
+		if position.Resolved() {
+			eventOccurred := position.EventOccurred()
+			dto.EventOccurred = &eventOccurred
+		}
+		response.Episodes = append(response.Episodes, dto)
+
+		totals.premiumPaid.Add(&totals.premiumPaid, position.PremiumPaid())
+		totals.payoutReceived.Add(&totals.payoutReceived, position.PayoutReceived())
+		totals.surplusReceived.Add(&totals.surplusReceived, position.SurplusReceived())
+		totals.claimablePayout.Add(&totals.claimablePayout, position.ClaimablePayout())
+		totals.claimableSurplus.Add(&totals.claimableSurplus, position.ClaimableSurplus())
+	}
+
+	response.Totals = PortfolioTotalsDTO{
+		EpisodeCount:     len(response.Episodes),
+		PremiumPaid:      totals.premiumPaid.String(),
+		PayoutReceived:   totals.payoutReceived.String(),
+		SurplusReceived:  totals.surplusReceived.String(),
+		ClaimablePayout:  totals.claimablePayout.String(),
+		ClaimableSurplus: totals.claimableSurplus.String(),
+	}
+
+	span.SetAttributes(attribute.Int("result.count", len(response.Episodes)))
+	return response, nil
+}
+
+// readPosition reads member's on-chain position in the episode. The episode state is read live
+// with the member flags, since the summary's can be a refresh interval old: right after settlement
+// it would hide a claimable payout. The oracle outcome, surplus and total premium are only read
+// once the episode has been resolved.
+func readPosition(caller contract.Caller, summary *eventsureepisode.Summary, member string) (*eventsureepisode.Position, error) {
+	episodeContract := contract.NewEpisode(caller, summary.Address())
+
+	stateIndex, err := episodeContract.State()
+	if err != nil {
+		return nil, apperr.Unavailable("failed to get episode state", err)
+	}
+	state, ok := eventsureepisode.StateFromIndex(stateIndex)
+	if !ok {
+		return nil, apperr.Unavailable("unknown episode state "+strconv.Itoa(int(stateIndex)), nil)
+	}
+
+	membership, err := episodeContract.Members(member)
+	if err != nil {
+		return nil, apperr.Unavailable("failed to get membership", err)
+	}
+
+	premiumPaid, err := episodeContract.PremiumOf(member)
+	if err != nil {
+		return nil, apperr.Unavailable("failed to get premium", err)
+	}
+
+	claimed, err := episodeContract.Claimed(member)
+	if err != nil {
+		return nil, apperr.Unavailable("failed to get claim status", err)
+	}
+
+	surplusWithdrawn, err := episodeContract.SurplusWithdrawn(member)
+	if err != nil {
+		return nil, apperr.Unavailable("failed to get surplus withdrawal status", err)
+	}
+
+	position := eventsureepisode.NewPosition(summary, strings.ToLower(member), state, membership.Joined, premiumPaid, claimed, surplusWithdrawn)
+
+	switch state {
+	case eventsureepisode.StateResolved, eventsureepisode.StateSettled, eventsureepisode.StateClosed:
+		eventOccurred, err := episodeContract.EventOccurred()
+		if err != nil {
+			return nil, apperr.Unavailable("failed to get event outcome", err)
+		}
+		surplus, err := episodeContract.Surplus()
+		if err != nil {
+			return nil, apperr.Unavailable("failed to get surplus", err)
+		}
+		totalPremium, err := episodeContract.TotalPremium()
+		if err != nil {
+			return nil, apperr.Unavailable("failed to get total premium", err)
+		}
+		position.SetResolution(eventOccurred, surplus, totalPremium)
+	}
+
+	return position, nil
+}
@@ -1,210 +1,252 @@
 package episode
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 
+	"eventsure-server/application/apperr"
+	"eventsure-server/domain/chain"
+	eventsureepisode "eventsure-server/domain/episode"
+	"eventsure-server/domain/event"
+	"eventsure-server/domain/membership"
+	"eventsure-server/infrastructure/config"
+	"eventsure-server/infrastructure/contract"
 	"eventsure-server/infrastructure/etherscan"
-	"eventsure-server/infrastructure/repository"
-	"os"
+	"eventsure-server/infrastructure/tracing"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+var (
+	// ErrUserEpisodeNotFound is returned when no user episode has the requested ID
+	ErrUserEpisodeNotFound = apperr.NotFound("user episode not found")
+	// ErrInvalidProgress is returned for progress values outside the membership lifecycle
+	ErrInvalidProgress = apperr.Validation("invalid progress")
 )
 
 // UseCase handles episode use cases
 type UseCase struct {
-	userEpisodeRepo *repository.UserEpisodeRepository
+	userEpisodeRepo membership.Repository
+	summaryRepo     eventsureepisode.SummaryRepository
+	eventStore      event.Store
+	etherscan       config.Etherscan
+	contracts       config.Contracts
+	summaries       config.Summaries
+
+	// summarySyncMu serializes SyncSummaries
+	summarySyncMu sync.Mutex
 }
 
-// NewUseCase creates a new EpisodeUseCase
-func NewUseCase() *UseCase {
-	userEpisodeRepo, err := repository.NewUserEpisodeRepository()
-	if err != nil {
-		// Repository 초기화 실패 시 nil로 설정
-		userEpisodeRepo = nil
-	}
-
+// NewUseCase creates a new EpisodeUseCase reading the chain through Etherscan with etherscanCfg.
+// summaries.Interval is how often SyncSummaries is expected to run, which decides when a summary is stale.
+func NewUseCase(userEpisodeRepo membership.Repository, summaryRepo eventsureepisode.SummaryRepository, eventStore event.Store, etherscanCfg config.Etherscan, contracts config.Contracts, summaries config.Summaries) *UseCase {
 	return &UseCase{
 		userEpisodeRepo: userEpisodeRepo,
+		summaryRepo:     summaryRepo,
+		eventStore:      eventStore,
+		etherscan:       etherscanCfg,
+		contracts:       contracts,
+		summaries:       summaries,
 	}
 }
 
-// CreateUserEpisode creates a new user_episode record in Supabase
-func (uc *UseCase) CreateUserEpisode(req CreateUserEpisodeRequest) (*CreateUserEpisodeResponse, error) {
+// CreateUserEpisode creates a new user_episode record.
+// Addresses are matched case-insensitively; when the user already joined the episode
+// the existing record is returned and created is false.
+func (uc *UseCase) CreateUserEpisode(ctx context.Context, req CreateUserEpisodeRequest) (response *CreateUserEpisodeResponse, created bool, err error) {
+	ctx, span := tracing.Start(ctx, "episode.CreateUserEpisode", attribute.String("user.address", req.User.String()), attribute.String("episode.address", req.Episode.String()))
+	defer func() { tracing.End(span, err) }()
+
 	if uc.userEpisodeRepo == nil {
-		return nil, errors.New("user episode repository is not initialized")
+		return nil, false, apperr.Unavailable("user episode repository is not initialized", nil)
 	}
 
-	if req.User == "" {
-		return nil, errors.New("user is required")
+	if req.User.IsZero() {
+		return nil, false, apperr.Validation("user is required")
 	}
-	if req.Episode == "" {
-		return nil, errors.New("episode is required")
+	if req.Episode.IsZero() {
+		return nil, false, apperr.Validation("episode is required")
 	}
 
-	result, err := uc.userEpisodeRepo.Create(req.User, req.Episode)
-	if err != nil {
-		return nil, err
+	if err := uc.requireEpisode(ctx, req.Episode); err != nil {
+		if errors.Is(err, eventsureepisode.ErrNotEpisode) {
+			return nil, false, apperr.New(apperr.ErrValidation, "episode "+req.Episode.String()+" is not an episode of the factory", err)
+		}
+		return nil, false, err
 	}
 
-	if result == nil {
-		return nil, errors.New("failed to create user_episode")
+	userEpisode, err := uc.userEpisodeRepo.FindByUserAndEpisode(ctx, req.User.String(), req.Episode.String())
+	if err != nil {
+		return nil, false, err
+	}
+
+	if userEpisode == nil {
+		userEpisode, err = uc.userEpisodeRepo.Create(ctx, membership.NewUserEpisode(req.User, req.Episode))
+		switch {
+		case errors.Is(err, membership.ErrDuplicate):
+			// A concurrent request created it first
+			userEpisode, err = uc.userEpisodeRepo.FindByUserAndEpisode(ctx, req.User.String(), req.Episode.String())
+			if err != nil {
+				return nil, false, err
+			}
+			if userEpisode == nil {
+				return nil, false, errors.New("failed to find duplicate user episode")
+			}
+		case err != nil:
+			return nil, false, err
+		default:
+			created = true
+		}
 	}
 
-	// Convert map to response DTO
-	response := &CreateUserEpisodeResponse{}
+	span.SetAttributes(attribute.Bool("user_episode.created", created))
+	dto := CreateUserEpisodeResponse(toUserEpisodeDTO(userEpisode))
+	return &dto, created, nil
+}
 
-	// id는 int64로 변환
-	if id, ok := result["id"].(float64); ok {
-		response.ID = int64(id)
-	} else if id, ok := result["id"].(int64); ok {
-		response.ID = id
-	}
+// GetUserEpisodes gets all episodes for a specific user, optionally only those at progress
+func (uc *UseCase) GetUserEpisodes(ctx context.Context, user chain.Address, progress membership.Progress) (response *GetUserEpisodesResponse, err error) {
+	ctx, span := tracing.Start(ctx, "episode.GetUserEpisodes", attribute.String("user.address", user.String()))
+	defer func() { tracing.End(span, err) }()
 
-	// user는 string
-	if user, ok := result["user"].(string); ok {
-		response.User = user
+	if uc.userEpisodeRepo == nil {
+		return nil, apperr.Unavailable("user episode repository is not initialized", nil)
 	}
 
-	// episode는 string
-	if episode, ok := result["episode"].(string); ok {
-		response.Episode = episode
+	if user.IsZero() {
+		return nil, apperr.Validation("user is required")
 	}
 
-	// progress는 nullable
-	if progress, ok := result["progress"].(string); ok && progress != "" {
-		response.Progress = &progress
+	userEpisodes, err := uc.userEpisodeRepo.FindByUser(ctx, user.String())
+	if err != nil {
+		return nil, err
 	}
 
-	// created_at은 string
-	if createdAt, ok := result["created_at"].(string); ok {
-		response.CreatedAt = createdAt
+	response = &GetUserEpisodesResponse{
+		Episodes: toUserEpisodeDTOs(filterByProgress(userEpisodes, progress)),
 	}
-
+	span.SetAttributes(attribute.Int("result.count", len(response.Episodes)))
 	return response, nil
 }
 
-// GetUserEpisodes gets all episodes for a specific user
-func (uc *UseCase) GetUserEpisodes(user string) (*GetUserEpisodesResponse, error) {
+// GetEpisodeUsers gets all users for a specific episode, optionally only those at progress
+func (uc *UseCase) GetEpisodeUsers(ctx context.Context, episode chain.Address, progress membership.Progress) (response *GetEpisodeUsersResponse, err error) {
+	ctx, span := tracing.Start(ctx, "episode.GetEpisodeUsers", attribute.String("episode.address", episode.String()))
+	defer func() { tracing.End(span, err) }()
+
 	if uc.userEpisodeRepo == nil {
-		return nil, errors.New("user episode repository is not initialized")
+		return nil, apperr.Unavailable("user episode repository is not initialized", nil)
 	}
 
-	if user == "" {
-		return nil, errors.New("user is required")
+	if episode.IsZero() {
+		return nil, apperr.Validation("episode is required")
 	}
 
-	results, err := uc.userEpisodeRepo.FindByUser(user)
+	userEpisodes, err := uc.userEpisodeRepo.FindByEpisode(ctx, episode.String())
 	if err != nil {
 		return nil, err
 	}
 
-	episodes := make([]UserEpisodeDTO, len(results))
-	for i, result := range results {
-		episode := UserEpisodeDTO{}
-
-		// id는 int64로 변환
-		if id, ok := result["id"].(float64); ok {
-			episode.ID = int64(id)
-		} else if id, ok := result["id"].(int64); ok {
-			episode.ID = id
-		}
-
-		// user는 string
-		if u, ok := result["user"].(string); ok {
-			episode.User = u
-		}
-
-		// episode는 string
-		if ep, ok := result["episode"].(string); ok {
-			episode.Episode = ep
-		}
-
-		// progress는 nullable
-		if progress, ok := result["progress"].(string); ok && progress != "" {
-			episode.Progress = &progress
-		}
-
-		// created_at은 string
-		if createdAt, ok := result["created_at"].(string); ok {
-			episode.CreatedAt = createdAt
-		}
-
-		episodes[i] = episode
+	response = &GetEpisodeUsersResponse{
+		Users: toUserEpisodeDTOs(filterByProgress(userEpisodes, progress)),
 	}
-
-	return &GetUserEpisodesResponse{
-		Episodes: episodes,
-	}, nil
+	span.SetAttributes(attribute.Int("result.count", len(response.Users)))
+	return response, nil
 }
 
-// GetEpisodeUsers gets all users for a specific episode
-func (uc *UseCase) GetEpisodeUsers(episode string) (*GetEpisodeUsersResponse, error) {
+// UpdateUserEpisodeProgress sets the progress of a user episode by hand.
+// The correction holds until new events of the episode are indexed.
+func (uc *UseCase) UpdateUserEpisodeProgress(ctx context.Context, id int64, req UpdateUserEpisodeRequest) (response *UserEpisodeDTO, err error) {
+	ctx, span := tracing.Start(ctx, "episode.UpdateUserEpisodeProgress", attribute.Int64("user_episode.id", id))
+	defer func() { tracing.End(span, err) }()
+
 	if uc.userEpisodeRepo == nil {
-		return nil, errors.New("user episode repository is not initialized")
+		return nil, apperr.Unavailable("user episode repository is not initialized", nil)
 	}
 
-	if episode == "" {
-		return nil, errors.New("episode is required")
+	progress, ok := membership.ParseProgress(req.Progress)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidProgress, req.Progress)
 	}
 
-	results, err := uc.userEpisodeRepo.FindByEpisode(episode)
+	userEpisode, err := uc.userEpisodeRepo.FindByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
+	if userEpisode == nil {
+		return nil, ErrUserEpisodeNotFound
+	}
 
-	users := make([]UserEpisodeDTO, len(results))
-	for i, result := range results {
-		user := UserEpisodeDTO{}
-
-		// id는 int64로 변환
-		if id, ok := result["id"].(float64); ok {
-			user.ID = int64(id)
-		} else if id, ok := result["id"].(int64); ok {
-			user.ID = id
-		}
-
-		// user는 string
-		if u, ok := result["user"].(string); ok {
-			user.User = u
-		}
-
-		// episode는 string
-		if ep, ok := result["episode"].(string); ok {
-			user.Episode = ep
-		}
+	userEpisode.SetProgress(progress)
+	if err := uc.userEpisodeRepo.Update(ctx, userEpisode); err != nil {
+		return nil, err
+	}
 
-		// progress는 nullable
-		if progress, ok := result["progress"].(string); ok && progress != "" {
-			user.Progress = &progress
-		}
+	dto := toUserEpisodeDTO(userEpisode)
+	return &dto, nil
+}
 
-		// created_at은 string
-		if createdAt, ok := result["created_at"].(string); ok {
-			user.CreatedAt = createdAt
+// filterByProgress keeps the user episodes at progress; an empty progress keeps all
+func filterByProgress(userEpisodes []*membership.UserEpisode, progress membership.Progress) []*membership.UserEpisode {
+	if progress == "" {
+		return userEpisodes
+	}
+	filtered := make([]*membership.UserEpisode, 0, len(userEpisodes))
+	for _, u := range userEpisodes {
+		if u.Progress() == progress {
+			filtered = append(filtered, u)
 		}
+	}
+	return filtered
+}
 
-		users[i] = user
+// toUserEpisodeDTO converts a UserEpisode to its DTO
+func toUserEpisodeDTO(u *membership.UserEpisode) UserEpisodeDTO {
+	dto := UserEpisodeDTO{
+		ID:        u.ID(),
+		User:      u.User(),
+		Episode:   u.Episode(),
+		CreatedAt: u.CreatedAt().UTC().Format(time.RFC3339Nano),
+	}
+	if progress := u.Progress(); progress != "" {
+		s := string(progress)
+		dto.Progress = &s
 	}
+	return dto
+}
 
-	return &GetEpisodeUsersResponse{
-		Users: users,
-	}, nil
+// toUserEpisodeDTOs converts UserEpisodes to DTOs
+func toUserEpisodeDTOs(userEpisodes []*membership.UserEpisode) []UserEpisodeDTO {
+	dtos := make([]UserEpisodeDTO, len(userEpisodes))
+	for i, u := range userEpisodes {
+		dtos[i] = toUserEpisodeDTO(u)
+	}
+	return dtos
 }
 
 // GetAllEpisodes gets all episode contract addresses from Etherscan
 // by querying internal transactions of the EpisodeContractFactory
-func (uc *UseCase) GetAllEpisodes() (*GetAllEpisodesResponse, error) {
+func (uc *UseCase) GetAllEpisodes(ctx context.Context) (result *GetAllEpisodesResponse, err error) {
+	ctx, span := tracing.Start(ctx, "episode.GetAllEpisodes")
+	defer func() { tracing.End(span, err) }()
+
 	// Get EpisodeContractFactory address from environment variable
-	factoryAddress := os.Getenv("EPISODE_CONTRACT_FACTORY")
+	factoryAddress := uc.contracts.EpisodeFactory
 	if factoryAddress == "" {
-		return nil, errors.New("EPISODE_CONTRACT_FACTORY environment variable is not set")
+		return nil, apperr.Unavailable("EPISODE_CONTRACT_FACTORY is not set", nil)
 	}
 
 	// Create Etherscan client
-	etherscanClient, err := etherscan.NewEtherscanClient()
+	etherscanClient, err := etherscan.NewEtherscanClient(uc.etherscan)
 	if err != nil {
-		return nil, errors.New("failed to create Etherscan client: " + err.Error())
+		return nil, apperr.Unavailable("failed to create Etherscan client", err)
 	}
+	etherscanClient = etherscanClient.WithContext(ctx)
 
 	// Get internal transactions for the factory address
 	params := etherscan.GetInternalTransactionsParams{
@@ -214,48 +256,58 @@ func (uc *UseCase) GetAllEpisodes() (*GetAllEpisodesResponse, error) {
 
 	response, err := etherscanClient.GetInternalTransactions(params)
 	if err != nil {
-		return nil, errors.New("failed to get internal transactions: " + err.Error())
+		return nil, apperr.Unavailable("failed to get internal transactions", err)
 	}
 
-	// Extract unique contract addresses from the response
-	contractAddressMap := make(map[string]bool)
+	// Extract unique contract addresses from the response, keeping the newest-first order
+	seen := make(map[string]bool)
+	episodes := make([]string, 0, len(response.Result))
 	for _, tx := range response.Result {
-		if tx.ContractAddress != "" {
-			contractAddressMap[tx.ContractAddress] = true
+		addr := strings.ToLower(tx.ContractAddress)
+		if addr == "" || seen[addr] {
+			continue
 		}
-	}
-
-	// Convert map to slice
-	episodes := make([]string, 0, len(contractAddressMap))
-	for addr := range contractAddressMap {
+		seen[addr] = true
 		episodes = append(episodes, addr)
 	}
 
+	span.SetAttributes(attribute.Int("result.count", len(episodes)))
 	return &GetAllEpisodesResponse{
 		Episodes: episodes,
 	}, nil
 }
 
 // GetEpisodeEvents gets all events for a specific episode contract address
-func (uc *UseCase) GetEpisodeEvents(episodeAddress string) (*GetEpisodeEventsResponse, error) {
-	if episodeAddress == "" {
-		return nil, errors.New("episode address is required")
+func (uc *UseCase) GetEpisodeEvents(ctx context.Context, episodeAddress chain.Address) (result *GetEpisodeEventsResponse, err error) {
+	ctx, span := tracing.Start(ctx, "episode.GetEpisodeEvents", attribute.String("episode.address", episodeAddress.String()))
+	defer func() { tracing.End(span, err) }()
+
+	if episodeAddress.IsZero() {
+		return nil, apperr.Validation("episode address is required")
+	}
+
+	if err := uc.requireEpisode(ctx, episodeAddress); err != nil {
+		if errors.Is(err, eventsureepisode.ErrNotEpisode) {
+			return nil, apperr.New(apperr.ErrNotFound, "episode "+episodeAddress.String()+" not found", err)
+		}
+		return nil, err
 	}
 
 	// Create Etherscan client
-	etherscanClient, err := etherscan.NewEtherscanClient()
+	etherscanClient, err := etherscan.NewEtherscanClient(uc.etherscan)
 	if err != nil {
-		return nil, errors.New("failed to create Etherscan client: " + err.Error())
+		return nil, apperr.Unavailable("failed to create Etherscan client", err)
 	}
+	etherscanClient = etherscanClient.WithContext(ctx)
 
 	// Get event logs for the episode contract address
 	params := etherscan.GetEventLogsParams{
-		Address: episodeAddress,
+		Address: episodeAddress.String(),
 	}
 
 	response, err := etherscanClient.GetEventLogs(params)
 	if err != nil {
-		return nil, errors.New("failed to get event logs: " + err.Error())
+		return nil, apperr.Unavailable("failed to get event logs", err)
 	}
 
 	// Extract event information from event logs
@@ -276,11 +328,36 @@ func (uc *UseCase) GetEpisodeEvents(episodeAddress string) (*GetEpisodeEventsRes
 		})
 	}
 
+	span.SetAttributes(attribute.Int("result.count", len(events)))
 	return &GetEpisodeEventsResponse{
 		Events: events,
 	}, nil
 }
 
+// requireEpisode returns eventsureepisode.ErrNotEpisode unless the factory created the episode.
+// The check needs the factory, so it is skipped when EPISODE_CONTRACT_FACTORY is not set.
+func (uc *UseCase) requireEpisode(ctx context.Context, episodeAddress chain.Address) error {
+	factoryAddress := uc.contracts.EpisodeFactory
+	if factoryAddress == "" {
+		return nil
+	}
+
+	etherscanClient, err := etherscan.NewEtherscanClient(uc.etherscan)
+	if err != nil {
+		return apperr.Unavailable("failed to create Etherscan client", err)
+	}
+	etherscanClient = etherscanClient.WithContext(ctx)
+
+	isEpisode, err := contract.NewFactory(etherscanClient, factoryAddress).IsEpisode(episodeAddress.String())
+	if err != nil {
+		return apperr.Unavailable("failed to check episode", err)
+	}
+	if !isEpisode {
+		return eventsureepisode.ErrNotEpisode
+	}
+	return nil
+}
+
 // formatTimestamp converts Etherscan timestamp (hex or decimal string) to "2006-01-02 15:04:05" format
 func formatTimestamp(timestampStr string) string {
 	if timestampStr == "" {
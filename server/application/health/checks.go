@@ -0,0 +1,128 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"eventsure-server/application/indexer"
+	"eventsure-server/infrastructure/config"
+	"eventsure-server/infrastructure/etherscan"
+)
+
+// Pinger is a storage backend that can check it is reachable
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// ConfigCheck checks that the settings the server cannot work without are set and valid.
+// backend is the storage backend in use; the in-memory backend is reported as not persistent.
+func ConfigCheck(cfg *config.Config, backend string) Check {
+	return Check{
+		Name:     "config",
+		Critical: true,
+		Run: func(ctx context.Context) (map[string]interface{}, error) {
+			details := map[string]interface{}{
+				"storage":    backend,
+				"persistent": backend != "memory",
+			}
+			if file := cfg.File(); file != "" {
+				details["file"] = file
+			}
+
+			var problems []string
+			if missing := cfg.Missing(); len(missing) > 0 {
+				details["missing"] = missing
+				problems = append(problems, "missing "+strings.Join(missing, ", "))
+			}
+			if err := cfg.Validate(); err != nil {
+				problems = append(problems, strings.ReplaceAll(err.Error(), "\n", "; "))
+			}
+			if len(problems) > 0 {
+				return details, errors.New(strings.Join(problems, "; "))
+			}
+			return details, nil
+		},
+	}
+}
+
+// StorageCheck checks that the storage backend answers a query
+func StorageCheck(backend string, pinger Pinger) Check {
+	return Check{
+		Name:     "storage",
+		Critical: true,
+		Run: func(ctx context.Context) (map[string]interface{}, error) {
+			details := map[string]interface{}{"backend": backend}
+			if err := pinger.Ping(ctx); err != nil {
+				return details, errors.New("failed to reach " + backend + ": " + err.Error())
+			}
+			return details, nil
+		},
+	}
+}
+
+// EtherscanCheck checks that Etherscan accepts the API keys by reading the latest block
+func EtherscanCheck(cfg config.Etherscan) Check {
+	return Check{
+		Name:     "etherscan",
+		Critical: true,
+		Run: func(ctx context.Context) (map[string]interface{}, error) {
+			client, err := etherscan.NewEtherscanClient(cfg)
+			if err != nil {
+				return nil, err
+			}
+
+			latest, err := client.WithContext(ctx).GetBlockNumber()
+			if err != nil {
+				return nil, errors.New("failed to get latest block: " + err.Error())
+			}
+			return map[string]interface{}{"latestBlock": latest}, nil
+		},
+	}
+}
+
+// IndexerCheck checks that the indexer synced within three intervals and is at most maxLag blocks behind.
+// ix is nil when the indexer is disabled.
+func IndexerCheck(ix *indexer.Indexer, maxLag int64) Check {
+	started := time.Now()
+	return Check{
+		Name:     "indexer",
+		Critical: false,
+		Run: func(ctx context.Context) (map[string]interface{}, error) {
+			if ix == nil {
+				return nil, ErrDisabled
+			}
+
+			status := ix.Status()
+			details := map[string]interface{}{
+				"lagBlocks":    status.Lag,
+				"maxLagBlocks": maxLag,
+			}
+			if !status.LastSyncAt.IsZero() {
+				details["lastSyncAt"] = status.LastSyncAt.UTC().Format(time.RFC3339)
+			}
+			if !status.LastSuccessAt.IsZero() {
+				details["lastSuccessAt"] = status.LastSuccessAt.UTC().Format(time.RFC3339)
+			}
+
+			stale := 3 * ix.Interval()
+			lastSuccess := status.LastSuccessAt
+			if lastSuccess.IsZero() {
+				lastSuccess = started
+			}
+			if since := time.Since(lastSuccess); since > stale {
+				message := fmt.Sprintf("no successful sync for %s", since.Round(time.Second))
+				if status.Err != nil {
+					message += ": " + status.Err.Error()
+				}
+				return details, errors.New(message)
+			}
+			if status.Lag > maxLag {
+				return details, fmt.Errorf("%d blocks behind the latest block (max %d)", status.Lag, maxLag)
+			}
+			return details, nil
+		},
+	}
+}
@@ -0,0 +1,23 @@
+package health
+
+// ComponentDTO represents the result of checking one dependency
+type ComponentDTO struct {
+	Status    string                 `json:"status"`
+	Critical  bool                   `json:"critical"`
+	LatencyMs float64                `json:"latencyMs"`
+	Error     string                 `json:"error,omitempty"`
+	Details   map[string]interface{} `json:"details,omitempty"`
+}
+
+// HealthResponse represents the status of the server and of each checked dependency.
+// Status is down when a critical component is down and degraded when another one is.
+type HealthResponse struct {
+	Status     string                  `json:"status"`
+	CheckedAt  string                  `json:"checkedAt"`
+	Components map[string]ComponentDTO `json:"components"`
+}
+
+// Ready reports whether every critical component is up
+func (r *HealthResponse) Ready() bool {
+	return r.Status != StatusDown
+}
@@ -0,0 +1,139 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"eventsure-server/infrastructure/logging"
+	"eventsure-server/infrastructure/tracing"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Status values of components and of the whole server
+const (
+	StatusUp       = "up"
+	StatusDown     = "down"
+	StatusDegraded = "degraded"
+	StatusDisabled = "disabled"
+)
+
+// checkTimeout bounds each check, so a hanging dependency reports down instead of blocking the probe
+const checkTimeout = 10 * time.Second
+
+// ErrDisabled is returned by a check whose component is not configured to run
+var ErrDisabled = errors.New("disabled")
+
+// Check checks one dependency of the server.
+// A critical component that is down makes the server not ready.
+type Check struct {
+	Name     string
+	Critical bool
+	Run      func(ctx context.Context) (details map[string]interface{}, err error)
+}
+
+// UseCase checks the dependencies of the server
+type UseCase struct {
+	checks []Check
+}
+
+// NewUseCase creates a new health UseCase running checks
+func NewUseCase(checks ...Check) *UseCase {
+	return &UseCase{checks: checks}
+}
+
+// Ready runs the critical checks and returns their status without details
+func (uc *UseCase) Ready(ctx context.Context) *HealthResponse {
+	ctx, span := tracing.Start(ctx, "health.Ready")
+	defer span.End()
+
+	var critical []Check
+	for _, c := range uc.checks {
+		if c.Critical {
+			critical = append(critical, c)
+		}
+	}
+
+	response := run(ctx, critical)
+	for name, component := range response.Components {
+		component.Error = ""
+		component.Details = nil
+		response.Components[name] = component
+	}
+	span.SetAttributes(attribute.String("health.status", response.Status))
+	return response
+}
+
+// Details runs every check and returns their status with details
+func (uc *UseCase) Details(ctx context.Context) *HealthResponse {
+	ctx, span := tracing.Start(ctx, "health.Details")
+	defer span.End()
+
+	response := run(ctx, uc.checks)
+	span.SetAttributes(attribute.String("health.status", response.Status))
+	return response
+}
+
+// run runs checks concurrently and aggregates their status
+func run(ctx context.Context, checks []Check) *HealthResponse {
+	components := make([]ComponentDTO, len(checks))
+
+	var wg sync.WaitGroup
+	for i, c := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			components[i] = runCheck(ctx, c)
+		}()
+	}
+	wg.Wait()
+
+	response := &HealthResponse{
+		Status:     StatusUp,
+		CheckedAt:  time.Now().UTC().Format(time.RFC3339),
+		Components: make(map[string]ComponentDTO, len(checks)),
+	}
+	for i, c := range checks {
+		component := components[i]
+		response.Components[c.Name] = component
+		if component.Status != StatusDown {
+			continue
+		}
+		if c.Critical {
+			response.Status = StatusDown
+		} else if response.Status == StatusUp {
+			response.Status = StatusDegraded
+		}
+	}
+	return response
+}
+
+// runCheck runs a check with checkTimeout
+func runCheck(ctx context.Context, c Check) ComponentDTO {
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+
+	ctx, span := tracing.Start(ctx, "health.check "+c.Name, attribute.Bool("health.critical", c.Critical))
+	start := time.Now()
+	details, err := c.Run(ctx)
+	component := ComponentDTO{
+		Status:    StatusUp,
+		Critical:  c.Critical,
+		LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+		Details:   details,
+	}
+
+	switch {
+	case errors.Is(err, ErrDisabled):
+		component.Status = StatusDisabled
+		err = nil
+	case err != nil:
+		component.Status = StatusDown
+		component.Error = logging.Redact(err.Error())
+	}
+	span.SetAttributes(attribute.String("health.status", component.Status))
+	tracing.End(span, err)
+	return component
+}
@@ -0,0 +1,307 @@
+package indexer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"eventsure-server/domain/event"
+	"eventsure-server/infrastructure/config"
+	"eventsure-server/infrastructure/contract"
+	"eventsure-server/infrastructure/etherscan"
+	"eventsure-server/infrastructure/metrics"
+	"eventsure-server/infrastructure/tracing"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// pageSize is the number of logs requested per Etherscan getLogs page (the API maximum)
+const pageSize = 1000
+
+// Indexer decodes Episode contract logs from Etherscan into the event store.
+// Each episode is indexed from its own checkpoint, so newly created episodes are
+// backfilled from the start while known episodes only fetch new blocks.
+type Indexer struct {
+	store       event.Store
+	checkpoints event.CheckpointRepository
+	interval    time.Duration
+	etherscan   config.Etherscan
+	contracts   config.Contracts
+	mu          sync.Mutex
+	status      Status
+}
+
+// Status describes the last sync of the indexer
+type Status struct {
+	// LastSyncAt is when the last sync finished, zero before the first sync
+	LastSyncAt time.Time
+	// LastSuccessAt is when the last successful sync finished
+	LastSuccessAt time.Time
+	// Lag is the number of blocks the furthest behind episode was behind the latest block
+	// when the last sync started
+	Lag int64
+	// Err is the error of the last sync, nil if it succeeded
+	Err error
+}
+
+// NewIndexer creates a new Indexer that syncs the episodes of the configured factory every cfg.Interval
+func NewIndexer(store event.Store, checkpoints event.CheckpointRepository, cfg config.Indexer, etherscanCfg config.Etherscan, contracts config.Contracts) *Indexer {
+	return &Indexer{
+		store:       store,
+		checkpoints: checkpoints,
+		interval:    cfg.Interval,
+		etherscan:   etherscanCfg,
+		contracts:   contracts,
+	}
+}
+
+// Interval returns the time between syncs
+func (ix *Indexer) Interval() time.Duration {
+	return ix.interval
+}
+
+// Status returns the status of the last sync
+func (ix *Indexer) Status() Status {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	return ix.status
+}
+
+// record records the result of a sync
+func (ix *Indexer) record(lag int64, err error) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+
+	now := time.Now()
+	ix.status.LastSyncAt = now
+	ix.status.Lag = lag
+	ix.status.Err = err
+	if err == nil {
+		ix.status.LastSuccessAt = now
+	}
+}
+
+// CheckpointName returns the checkpoint name used for an episode
+func CheckpointName(episode string) string {
+	return "episode:" + strings.ToLower(episode)
+}
+
+// Run syncs immediately and then every interval until ctx is cancelled.
+// A sync interrupted by the cancellation keeps the episodes it completed.
+func (ix *Indexer) Run(ctx context.Context) {
+	ticker := time.NewTicker(ix.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := ix.SyncOnce(ctx); err != nil && ctx.Err() == nil {
+			slog.ErrorContext(ctx, "indexer sync failed", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// SyncOnce indexes all factory episodes up to the latest block
+func (ix *Indexer) SyncOnce(ctx context.Context) (err error) {
+	ctx, span := tracing.Start(ctx, "indexer.SyncOnce")
+	defer func() { tracing.End(span, err) }()
+
+	// The lag is measured before indexing, so a failing indexer reports a growing lag
+	var lag int64
+	defer func() { ix.record(lag, err) }()
+
+	factoryAddress := ix.contracts.EpisodeFactory
+	if factoryAddress == "" {
+		return errors.New("EPISODE_CONTRACT_FACTORY is not set")
+	}
+
+	etherscanClient, err := etherscan.NewEtherscanClient(ix.etherscan)
+	if err != nil {
+		return errors.New("failed to create Etherscan client: " + err.Error())
+	}
+	etherscanClient = etherscanClient.WithContext(ctx)
+
+	latest, err := etherscanClient.GetBlockNumber()
+	if err != nil {
+		return errors.New("failed to get latest block: " + err.Error())
+	}
+
+	addresses, err := contract.NewFactory(etherscanClient, factoryAddress).AllEpisodes()
+	if err != nil {
+		return errors.New("failed to get episodes from factory: " + err.Error())
+	}
+
+	defer func() { metrics.SetIndexerLag(lag) }()
+
+	span.SetAttributes(attribute.Int("result.count", len(addresses)))
+	lag, err = ix.indexEpisodes(ctx, etherscanClient, addresses, latest)
+	return err
+}
+
+// indexEpisodes indexes the episodes up to toBlock and returns the largest lag among them.
+// An episode that fails is logged and skipped, so it does not hold back the episodes after it;
+// the failures are returned together.
+func (ix *Indexer) indexEpisodes(ctx context.Context, client *etherscan.EtherscanClient, addresses []string, toBlock int64) (lag int64, err error) {
+	var failed []error
+	for _, address := range addresses {
+		// Stop between episodes on shutdown; the remaining episodes resume from their checkpoints
+		if err := ctx.Err(); err != nil {
+			return lag, err
+		}
+		episodeLag, err := ix.indexEpisode(ctx, client, address, toBlock)
+		if episodeLag > lag {
+			lag = episodeLag
+		}
+		if err != nil {
+			slog.WarnContext(ctx, "failed to index episode", "episode", address, "error", err)
+			failed = append(failed, errors.New(address+": "+err.Error()))
+		}
+	}
+
+	if len(failed) > 0 {
+		return lag, fmt.Errorf("failed to index %d of %d episodes: %w", len(failed), len(addresses), errors.Join(failed...))
+	}
+	return lag, nil
+}
+
+// IndexEpisode indexes the logs of one episode from its checkpoint up to toBlock
+// and advances the checkpoint
+func (ix *Indexer) IndexEpisode(ctx context.Context, client *etherscan.EtherscanClient, address string, toBlock int64) error {
+	_, err := ix.indexEpisode(ctx, client, address, toBlock)
+	return err
+}
+
+// indexEpisode indexes one episode like IndexEpisode and returns the number of blocks
+// its checkpoint was behind toBlock
+func (ix *Indexer) indexEpisode(ctx context.Context, client *etherscan.EtherscanClient, address string, toBlock int64) (lag int64, err error) {
+	ctx, span := tracing.Start(ctx, "indexer.IndexEpisode", attribute.String("episode.address", address), attribute.Int64("indexer.to_block", toBlock))
+	defer func() { tracing.End(span, err) }()
+
+	name := CheckpointName(address)
+	checkpoint, err := ix.checkpoints.FindCheckpoint(ctx, name)
+	if err != nil {
+		return 0, errors.New("failed to get checkpoint: " + err.Error())
+	}
+
+	// Episodes without a checkpoint are backfilled from the start and do not count as lag
+	if checkpoint > 0 {
+		lag = toBlock - checkpoint
+	}
+	fromBlock := checkpoint + 1
+	if fromBlock > toBlock {
+		return 0, nil
+	}
+
+	var events []*event.Event
+	offset := pageSize
+	for page := 1; ; page++ {
+		p := page
+		response, err := client.GetEventLogs(etherscan.GetEventLogsParams{
+			Address:   address,
+			FromBlock: &fromBlock,
+			ToBlock:   &toBlock,
+			Page:      &p,
+			Offset:    &offset,
+		})
+		if err != nil {
+			return lag, errors.New("failed to get event logs for " + address + ": " + err.Error())
+		}
+
+		for _, l := range response.Result {
+			e, ok, err := contract.DecodeEpisodeLog(l)
+			if err != nil {
+				return lag, errors.New("failed to decode event log: " + err.Error())
+			}
+			if ok {
+				events = append(events, e)
+			}
+		}
+
+		if len(response.Result) < pageSize {
+			break
+		}
+	}
+
+	span.SetAttributes(attribute.Int("result.count", len(events)))
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Before(events[j])
+	})
+
+	// The logs are fetched, so store them and advance the checkpoint even when ctx was cancelled meanwhile
+	storeCtx := context.WithoutCancel(ctx)
+	if err := ix.store.Append(storeCtx, events); err != nil {
+		return lag, errors.New("failed to store events: " + err.Error())
+	}
+
+	if err := ix.checkpoints.SaveCheckpoint(storeCtx, name, toBlock); err != nil {
+		return lag, errors.New("failed to save checkpoint: " + err.Error())
+	}
+	return lag, nil
+}
+
+// EpisodeStatus is the indexing state of one episode after a backfill
+type EpisodeStatus struct {
+	Episode    string
+	Checkpoint int64
+	Events     int
+}
+
+// Backfill indexes the episodes up to the latest block, or all factory episodes when none are given.
+// With reset their checkpoints are cleared first, so their logs are fetched again from the start;
+// events already stored are kept, since appending an event twice stores it once.
+func (ix *Indexer) Backfill(ctx context.Context, episodes []string, reset bool) (statuses []EpisodeStatus, err error) {
+	ctx, span := tracing.Start(ctx, "indexer.Backfill", attribute.Bool("indexer.reset", reset))
+	defer func() { tracing.End(span, err) }()
+
+	etherscanClient, err := etherscan.NewEtherscanClient(ix.etherscan)
+	if err != nil {
+		return nil, errors.New("failed to create Etherscan client: " + err.Error())
+	}
+	etherscanClient = etherscanClient.WithContext(ctx)
+
+	if len(episodes) == 0 {
+		if ix.contracts.EpisodeFactory == "" {
+			return nil, errors.New("EPISODE_CONTRACT_FACTORY is not set")
+		}
+		episodes, err = contract.NewFactory(etherscanClient, ix.contracts.EpisodeFactory).AllEpisodes()
+		if err != nil {
+			return nil, errors.New("failed to get episodes from factory: " + err.Error())
+		}
+	}
+
+	latest, err := etherscanClient.GetBlockNumber()
+	if err != nil {
+		return nil, errors.New("failed to get latest block: " + err.Error())
+	}
+
+	span.SetAttributes(attribute.Int("result.count", len(episodes)))
+	for _, address := range episodes {
+		if err := ctx.Err(); err != nil {
+			return statuses, err
+		}
+		if reset {
+			if err := ix.checkpoints.SaveCheckpoint(ctx, CheckpointName(address), 0); err != nil {
+				return statuses, errors.New("failed to reset checkpoint: " + err.Error())
+			}
+		}
+		if _, err := ix.indexEpisode(ctx, etherscanClient, address, latest); err != nil {
+			return statuses, err
+		}
+
+		events, err := ix.store.FindByEpisode(ctx, address)
+		if err != nil {
+			return statuses, errors.New("failed to get episode events: " + err.Error())
+		}
+		statuses = append(statuses, EpisodeStatus{Episode: strings.ToLower(address), Checkpoint: latest, Events: len(events)})
+	}
+	return statuses, nil
+}
@@ -0,0 +1,75 @@
+package indexer
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"eventsure-server/infrastructure/config"
+)
+
+// checkpointStub returns the checkpoint of each episode, failing for the episodes in failing
+type checkpointStub struct {
+	checkpoints map[string]int64
+	failing     map[string]bool
+	visited     []string
+}
+
+func (s *checkpointStub) FindCheckpoint(ctx context.Context, name string) (int64, error) {
+	s.visited = append(s.visited, name)
+	if s.failing[name] {
+		return 0, errors.New("storage unavailable")
+	}
+	return s.checkpoints[name], nil
+}
+
+func (s *checkpointStub) SaveCheckpoint(ctx context.Context, name string, block int64) error {
+	s.checkpoints[name] = block
+	return nil
+}
+
+func TestIndexEpisodesContinuesPastFailures(t *testing.T) {
+	const latest = 1000
+	addresses := []string{
+		"0x1111111111111111111111111111111111111111",
+		"0x2222222222222222222222222222222222222222",
+		"0x3333333333333333333333333333333333333333",
+	}
+	// The first and last episodes fail; the middle one is up to date, so no logs are fetched
+	checkpoints := &checkpointStub{
+		checkpoints: map[string]int64{CheckpointName(addresses[1]): latest},
+		failing:     map[string]bool{CheckpointName(addresses[0]): true, CheckpointName(addresses[2]): true},
+	}
+	ix := NewIndexer(nil, checkpoints, config.Indexer{}, config.Etherscan{}, config.Contracts{})
+
+	_, err := ix.indexEpisodes(context.Background(), nil, addresses, latest)
+	if len(checkpoints.visited) != len(addresses) {
+		t.Fatalf("visited %v, want all %d episodes", checkpoints.visited, len(addresses))
+	}
+	if err == nil {
+		t.Fatal("indexEpisodes succeeded, want the failures")
+	}
+	for _, want := range []string{"failed to index 2 of 3 episodes", addresses[0], addresses[2]} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not mention %q", err, want)
+		}
+	}
+	if strings.Contains(err.Error(), addresses[1]) {
+		t.Errorf("error %q mentions the indexed episode", err)
+	}
+}
+
+func TestIndexEpisodesStopsOnShutdown(t *testing.T) {
+	checkpoints := &checkpointStub{checkpoints: map[string]int64{}}
+	ix := NewIndexer(nil, checkpoints, config.Indexer{}, config.Etherscan{}, config.Contracts{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := ix.indexEpisodes(ctx, nil, []string{"0x1111111111111111111111111111111111111111"}, 1); !errors.Is(err, context.Canceled) {
+		t.Errorf("error = %v, want context.Canceled", err)
+	}
+	if len(checkpoints.visited) != 0 {
+		t.Errorf("visited %v after shutdown", checkpoints.visited)
+	}
+}
@@ -0,0 +1,35 @@
+package pricing
+
+// QuoteRequest represents a request for a premium/payout quote.
+// Exactly one of PayoutAmount and PremiumAmount (wei, decimal strings) must be set.
+type QuoteRequest struct {
+	Route         string  `json:"route,omitempty"`
+	Carrier       string  `json:"carrier,omitempty"`
+	PayoutAmount  string  `json:"payoutAmount,omitempty"`
+	PremiumAmount string  `json:"premiumAmount,omitempty"`
+	Confidence    float64 `json:"confidence,omitempty"`
+	Loading       float64 `json:"loading,omitempty"`
+	// TargetSolvency is the least probability that the pool pays every claim, 0 for none
+	TargetSolvency float64 `json:"targetSolvency,omitempty"`
+}
+
+// QuoteResponse represents a recommended premium/payout pair.
+// Amounts are in wei and encoded as decimal strings.
+type QuoteResponse struct {
+	Route             string   `json:"route"`
+	Carrier           string   `json:"carrier"`
+	EstimateLevel     string   `json:"estimateLevel"`
+	Observations      int      `json:"observations"`
+	Delayed           int      `json:"delayed"`
+	DelayProbability  float64  `json:"delayProbability"`
+	PricedProbability float64  `json:"pricedProbability"`
+	Confidence        float64  `json:"confidence"`
+	Loading           float64  `json:"loading"`
+	TargetSolvency    float64  `json:"targetSolvency"`
+	PremiumAmount     string   `json:"premiumAmount"`
+	PayoutAmount      string   `json:"payoutAmount"`
+	ExpectedLossRatio float64  `json:"expectedLossRatio"`
+	CoverageRatio     float64  `json:"coverageRatio"`
+	Solvency          float64  `json:"solvency"`
+	Warnings          []string `json:"warnings"`
+}
@@ -0,0 +1,117 @@
+package pricing
+
+import (
+	"fmt"
+	"math/big"
+
+	"eventsure-server/application/apperr"
+	domainpricing "eventsure-server/domain/pricing"
+)
+
+const (
+	// DefaultConfidence is used when a request does not set a confidence
+	DefaultConfidence = 0.95
+	// minObservations is the group size below which a quote carries a warning
+	minObservations = 30
+)
+
+var (
+	// ErrInvalidRequest is returned for malformed quote requests
+	ErrInvalidRequest = apperr.Validation("invalid quote request")
+	// ErrNoDataset is returned when no historical arrivals have been loaded
+	ErrNoDataset = apperr.Unavailable("pricing dataset is not loaded", nil)
+)
+
+// UseCase handles premium pricing use cases
+type UseCase struct {
+	model *domainpricing.Model
+}
+
+// NewUseCase creates a new pricing UseCase. model may be nil when no dataset is configured.
+func NewUseCase(model *domainpricing.Model) *UseCase {
+	return &UseCase{
+		model: model,
+	}
+}
+
+// Quote recommends a premium/payout pair for a route and carrier
+func (uc *UseCase) Quote(req QuoteRequest) (*QuoteResponse, error) {
+	if uc.model == nil || uc.model.Observations() == 0 {
+		return nil, ErrNoDataset
+	}
+
+	if req.Route == "" && req.Carrier == "" {
+		return nil, fmt.Errorf("%w: route or carrier is required", ErrInvalidRequest)
+	}
+
+	params := domainpricing.QuoteParams{
+		Confidence:     req.Confidence,
+		Loading:        req.Loading,
+		TargetSolvency: req.TargetSolvency,
+	}
+	if params.Confidence == 0 {
+		params.Confidence = DefaultConfidence
+	}
+
+	var err error
+	if params.Payout, err = parseAmount("payoutAmount", req.PayoutAmount); err != nil {
+		return nil, err
+	}
+	if params.Premium, err = parseAmount("premiumAmount", req.PremiumAmount); err != nil {
+		return nil, err
+	}
+
+	estimate := uc.model.Estimate(req.Route, req.Carrier)
+	quote, err := domainpricing.NewQuote(estimate, params)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidRequest, err.Error())
+	}
+
+	response := &QuoteResponse{
+		Route:             estimate.Route,
+		Carrier:           estimate.Carrier,
+		EstimateLevel:     string(estimate.Level),
+		Observations:      estimate.Observations,
+		Delayed:           estimate.Delayed,
+		DelayProbability:  estimate.Probability(),
+		PricedProbability: quote.PricedProbability,
+		Confidence:        quote.Confidence,
+		Loading:           quote.Loading,
+		TargetSolvency:    quote.TargetSolvency,
+		PremiumAmount:     quote.Premium.String(),
+		PayoutAmount:      quote.Payout.String(),
+		ExpectedLossRatio: quote.ExpectedLossRatio,
+		CoverageRatio:     quote.CoverageRatio,
+		Solvency:          quote.Solvency,
+		Warnings:          []string{},
+	}
+
+	if estimate.Level != domainpricing.LevelRouteCarrier {
+		response.Warnings = append(response.Warnings,
+			fmt.Sprintf("no history for this route and carrier; estimate is based on the %s level", estimate.Level))
+	}
+	if estimate.Observations < minObservations {
+		response.Warnings = append(response.Warnings,
+			fmt.Sprintf("only %d observations; the estimate is uncertain", estimate.Observations))
+	}
+	if quote.CoverageRatio < 1 {
+		response.Warnings = append(response.Warnings,
+			fmt.Sprintf("payout exceeds premium: every member claims when the flight is delayed and Episode.settle() caps the total payout "+
+				"at the total premium, so only %.0f%% of the claims can be paid; the pool pays every claim with probability %.2f",
+				quote.CoverageRatio*100, quote.Solvency))
+	}
+
+	return response, nil
+}
+
+// parseAmount parses an optional wei amount
+func parseAmount(name, s string) (*big.Int, error) {
+	if s == "" {
+		return nil, nil
+	}
+	amount, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s must be a decimal wei amount", ErrInvalidRequest, name)
+	}
+	return amount, nil
+}
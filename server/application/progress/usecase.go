@@ -0,0 +1,148 @@
+package progress
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"eventsure-server/domain/event"
+	"eventsure-server/domain/membership"
+	"eventsure-server/infrastructure/tracing"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const (
+	// batchSize is the number of events read from the store per round trip
+	batchSize = 500
+	// checkpointName is the checkpoint holding the last event sequence written to user_episodes
+	checkpointName = "user-episode-progress"
+)
+
+// UseCase keeps user_episodes progress in step with the indexed episode events.
+// The projection is rebuilt from the whole event store on start, but progress is only
+// rewritten for episodes with events newer than the checkpoint, so admin corrections
+// stay until the episode moves on. User episodes without progress are looked up
+// separately on every sync and filled in once their member has joined.
+type UseCase struct {
+	store       event.Store
+	checkpoints event.CheckpointRepository
+	repo        membership.Repository
+	projection  *membership.ProgressProjection
+	checkpoint  int64
+	loaded      bool
+	// changed holds the episodes with events newer than the checkpoint. It is kept across
+	// syncs, so episodes applied to the projection by a failed sync are still updated.
+	changed map[string]bool
+	mu      sync.Mutex
+}
+
+// NewUseCase creates a new progress UseCase
+func NewUseCase(store event.Store, checkpoints event.CheckpointRepository, repo membership.Repository) *UseCase {
+	return &UseCase{
+		store:       store,
+		checkpoints: checkpoints,
+		repo:        repo,
+		projection:  membership.NewProgressProjection(),
+		changed:     make(map[string]bool),
+	}
+}
+
+// SyncOnce applies the events appended since the last sync and updates the progress
+// of the affected user episodes
+func (uc *UseCase) SyncOnce(ctx context.Context) (err error) {
+	ctx, span := tracing.Start(ctx, "progress.SyncOnce")
+	defer func() { tracing.End(span, err) }()
+
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	if !uc.loaded {
+		checkpoint, err := uc.checkpoints.FindCheckpoint(ctx, checkpointName)
+		if err != nil {
+			return errors.New("failed to get checkpoint: " + err.Error())
+		}
+		uc.checkpoint = checkpoint
+		uc.loaded = true
+	}
+
+	for {
+		events, err := uc.store.FindAfterSequence(ctx, uc.projection.LastSequence(), batchSize)
+		if err != nil {
+			return err
+		}
+		for _, e := range events {
+			uc.projection.Apply(e)
+			if e.Sequence > uc.checkpoint {
+				uc.changed[strings.ToLower(e.Episode)] = true
+			}
+		}
+		if len(events) < batchSize {
+			break
+		}
+	}
+
+	span.SetAttributes(attribute.Int("result.count", len(uc.changed)))
+	for episode := range uc.changed {
+		userEpisodes, err := uc.repo.FindByEpisode(ctx, episode)
+		if err != nil {
+			return err
+		}
+		if err := uc.update(ctx, userEpisodes); err != nil {
+			return err
+		}
+	}
+
+	unset, err := uc.repo.FindWithoutProgress(ctx)
+	if err != nil {
+		return err
+	}
+	if err := uc.update(ctx, unset); err != nil {
+		return err
+	}
+
+	if last := uc.projection.LastSequence(); last > uc.checkpoint {
+		if err := uc.checkpoints.SaveCheckpoint(ctx, checkpointName, last); err != nil {
+			return errors.New("failed to save checkpoint: " + err.Error())
+		}
+		uc.checkpoint = last
+	}
+	clear(uc.changed)
+	return nil
+}
+
+// update writes the projected progress of the user episodes that differ from it
+func (uc *UseCase) update(ctx context.Context, userEpisodes []*membership.UserEpisode) error {
+	for _, userEpisode := range userEpisodes {
+		progress, ok := uc.projection.Progress(userEpisode.Episode(), userEpisode.User())
+		if !ok || progress == userEpisode.Progress() {
+			continue
+		}
+		userEpisode.SetProgress(progress)
+		if err := uc.repo.Update(ctx, userEpisode); err != nil {
+			return errors.New("failed to update progress: " + err.Error())
+		}
+	}
+	return nil
+}
+
+// Run syncs immediately and then every interval until ctx is cancelled
+func (uc *UseCase) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := uc.SyncOnce(ctx); err != nil && ctx.Err() == nil {
+			slog.ErrorContext(ctx, "progress sync failed", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
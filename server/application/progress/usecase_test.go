@@ -0,0 +1,115 @@
+package progress
+
+import (
+	"context"
+	"testing"
+
+	"eventsure-server/domain/chain"
+	"eventsure-server/domain/event"
+	"eventsure-server/domain/membership"
+	"eventsure-server/infrastructure/repository"
+)
+
+// countingRepository records the episodes FindByEpisode is asked for
+type countingRepository struct {
+	membership.Repository
+	lookups []string
+}
+
+func (r *countingRepository) FindByEpisode(ctx context.Context, episode string) ([]*membership.UserEpisode, error) {
+	r.lookups = append(r.lookups, episode)
+	return r.Repository.FindByEpisode(ctx, episode)
+}
+
+const (
+	episodeA = "0x1111111111111111111111111111111111111111"
+	episodeB = "0x2222222222222222222222222222222222222222"
+	alice    = "0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+	bob      = "0xbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"
+)
+
+func lifecycleEvent(episode string, name event.Name, member string, logIndex int64) *event.Event {
+	return &event.Event{Episode: episode, Name: name, Member: member, TransactionHash: "0x01", LogIndex: logIndex}
+}
+
+func createUserEpisode(t *testing.T, repo membership.Repository, user, episode string) *membership.UserEpisode {
+	t.Helper()
+	userAddress, err := chain.ParseAddress(user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	episodeAddress, err := chain.ParseAddress(episode)
+	if err != nil {
+		t.Fatal(err)
+	}
+	userEpisode, err := repo.Create(context.Background(), membership.NewUserEpisode(userAddress, episodeAddress))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return userEpisode
+}
+
+func progressOf(t *testing.T, repo membership.Repository, id int64) membership.Progress {
+	t.Helper()
+	userEpisode, err := repo.FindByID(context.Background(), id)
+	if err != nil || userEpisode == nil {
+		t.Fatalf("FindByID(%d) = %v, %v", id, userEpisode, err)
+	}
+	return userEpisode.Progress()
+}
+
+func TestSyncOnceVisitsOnlyChangedEpisodes(t *testing.T) {
+	ctx := context.Background()
+	store := repository.NewEventStore()
+	repo := &countingRepository{Repository: repository.NewUserEpisodeRepository()}
+	uc := NewUseCase(store, repository.NewCheckpointRepository(), repo)
+
+	aliceA := createUserEpisode(t, repo, alice, episodeA)
+	if err := store.Append(ctx, []*event.Event{
+		lifecycleEvent(episodeA, event.NameMemberJoined, alice, 0),
+		lifecycleEvent(episodeB, event.NameMemberJoined, bob, 1),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := uc.SyncOnce(ctx); err != nil {
+		t.Fatalf("SyncOnce() error = %v", err)
+	}
+	if got := progressOf(t, repo, aliceA.ID()); got != membership.ProgressJoined {
+		t.Errorf("alice progress = %q, want %q", got, membership.ProgressJoined)
+	}
+	if len(repo.lookups) != 2 {
+		t.Errorf("first sync looked up %v, want both episodes", repo.lookups)
+	}
+
+	// Without new events no episode is revisited, but a user episode created
+	// after its member joined is still filled in
+	repo.lookups = nil
+	bobB := createUserEpisode(t, repo, bob, episodeB)
+	if err := uc.SyncOnce(ctx); err != nil {
+		t.Fatalf("SyncOnce() error = %v", err)
+	}
+	if len(repo.lookups) != 0 {
+		t.Errorf("idle sync looked up %v, want none", repo.lookups)
+	}
+	if got := progressOf(t, repo, bobB.ID()); got != membership.ProgressJoined {
+		t.Errorf("bob progress = %q, want %q", got, membership.ProgressJoined)
+	}
+
+	// Only the episode with new events is revisited
+	if err := store.Append(ctx, []*event.Event{lifecycleEvent(episodeA, event.NameEpisodeLocked, "", 2)}); err != nil {
+		t.Fatal(err)
+	}
+	if err := uc.SyncOnce(ctx); err != nil {
+		t.Fatalf("SyncOnce() error = %v", err)
+	}
+	if len(repo.lookups) != 1 || repo.lookups[0] != episodeA {
+		t.Errorf("sync after EpisodeLocked looked up %v, want [%s]", repo.lookups, episodeA)
+	}
+	if got := progressOf(t, repo, aliceA.ID()); got != membership.ProgressLocked {
+		t.Errorf("alice progress = %q, want %q", got, membership.ProgressLocked)
+	}
+	if got := progressOf(t, repo, bobB.ID()); got != membership.ProgressJoined {
+		t.Errorf("bob progress = %q, want %q", got, membership.ProgressJoined)
+	}
+}
@@ -0,0 +1,50 @@
+package simulation
+
+// SimulateRequest represents a solvency simulation request.
+// Amounts are in wei and encoded as decimal strings; Members is
+// "fixed:N", "uniform:MIN-MAX" or "poisson:MEAN" with counts and mean
+// of at most simulation.MaxMembers.
+type SimulateRequest struct {
+	PremiumAmount    string  `json:"premiumAmount"`
+	PayoutAmount     string  `json:"payoutAmount"`
+	Members          string  `json:"members"`
+	EventProbability float64 `json:"eventProbability"`
+	Trials           int     `json:"trials,omitempty"`
+	Seed             int64   `json:"seed,omitempty"`
+}
+
+// DistributionDTO summarises the distribution of a simulated quantity
+type DistributionDTO struct {
+	Mean float64 `json:"mean"`
+	P50  float64 `json:"p50"`
+	P95  float64 `json:"p95"`
+	P99  float64 `json:"p99"`
+	Max  float64 `json:"max"`
+}
+
+// ViolationDTO represents an invariant broken in some of the trials
+type ViolationDTO struct {
+	Invariant   int     `json:"invariant"`
+	Description string  `json:"description"`
+	Trials      int     `json:"trials"`
+	Probability float64 `json:"probability"`
+}
+
+// SimulateResponse represents the outcome distributions of a simulation.
+// Shortfall, stranded and surplus distributions are in wei.
+type SimulateResponse struct {
+	PremiumAmount        string          `json:"premiumAmount"`
+	PayoutAmount         string          `json:"payoutAmount"`
+	Members              string          `json:"members"`
+	EventProbability     float64         `json:"eventProbability"`
+	Trials               int             `json:"trials"`
+	Seed                 int64           `json:"seed"`
+	EventTrials          int             `json:"eventTrials"`
+	MeanMembers          float64         `json:"meanMembers"`
+	ShortfallProbability float64         `json:"shortfallProbability"`
+	PayoutShortfall      DistributionDTO `json:"payoutShortfall"`
+	FailedClaims         DistributionDTO `json:"failedClaims"`
+	SurplusPerMember     DistributionDTO `json:"surplusPerMember"`
+	StrandedFunds        DistributionDTO `json:"strandedFunds"`
+	Violations           []ViolationDTO  `json:"violations"`
+}
@@ -0,0 +1,106 @@
+package simulation
+
+import (
+	"fmt"
+	"math/big"
+
+	"eventsure-server/application/apperr"
+	domainsimulation "eventsure-server/domain/simulation"
+)
+
+// maxTrials bounds the work of a single simulation
+const maxTrials = 1000000
+
+// ErrInvalidRequest is returned for malformed simulation requests
+var ErrInvalidRequest = apperr.Validation("invalid simulation request")
+
+// UseCase handles episode solvency simulation use cases
+type UseCase struct{}
+
+// NewUseCase creates a new simulation UseCase
+func NewUseCase() *UseCase {
+	return &UseCase{}
+}
+
+// Simulate runs a Monte Carlo simulation of an episode with the requested parameters
+func (uc *UseCase) Simulate(req SimulateRequest) (*SimulateResponse, error) {
+	premium, err := parseAmount("premiumAmount", req.PremiumAmount)
+	if err != nil {
+		return nil, err
+	}
+	payout, err := parseAmount("payoutAmount", req.PayoutAmount)
+	if err != nil {
+		return nil, err
+	}
+
+	members, err := domainsimulation.ParseMemberDistribution(req.Members)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidRequest, err.Error())
+	}
+
+	if req.Trials > maxTrials {
+		return nil, fmt.Errorf("%w: trials must not exceed %d", ErrInvalidRequest, maxTrials)
+	}
+
+	report, err := domainsimulation.Run(domainsimulation.Params{
+		Premium:          premium,
+		Payout:           payout,
+		Members:          members,
+		EventProbability: req.EventProbability,
+		Trials:           req.Trials,
+		Seed:             req.Seed,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidRequest, err.Error())
+	}
+
+	response := &SimulateResponse{
+		PremiumAmount:        premium.String(),
+		PayoutAmount:         payout.String(),
+		Members:              members.String(),
+		EventProbability:     req.EventProbability,
+		Trials:               report.Trials,
+		Seed:                 req.Seed,
+		EventTrials:          report.EventTrials,
+		MeanMembers:          report.MeanMembers,
+		ShortfallProbability: report.ShortfallProbability,
+		PayoutShortfall:      toDistributionDTO(report.PayoutShortfall),
+		FailedClaims:         toDistributionDTO(report.FailedClaims),
+		SurplusPerMember:     toDistributionDTO(report.SurplusPerMember),
+		StrandedFunds:        toDistributionDTO(report.Stranded),
+		Violations:           make([]ViolationDTO, 0, len(report.Violations)),
+	}
+	for _, v := range report.Violations {
+		response.Violations = append(response.Violations, ViolationDTO{
+			Invariant:   v.Invariant,
+			Description: v.Description,
+			Trials:      v.Trials,
+			Probability: v.Probability,
+		})
+	}
+
+	return response, nil
+}
+
+// parseAmount parses a required positive wei amount
+func parseAmount(field, value string) (*big.Int, error) {
+	if value == "" {
+		return nil, fmt.Errorf("%w: %s is required", ErrInvalidRequest, field)
+	}
+	amount, ok := new(big.Int).SetString(value, 10)
+	if !ok || amount.Sign() <= 0 {
+		return nil, fmt.Errorf("%w: %s must be a positive integer in wei", ErrInvalidRequest, field)
+	}
+	return amount, nil
+}
+
+// toDistributionDTO converts a Distribution to its DTO
+func toDistributionDTO(d domainsimulation.Distribution) DistributionDTO {
+	return DistributionDTO{
+		Mean: d.Mean,
+		P50:  d.P50,
+		P95:  d.P95,
+		P99:  d.P99,
+		Max:  d.Max,
+	}
+}
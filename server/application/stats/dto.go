@@ -0,0 +1,28 @@
+package stats
+
+// BucketDTO represents joins and payouts within a time bucket.
+// Amounts are in wei and encoded as decimal strings.
+type BucketDTO struct {
+	Start        string `json:"start"`
+	Joins        int    `json:"joins"`
+	JoinAmount   string `json:"joinAmount"`
+	Payouts      int    `json:"payouts"`
+	PayoutAmount string `json:"payoutAmount"`
+}
+
+// GetStatsResponse represents protocol-wide statistics across all episodes.
+// Amounts are in wei and encoded as decimal strings.
+type GetStatsResponse struct {
+	Episodes        int               `json:"episodes"`
+	EpisodesByState map[string]int    `json:"episodesByState"`
+	TVL             string            `json:"tvl"`
+	TVLByState      map[string]string `json:"tvlByState"`
+	TotalPremiums   string            `json:"totalPremiums"`
+	TotalPayouts    string            `json:"totalPayouts"`
+	SurplusReturned string            `json:"surplusReturned"`
+	LossRatio       float64           `json:"lossRatio"`
+	UniqueMembers   int               `json:"uniqueMembers"`
+	Interval        string            `json:"interval"`
+	Series          []BucketDTO       `json:"series"`
+	LastSequence    int64             `json:"lastSequence"`
+}
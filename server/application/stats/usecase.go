@@ -0,0 +1,100 @@
+package stats
+
+import (
+	"context"
+	"math/big"
+	"sync"
+	"time"
+
+	"eventsure-server/domain/event"
+	domainstats "eventsure-server/domain/stats"
+	"eventsure-server/infrastructure/tracing"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// batchSize is the number of events read from the store per round trip
+const batchSize = 500
+
+// UseCase handles protocol statistics use cases
+type UseCase struct {
+	store      event.Store
+	projection *domainstats.Projection
+	mu         sync.Mutex
+}
+
+// NewUseCase creates a new stats UseCase backed by the event store
+func NewUseCase(store event.Store) *UseCase {
+	return &UseCase{
+		store:      store,
+		projection: domainstats.NewProjection(),
+	}
+}
+
+// GetStats returns protocol-wide statistics with a time series of the given interval.
+// Only events appended since the previous call are read from the store.
+func (uc *UseCase) GetStats(ctx context.Context, interval domainstats.Interval) (response *GetStatsResponse, err error) {
+	ctx, span := tracing.Start(ctx, "stats.GetStats", attribute.String("stats.interval", string(interval)))
+	defer func() { tracing.End(span, err) }()
+
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	if err := uc.catchUp(ctx); err != nil {
+		return nil, err
+	}
+
+	p := uc.projection
+	response = &GetStatsResponse{
+		Episodes:        p.EpisodeCount(),
+		EpisodesByState: make(map[string]int),
+		TVLByState:      make(map[string]string),
+		TotalPremiums:   p.TotalPremiums().String(),
+		TotalPayouts:    p.TotalPayouts().String(),
+		SurplusReturned: p.SurplusReturned().String(),
+		LossRatio:       p.LossRatio(),
+		UniqueMembers:   p.UniqueMembers(),
+		Interval:        string(interval),
+		Series:          []BucketDTO{},
+		LastSequence:    p.LastSequence(),
+	}
+
+	for state, count := range p.EpisodesByState() {
+		response.EpisodesByState[string(state)] = count
+	}
+
+	tvl := new(big.Int)
+	for state, amount := range p.TVLByState() {
+		response.TVLByState[string(state)] = amount.String()
+		tvl.Add(tvl, amount)
+	}
+	response.TVL = tvl.String()
+
+	for _, b := range p.Series(interval) {
+		response.Series = append(response.Series, BucketDTO{
+			Start:        b.Start.Format(time.RFC3339),
+			Joins:        b.Joins,
+			JoinAmount:   b.JoinAmount.String(),
+			Payouts:      b.Payouts,
+			PayoutAmount: b.PayoutAmount.String(),
+		})
+	}
+
+	return response, nil
+}
+
+// catchUp applies all events appended to the store since the last applied sequence
+func (uc *UseCase) catchUp(ctx context.Context) error {
+	for {
+		events, err := uc.store.FindAfterSequence(ctx, uc.projection.LastSequence(), batchSize)
+		if err != nil {
+			return err
+		}
+		for _, e := range events {
+			uc.projection.Apply(e)
+		}
+		if len(events) < batchSize {
+			return nil
+		}
+	}
+}
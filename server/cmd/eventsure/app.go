@@ -0,0 +1,142 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+
+	auditusecase "eventsure-server/application/audit"
+	episodeusecase "eventsure-server/application/episode"
+	"eventsure-server/application/indexer"
+	progressusecase "eventsure-server/application/progress"
+	"eventsure-server/infrastructure/alert"
+	"eventsure-server/infrastructure/config"
+	"eventsure-server/infrastructure/database/sqlstore"
+	"eventsure-server/infrastructure/storage"
+)
+
+// app wires the configuration, storage and use cases on first use,
+// so commands only open what they need
+type app struct {
+	opts  globalOptions
+	out   *output
+	cfg   *config.Config
+	repos *storage.Repositories
+	db    *sql.DB
+}
+
+// config loads the configuration; an invalid configuration is an error
+func (a *app) config() (*config.Config, error) {
+	if a.cfg != nil {
+		return a.cfg, nil
+	}
+	cfg, err := loadConfig(a.opts.configFile)
+	if err != nil {
+		return nil, errors.New("invalid configuration (see eventsure config check): " + err.Error())
+	}
+	a.cfg = cfg
+	return cfg, nil
+}
+
+// storage opens the repositories of the configured backend
+func (a *app) storage() (*config.Config, *storage.Repositories, error) {
+	cfg, err := a.config()
+	if err != nil {
+		return nil, nil, err
+	}
+	if a.repos == nil {
+		repos, err := storage.Open(cfg.Storage, cfg.Supabase)
+		if err != nil {
+			return nil, nil, errors.New("failed to open storage: " + err.Error())
+		}
+		slog.Debug("using storage", "backend", repos.Backend)
+		a.repos = repos
+	}
+	return cfg, a.repos, nil
+}
+
+// episodes creates the episode use case
+func (a *app) episodes() (*episodeusecase.UseCase, error) {
+	cfg, repos, err := a.storage()
+	if err != nil {
+		return nil, err
+	}
+	return episodeusecase.NewUseCase(repos.UserEpisodes, repos.Summaries, repos.Events, cfg.Etherscan, cfg.Contracts, cfg.Summaries), nil
+}
+
+// syncedEpisodes creates the episode use case and refreshes the episode summaries from chain first,
+// like the server's summaries worker. When the refresh fails the stored summaries are used, marked stale.
+func (a *app) syncedEpisodes(ctx context.Context) (*episodeusecase.UseCase, error) {
+	useCase, err := a.episodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := useCase.SyncSummaries(ctx); err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		slog.Warn("failed to sync episode summaries, using the stored ones", "error", err)
+	}
+	return useCase, nil
+}
+
+// indexer creates the event indexer
+func (a *app) indexer() (*indexer.Indexer, error) {
+	cfg, repos, err := a.storage()
+	if err != nil {
+		return nil, err
+	}
+	return indexer.NewIndexer(repos.Events, repos.Checkpoints, cfg.Indexer, cfg.Etherscan, cfg.Contracts), nil
+}
+
+// audit creates the audit use case; findings are alerted to the log only,
+// the command reports them itself
+func (a *app) audit() (*auditusecase.UseCase, error) {
+	cfg, repos, err := a.storage()
+	if err != nil {
+		return nil, err
+	}
+	ix, err := a.indexer()
+	if err != nil {
+		return nil, err
+	}
+	return auditusecase.NewUseCase(repos.Events, ix, alert.NewLogAlerter(), cfg.Etherscan, cfg.Contracts), nil
+}
+
+// progress creates the user episode progress use case
+func (a *app) progress() (*progressusecase.UseCase, error) {
+	_, repos, err := a.storage()
+	if err != nil {
+		return nil, err
+	}
+	return progressusecase.NewUseCase(repos.Events, repos.Checkpoints, repos.UserEpisodes), nil
+}
+
+// migrator opens the PostgreSQL or SQLite database of the configured storage without migrating it
+func (a *app) migrator() (*sqlstore.Migrator, error) {
+	cfg, err := a.config()
+	if err != nil {
+		return nil, err
+	}
+	db, dialect, err := storage.OpenDatabase(cfg.Storage.URL)
+	if err != nil {
+		return nil, errors.New("failed to open database: " + err.Error())
+	}
+	a.db = db
+	return sqlstore.NewMigrator(db, dialect)
+}
+
+// close releases the storage
+func (a *app) close() {
+	if a.repos != nil {
+		if err := a.repos.Close(); err != nil {
+			slog.Warn("failed to close storage", "error", err)
+		}
+	}
+	if a.db != nil {
+		if err := a.db.Close(); err != nil {
+			slog.Warn("failed to close database", "error", err)
+		}
+	}
+}
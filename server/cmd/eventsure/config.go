@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// configCheck prints each setting with its source, or the settings as a YAML file with -yaml,
+// and validates them. An invalid configuration is an error; missing required settings exit with exitUsage,
+// like the server that only starts without them in DEGRADED_MODE.
+func configCheck(_ context.Context, app *app, args []string) error {
+	fs := newFlagSet("config check")
+	asYAML := fs.Bool("yaml", false, "print the settings as a YAML config file (secrets masked)")
+	if err := parseFlags(fs, args, 0, 0); err != nil {
+		return err
+	}
+
+	cfg, loadErr := loadConfig(app.opts.configFile)
+
+	if *asYAML {
+		if err := cfg.WriteYAML(os.Stdout); err != nil {
+			return err
+		}
+	} else {
+		if path := cfg.File(); path != "" {
+			fmt.Fprintf(os.Stderr, "config file: %s\n", path)
+		}
+		if path := cfg.DotEnvFile(); path != "" {
+			fmt.Fprintf(os.Stderr, ".env file:   %s\n", path)
+		}
+
+		entries := cfg.Entries()
+		rows := make([][]string, len(entries))
+		for i, e := range entries {
+			rows[i] = []string{e.Key, e.Env, e.Source, e.Value}
+		}
+		if err := app.out.print(entries, []string{"KEY", "ENV", "SOURCE", "VALUE"}, rows); err != nil {
+			return err
+		}
+	}
+
+	if loadErr != nil {
+		return errors.New("invalid configuration:\n" + loadErr.Error())
+	}
+	if missing := cfg.Missing(); len(missing) > 0 {
+		fmt.Fprintln(os.Stderr, "\nmissing required settings (the server only starts with DEGRADED_MODE=true):")
+		for _, name := range missing {
+			fmt.Fprintf(os.Stderr, "  %s\n", name)
+		}
+		return errIncomplete
+	}
+	fmt.Fprintln(os.Stderr, "configuration is valid")
+	return nil
+}
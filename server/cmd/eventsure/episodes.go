@@ -0,0 +1,172 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	episodeusecase "eventsure-server/application/episode"
+	"eventsure-server/domain/chain"
+	eventsureepisode "eventsure-server/domain/episode"
+)
+
+// episodeHeader is the table header of episode summaries
+var episodeHeader = []string{"ADDRESS", "STATE", "CATEGORY", "FLIGHT", "DEPARTURE", "MEMBERS", "PREMIUM", "PAYOUT", "TVL"}
+
+// episodeRow returns the table row of an episode summary
+func episodeRow(e episodeusecase.EpisodeSummaryDTO) []string {
+	return []string{e.Address, e.State, e.Category, e.FlightName, e.DepartureTime, strconv.Itoa(e.MemberCount), e.PremiumAmount, e.PayoutAmount, e.TVL}
+}
+
+// episodesList lists episode summaries like GET /api/episodes
+func episodesList(ctx context.Context, app *app, args []string) error {
+	fs := newFlagSet("episodes list")
+	state := fs.String("state", "", "only episodes in the state (created, open, locked, resolved, settled, closed)")
+	category := fs.String("category", "", "only episodes of the category (flightDelay, weather, tripCancel)")
+	flight := fs.String("flight", "", "only episodes of the flight")
+	sortBy := fs.String("sort", string(eventsureepisode.SortByCreationBlock), "sort by created, departure or tvl")
+	order := fs.String("order", "desc", "sort order: asc or desc")
+	limit := fs.Int("limit", 20, "episodes per page, at most 100")
+	all := fs.Bool("all", false, "follow the cursor and list every page")
+	if err := parseFlags(fs, args, 0, 0); err != nil {
+		return err
+	}
+
+	req := episodeusecase.ListEpisodesRequest{
+		FlightName: *flight,
+		Limit:      *limit,
+	}
+	if *category != "" {
+		c, ok := eventsureepisode.ParseCategory(*category)
+		if !ok {
+			return usagef("invalid category: %s (expected flightDelay, weather or tripCancel)", *category)
+		}
+		req.Category = c
+	}
+	if *state != "" {
+		s, ok := eventsureepisode.ParseState(*state)
+		if !ok {
+			return usagef("invalid state: %s", *state)
+		}
+		req.State = s
+	}
+	field, ok := eventsureepisode.ParseSortField(*sortBy)
+	if !ok {
+		return usagef("invalid sort: %s (expected created, departure or tvl)", *sortBy)
+	}
+	req.SortBy = field
+	switch *order {
+	case "desc":
+		req.Descending = true
+	case "asc":
+	default:
+		return usagef("invalid order: %s (expected asc or desc)", *order)
+	}
+
+	useCase, err := app.syncedEpisodes(ctx)
+	if err != nil {
+		return err
+	}
+
+	response := &episodeusecase.ListEpisodesResponse{Episodes: []episodeusecase.EpisodeSummaryDTO{}}
+	for {
+		page, err := useCase.ListEpisodes(ctx, req)
+		if err != nil {
+			return err
+		}
+		response.Episodes = append(response.Episodes, page.Episodes...)
+		response.NextCursor = page.NextCursor
+		response.Stale = response.Stale || page.Stale
+		if !*all || page.NextCursor == "" {
+			break
+		}
+		req.Cursor = page.NextCursor
+	}
+
+	rows := make([][]string, len(response.Episodes))
+	for i, e := range response.Episodes {
+		rows[i] = episodeRow(e)
+	}
+	return app.out.print(response, episodeHeader, rows)
+}
+
+// episodesShow shows the summary of an episode
+func episodesShow(ctx context.Context, app *app, args []string) error {
+	fs := newFlagSet("episodes show")
+	if err := parseFlags(fs, args, 1, 1); err != nil {
+		return err
+	}
+	address, err := chain.ParseAddress(fs.Arg(0))
+	if err != nil {
+		return usagef("invalid episode: %v", err)
+	}
+
+	useCase, err := app.syncedEpisodes(ctx)
+	if err != nil {
+		return err
+	}
+	e, err := useCase.GetEpisode(ctx, address)
+	if err != nil {
+		return err
+	}
+
+	return app.out.print(e, []string{"FIELD", "VALUE"}, fields(
+		"address", e.Address,
+		"state", e.State,
+		"status", e.Status,
+		"category", e.Category,
+		"flightName", e.FlightName,
+		"departureTime", e.DepartureTime,
+		"estimatedArrivalTime", e.EstimatedArrivalTime,
+		"premiumAmount", e.PremiumAmount,
+		"payoutAmount", e.PayoutAmount,
+		"memberCount", strconv.Itoa(e.MemberCount),
+		"totalPremium", e.TotalPremium,
+		"tvl", e.TVL,
+		"signupStart", e.SignupStart,
+		"signupEnd", e.SignupEnd,
+		"creationBlock", strconv.FormatInt(e.CreationBlock, 10),
+		"updatedAt", e.UpdatedAt,
+		"stale", strconv.FormatBool(e.Stale),
+	))
+}
+
+// episodesLedger exports the ledger of an episode like GET /api/episodes/{episode}/ledger;
+// -o csv prints the same file. It exits with exitFindings when the ledger does not reconcile.
+func episodesLedger(ctx context.Context, app *app, args []string) error {
+	fs := newFlagSet("episodes ledger")
+	if err := parseFlags(fs, args, 1, 1); err != nil {
+		return err
+	}
+	address, err := chain.ParseAddress(fs.Arg(0))
+	if err != nil {
+		return usagef("invalid episode: %v", err)
+	}
+
+	useCase, err := app.episodes()
+	if err != nil {
+		return err
+	}
+	response, err := useCase.GetEpisodeLedger(ctx, address)
+	if err != nil {
+		return err
+	}
+
+	header := episodeusecase.LedgerCSVHeader
+	if app.out.format == formatTable {
+		header = make([]string, len(episodeusecase.LedgerCSVHeader))
+		for i, h := range episodeusecase.LedgerCSVHeader {
+			header[i] = strings.ToUpper(h)
+		}
+	}
+	if err := app.out.print(response, header, response.CSVRecords()); err != nil {
+		return err
+	}
+	if !response.Reconciliation.Reconciled {
+		slog.Warn("ledger does not reconcile with the on-chain balance",
+			"episode", response.Episode, "differenceWei", response.Reconciliation.DifferenceWei)
+		return errFindings
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	episodeusecase "eventsure-server/application/episode"
+	"eventsure-server/domain/chain"
+)
+
+// eventHeader is the table header of indexed events
+var eventHeader = []string{"SEQ", "BLOCK", "LOG", "TIMESTAMP", "EVENT", "MEMBER", "AMOUNT", "TX"}
+
+// eventRow returns the table row of an indexed event
+func eventRow(e episodeusecase.IndexedEventDTO) []string {
+	return []string{strconv.FormatInt(e.Sequence, 10), strconv.FormatInt(e.BlockNumber, 10), strconv.FormatInt(e.LogIndex, 10), e.Timestamp, e.Event, e.Member, e.Amount, e.TransactionHash}
+}
+
+// eventsTail prints the last indexed events of an episode. With -f it keeps polling the event store
+// and prints the events the indexer of the server stores meanwhile, one JSON object per line with -o json.
+func eventsTail(ctx context.Context, app *app, args []string) error {
+	fs := newFlagSet("events tail")
+	n := fs.Int("n", 20, "number of events to print first, 0 for all")
+	follow := fs.Bool("f", false, "keep printing new events until interrupted")
+	interval := fs.Duration("interval", 5*time.Second, "poll interval with -f")
+	if err := parseFlags(fs, args, 1, 1); err != nil {
+		return err
+	}
+	if *n < 0 {
+		return usagef("invalid -n: %d", *n)
+	}
+	if *interval <= 0 {
+		return usagef("invalid -interval: %s", *interval)
+	}
+	address, err := chain.ParseAddress(fs.Arg(0))
+	if err != nil {
+		return usagef("invalid episode: %v", err)
+	}
+
+	useCase, err := app.episodes()
+	if err != nil {
+		return err
+	}
+	response, err := useCase.GetIndexedEpisodeEvents(ctx, address)
+	if err != nil {
+		return err
+	}
+
+	events := response.Events
+	if *n > 0 && len(events) > *n {
+		events = events[len(events)-*n:]
+	}
+	if !*follow {
+		rows := make([][]string, len(events))
+		for i, e := range events {
+			rows[i] = eventRow(e)
+		}
+		return app.out.print(&episodeusecase.GetIndexedEpisodeEventsResponse{Episode: response.Episode, Events: events}, eventHeader, rows)
+	}
+
+	write := app.out.stream(eventHeader)
+	var last int64
+	emit := func(events []episodeusecase.IndexedEventDTO) error {
+		var values []any
+		var rows [][]string
+		for _, e := range events {
+			if e.Sequence <= last {
+				continue
+			}
+			last = e.Sequence
+			values = append(values, e)
+			rows = append(rows, eventRow(e))
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+		return write(values, rows)
+	}
+	if err := emit(events); err != nil {
+		return err
+	}
+	ticker := time.NewTicker(*interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		response, err := useCase.GetIndexedEpisodeEvents(ctx, address)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		if err := emit(response.Events); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"strconv"
+
+	"eventsure-server/domain/chain"
+)
+
+// indexStatusDTO is the JSON form of the indexing state of an episode
+type indexStatusDTO struct {
+	Episode    string `json:"episode"`
+	Checkpoint int64  `json:"checkpoint"`
+	Events     int    `json:"events"`
+}
+
+// indexBackfill indexes episodes up to the latest block, resuming from their checkpoints
+// or, with -reset, fetching their logs again from the start
+func indexBackfill(ctx context.Context, app *app, args []string) error {
+	fs := newFlagSet("index backfill")
+	reset := fs.Bool("reset", false, "clear the checkpoints first and fetch all logs again")
+	if err := parseFlags(fs, args, 0, -1); err != nil {
+		return err
+	}
+	var episodes []string
+	for _, arg := range fs.Args() {
+		address, err := chain.ParseAddress(arg)
+		if err != nil {
+			return usagef("invalid episode: %v", err)
+		}
+		episodes = append(episodes, address.String())
+	}
+
+	ix, err := app.indexer()
+	if err != nil {
+		return err
+	}
+	statuses, err := ix.Backfill(ctx, episodes, *reset)
+	if err != nil && len(statuses) == 0 {
+		return err
+	}
+
+	response := make([]indexStatusDTO, len(statuses))
+	rows := make([][]string, len(statuses))
+	for i, s := range statuses {
+		response[i] = indexStatusDTO{Episode: s.Episode, Checkpoint: s.Checkpoint, Events: s.Events}
+		rows[i] = []string{response[i].Episode, strconv.FormatInt(s.Checkpoint, 10), strconv.Itoa(s.Events)}
+	}
+	// The episodes indexed before a failure are printed too
+	if printErr := app.out.print(response, []string{"EPISODE", "CHECKPOINT", "EVENTS"}, rows); printErr != nil && err == nil {
+		err = printErr
+	}
+	return err
+}
@@ -0,0 +1,261 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"eventsure-server/infrastructure/config"
+	"eventsure-server/infrastructure/logging"
+)
+
+// 이 파일은 운영용 eventsure CLI의 진입점입니다.
+//
+// 실행 방법:
+//
+//	go run ./cmd/eventsure episodes list -state open
+//	go run ./cmd/eventsure -o json episodes show 0x1234...
+//	go run ./cmd/eventsure -config prod.yaml -storage postgres://... reconcile
+//	go run ./cmd/eventsure -storage sqlite://eventsure.db migrate status
+//	go run ./cmd/eventsure pricing -data arrivals.csv -route ICN-NRT -carrier KE -payout 50000000000000000
+//	go run ./cmd/eventsure simulate -premium 10000000000000000 -payout 50000000000000000 -members poisson:20 -p 0.15
+//	go build -o eventsure ./cmd/eventsure && ./eventsure help
+//
+// 서버와 같은 설정 (기본값 → YAML 파일 → .env → 환경 변수)과 같은 application 계층을 사용하므로,
+// -config와 -storage만 바꾸면 어느 환경에서든 서버가 보는 것과 같은 결과를 얻습니다.
+// 결과는 stdout에 -output 형식 (table, json, csv)으로, 로그는 stderr에 JSON으로 출력됩니다.
+//
+// 종료 코드: 0 성공, 1 실패, 2 잘못된 사용법, 3 reconcile/simulate의 불변식 위반 또는 episodes ledger의 잔액 불일치
+func main() {
+	os.Exit(run(os.Args[1:]))
+}
+
+// Exit codes
+const (
+	exitOK       = 0
+	exitError    = 1
+	exitUsage    = 2
+	exitFindings = 3
+)
+
+// command is a subcommand such as "episodes list"
+type command struct {
+	name    string
+	args    string
+	summary string
+	run     func(ctx context.Context, app *app, args []string) error
+}
+
+// usage returns the command name followed by its arguments
+func (c command) usage() string {
+	if c.args == "" {
+		return c.name
+	}
+	return c.name + " " + c.args
+}
+
+// commands are the subcommands in the order they are listed by help.
+// They are set by init, since the commands print their usage from the list.
+var commands []command
+
+func init() {
+	commands = []command{
+		{"episodes list", "[-state s] [-category c] [-flight f] [-sort created|departure|tvl] [-order asc|desc] [-limit n] [-all]", "list episode summaries", episodesList},
+		{"episodes show", "<episode>", "show the summary of an episode", episodesShow},
+		{"episodes ledger", "<episode>", "export the premiums, payouts and surplus withdrawals of an episode reconciled with its balance", episodesLedger},
+		{"events tail", "[-n 20] [-f] [-interval 5s] <episode>", "print the last indexed events of an episode, and follow new ones", eventsTail},
+		{"members list", "<episode>", "list the members of an episode with what they paid and received", membersList},
+		{"user-episodes add", "<user> <episode>", "register that a user joined an episode", userEpisodesAdd},
+		{"user-episodes list", "(-user u | -episode e) [-progress p]", "list the episodes of a user or the users of an episode", userEpisodesList},
+		{"index backfill", "[-reset] [episode...]", "index episodes up to the latest block, all factory episodes by default", indexBackfill},
+		{"reconcile", "[episode...]", "audit episodes against the chain and sync user episode progress", reconcile},
+		{"pricing", "[-data arrivals.csv] -route r -carrier c (-payout wei | -premium wei) [-confidence 0.95] [-loading 0.1] [-target-solvency 0.95]", "quote a premium/payout pair from historical flight arrivals", pricingQuote},
+		{"simulate", "-premium wei -payout wei -members fixed:N|uniform:MIN-MAX|poisson:MEAN -p probability [-trials n] [-seed n]", "simulate the solvency of episode parameters", simulate},
+		{"migrate up", "", "apply the pending schema migrations to the -storage database", migrateUp},
+		{"migrate down", "[steps]", "roll back the last schema migrations, one by default", migrateDown},
+		{"migrate status", "", "list the schema migrations and whether they are applied", migrateStatus},
+		{"config check", "[-yaml]", "print each setting with its source and validate the configuration", configCheck},
+	}
+}
+
+// usageError is returned for invalid arguments; it exits with exitUsage
+type usageError struct {
+	msg string
+}
+
+func (e *usageError) Error() string {
+	return e.msg
+}
+
+// usagef returns a usageError
+func usagef(format string, args ...any) error {
+	return &usageError{msg: fmt.Sprintf(format, args...)}
+}
+
+// errUsagePrinted is returned for invalid flags, after the flag package printed the error and the usage
+var errUsagePrinted = errors.New("invalid flags")
+
+// errIncomplete is returned by config check when required settings are missing; it exits with exitUsage
+var errIncomplete = errors.New("required settings are missing")
+
+// errFindings is returned by reconcile when an audit failed, by episodes ledger when the ledger
+// does not reconcile and by simulate when the parameters violate an invariant; it exits with exitFindings
+var errFindings = errors.New("invariant violations found")
+
+// globalOptions are the flags given before the subcommand
+type globalOptions struct {
+	configFile string
+	storage    string
+	output     string
+}
+
+func run(args []string) int {
+	var opts globalOptions
+	fs := flag.NewFlagSet("eventsure", flag.ContinueOnError)
+	fs.StringVar(&opts.configFile, "config", "", "YAML config file (default: CONFIG_FILE)")
+	fs.StringVar(&opts.storage, "storage", "", "storage backend, overriding STORAGE (postgres://..., sqlite://..., supabase or memory)")
+	fs.StringVar(&opts.output, "output", "table", "output format: table, json or csv")
+	fs.StringVar(&opts.output, "o", "table", "shorthand for -output")
+	fs.Usage = func() { printUsage(fs) }
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitUsage
+	}
+
+	out, err := newOutput(os.Stdout, opts.output)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitUsage
+	}
+
+	rest := fs.Args()
+	if len(rest) == 0 || rest[0] == "help" {
+		printUsage(fs)
+		if len(rest) == 0 {
+			return exitUsage
+		}
+		return exitOK
+	}
+	cmd, cmdArgs, ok := findCommand(rest)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command: %s\n\n", strings.Join(rest, " "))
+		printUsage(fs)
+		return exitUsage
+	}
+
+	// The flag overrides STORAGE like an environment variable, the highest precedence source
+	if opts.storage != "" {
+		os.Setenv("STORAGE", opts.storage)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	app := &app{opts: opts, out: out}
+	defer app.close()
+
+	err = cmd.run(ctx, app, cmdArgs)
+	switch {
+	case err == nil:
+		return exitOK
+	case errors.Is(err, errFindings):
+		return exitFindings
+	case errors.Is(err, errIncomplete), errors.Is(err, errUsagePrinted):
+		return exitUsage
+	case errors.As(err, new(*usageError)):
+		fmt.Fprintf(os.Stderr, "%v\nusage: eventsure %s\n", err, cmd.usage())
+		return exitUsage
+	case errors.Is(err, flag.ErrHelp):
+		return exitOK
+	case errors.Is(err, context.Canceled):
+		return exitOK
+	default:
+		fmt.Fprintln(os.Stderr, "error:", err)
+		return exitError
+	}
+}
+
+// findCommand finds the command named by the first one or two arguments
+func findCommand(args []string) (command, []string, bool) {
+	for _, cmd := range commands {
+		words := strings.Fields(cmd.name)
+		if len(args) < len(words) {
+			continue
+		}
+		if strings.Join(args[:len(words)], " ") == cmd.name {
+			return cmd, args[len(words):], true
+		}
+	}
+	return command{}, nil, false
+}
+
+// printUsage prints the global flags and the commands
+func printUsage(fs *flag.FlagSet) {
+	w := fs.Output()
+	fmt.Fprintln(w, "usage: eventsure [-config file.yaml] [-storage url] [-o table|json|csv] <command> [flags] [args]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "commands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-20s %s\n", cmd.name, cmd.summary)
+		fmt.Fprintf(w, "  %-20s   %s\n", "", cmd.usage())
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "flags:")
+	fs.PrintDefaults()
+}
+
+// newFlagSet creates the flag set of a command
+func newFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.Usage = func() {
+		for _, cmd := range commands {
+			if cmd.name == name {
+				fmt.Fprintf(fs.Output(), "usage: eventsure %s\n", cmd.usage())
+			}
+		}
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// parseFlags parses the command flags and checks the number of positional arguments
+func parseFlags(fs *flag.FlagSet, args []string, minArgs, maxArgs int) error {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return errUsagePrinted
+	}
+	if n := fs.NArg(); n < minArgs || (maxArgs >= 0 && n > maxArgs) {
+		switch {
+		case maxArgs == 0:
+			return usagef("unexpected argument: %s", fs.Arg(0))
+		case n < minArgs:
+			return usagef("missing argument")
+		default:
+			return usagef("too many arguments")
+		}
+	}
+	return nil
+}
+
+// loadConfig loads the configuration like the server and logs to stderr at LOG_LEVEL
+func loadConfig(file string) (*config.Config, error) {
+	cfg, err := config.Load(config.Options{File: file})
+	if cfg != nil {
+		level, levelErr := logging.ParseLevel(cfg.Log.Level)
+		slog.SetDefault(logging.New(os.Stderr, level))
+		if levelErr != nil {
+			slog.Warn(levelErr.Error())
+		}
+	}
+	return cfg, err
+}
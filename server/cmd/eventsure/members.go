@@ -0,0 +1,34 @@
+package main
+
+import (
+	"context"
+
+	"eventsure-server/domain/chain"
+)
+
+// membersList lists the members of an episode from its indexed events
+func membersList(ctx context.Context, app *app, args []string) error {
+	fs := newFlagSet("members list")
+	if err := parseFlags(fs, args, 1, 1); err != nil {
+		return err
+	}
+	address, err := chain.ParseAddress(fs.Arg(0))
+	if err != nil {
+		return usagef("invalid episode: %v", err)
+	}
+
+	useCase, err := app.episodes()
+	if err != nil {
+		return err
+	}
+	response, err := useCase.GetEpisodeMembers(ctx, address)
+	if err != nil {
+		return err
+	}
+
+	rows := make([][]string, len(response.Members))
+	for i, m := range response.Members {
+		rows[i] = []string{m.Member, m.PremiumPaid, m.PayoutReceived, m.SurplusReceived, m.JoinedAt, m.JoinTransaction}
+	}
+	return app.out.print(response, []string{"MEMBER", "PREMIUM", "PAYOUT", "SURPLUS", "JOINED", "JOIN TX"}, rows)
+}
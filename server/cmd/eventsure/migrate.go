@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"eventsure-server/infrastructure/database/sqlstore"
+)
+
+// migrationHeader is the table header of applied or reverted migrations
+var migrationHeader = []string{"VERSION", "NAME"}
+
+// migrationDTO is the JSON form of an applied or reverted migration
+type migrationDTO struct {
+	Version int    `json:"version"`
+	Name    string `json:"name"`
+}
+
+// migrationStatusDTO is the JSON form of the status of a migration
+type migrationStatusDTO struct {
+	Version   int     `json:"version"`
+	Name      string  `json:"name"`
+	Applied   bool    `json:"applied"`
+	AppliedAt *string `json:"appliedAt"`
+}
+
+// printMigrations prints the migrations applied or reverted before err, if any, and returns err
+func printMigrations(app *app, migrations []sqlstore.Migration, err error) error {
+	response := make([]migrationDTO, len(migrations))
+	rows := make([][]string, len(migrations))
+	for i, m := range migrations {
+		response[i] = migrationDTO{Version: m.Version, Name: m.Name}
+		rows[i] = []string{fmt.Sprintf("%04d", m.Version), m.Name}
+	}
+	if printErr := app.out.print(response, migrationHeader, rows); printErr != nil && err == nil {
+		err = printErr
+	}
+	return err
+}
+
+// migrateUp applies the pending migrations to the -storage database
+func migrateUp(_ context.Context, app *app, args []string) error {
+	fs := newFlagSet("migrate up")
+	if err := parseFlags(fs, args, 0, 0); err != nil {
+		return err
+	}
+
+	migrator, err := app.migrator()
+	if err != nil {
+		return err
+	}
+	applied, err := migrator.Up()
+	if err == nil && len(applied) == 0 {
+		slog.Info("no pending migrations")
+	}
+	return printMigrations(app, applied, err)
+}
+
+// migrateDown rolls back the last steps migrations, one by default
+func migrateDown(_ context.Context, app *app, args []string) error {
+	fs := newFlagSet("migrate down")
+	if err := parseFlags(fs, args, 0, 1); err != nil {
+		return err
+	}
+	steps := 1
+	if fs.NArg() == 1 {
+		n, err := strconv.Atoi(fs.Arg(0))
+		if err != nil || n < 1 {
+			return usagef("invalid steps: %s", fs.Arg(0))
+		}
+		steps = n
+	}
+
+	migrator, err := app.migrator()
+	if err != nil {
+		return err
+	}
+	reverted, err := migrator.Down(steps)
+	if err == nil && len(reverted) == 0 {
+		slog.Info("no applied migrations")
+	}
+	return printMigrations(app, reverted, err)
+}
+
+// migrateStatus lists the migrations and whether they are applied
+func migrateStatus(_ context.Context, app *app, args []string) error {
+	fs := newFlagSet("migrate status")
+	if err := parseFlags(fs, args, 0, 0); err != nil {
+		return err
+	}
+
+	migrator, err := app.migrator()
+	if err != nil {
+		return err
+	}
+	statuses, err := migrator.Status()
+	if err != nil {
+		return err
+	}
+
+	response := make([]migrationStatusDTO, len(statuses))
+	rows := make([][]string, len(statuses))
+	for i, s := range statuses {
+		response[i] = migrationStatusDTO{Version: s.Version, Name: s.Name, Applied: s.Applied}
+		appliedAt := ""
+		if s.AppliedAt != nil {
+			appliedAt = s.AppliedAt.UTC().Format(time.RFC3339)
+			response[i].AppliedAt = &appliedAt
+		}
+		rows[i] = []string{fmt.Sprintf("%04d", s.Version), s.Name, strconv.FormatBool(s.Applied), appliedAt}
+	}
+	return app.out.print(response, []string{"VERSION", "NAME", "APPLIED", "APPLIED AT"}, rows)
+}
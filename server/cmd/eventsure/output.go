@@ -0,0 +1,103 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+// Output formats
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+	formatCSV   = "csv"
+)
+
+// output writes command results in the format selected by -output.
+// JSON prints the response DTO as the HTTP API returns it; table and CSV print rows.
+type output struct {
+	w      io.Writer
+	format string
+}
+
+// newOutput creates an output for format
+func newOutput(w io.Writer, format string) (*output, error) {
+	switch format {
+	case formatTable, formatJSON, formatCSV:
+		return &output{w: w, format: format}, nil
+	}
+	return nil, fmt.Errorf("invalid output format: %s (expected table, json or csv)", format)
+}
+
+// print writes v as JSON, or header and rows as a table or CSV
+func (o *output) print(v any, header []string, rows [][]string) error {
+	switch o.format {
+	case formatJSON:
+		encoder := json.NewEncoder(o.w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(v)
+	case formatCSV:
+		w := csv.NewWriter(o.w)
+		w.Write(header)
+		w.WriteAll(rows)
+		return w.Error()
+	default:
+		return o.table(header, rows)
+	}
+}
+
+// stream returns a function that writes batches of a stream, such as followed events.
+// JSON prints one object per line, table and CSV print the header before the first batch.
+func (o *output) stream(header []string) func(values []any, rows [][]string) error {
+	started := false
+	return func(values []any, rows [][]string) error {
+		switch o.format {
+		case formatJSON:
+			encoder := json.NewEncoder(o.w)
+			for _, v := range values {
+				if err := encoder.Encode(v); err != nil {
+					return err
+				}
+			}
+			return nil
+		case formatCSV:
+			w := csv.NewWriter(o.w)
+			if !started {
+				w.Write(header)
+			}
+			started = true
+			w.WriteAll(rows)
+			return w.Error()
+		default:
+			if !started {
+				started = true
+				return o.table(header, rows)
+			}
+			return o.table(nil, rows)
+		}
+	}
+}
+
+// table writes header and rows as aligned columns
+func (o *output) table(header []string, rows [][]string) error {
+	w := tabwriter.NewWriter(o.w, 0, 0, 2, ' ', 0)
+	if header != nil {
+		fmt.Fprintln(w, strings.Join(header, "\t"))
+	}
+	for _, row := range rows {
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+	return w.Flush()
+}
+
+// fields returns the rows of a FIELD/VALUE table of a single record
+func fields(pairs ...string) [][]string {
+	rows := make([][]string, 0, len(pairs)/2)
+	for i := 0; i+1 < len(pairs); i += 2 {
+		rows = append(rows, []string{pairs[i], pairs[i+1]})
+	}
+	return rows
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	pricingusecase "eventsure-server/application/pricing"
+	"eventsure-server/domain/pricing"
+	"eventsure-server/infrastructure/flightdata"
+)
+
+// pricingQuote quotes a premium/payout pair from historical arrivals like POST /api/pricing/quote.
+// The dataset is -data or PRICING_DATASET, in the CSV format of flightdata.LoadArrivalsCSV.
+func pricingQuote(_ context.Context, app *app, args []string) error {
+	fs := newFlagSet("pricing")
+	dataPath := fs.String("data", "", "historical arrivals CSV (default: PRICING_DATASET)")
+	route := fs.String("route", "", "route, e.g. ICN-NRT")
+	carrier := fs.String("carrier", "", "carrier code, e.g. KE")
+	payout := fs.String("payout", "", "payout per member in wei (derive the premium)")
+	premium := fs.String("premium", "", "premium per member in wei (derive the payout)")
+	confidence := fs.Float64("confidence", pricingusecase.DefaultConfidence, "probability that the true delay probability does not exceed the priced one")
+	loading := fs.Float64("loading", 0, "safety loading on top of the expected loss, e.g. 0.1 for 10%")
+	targetSolvency := fs.Float64("target-solvency", 0, "least probability that the pool pays every claim, e.g. 0.95 (0 for none)")
+	if err := parseFlags(fs, args, 0, 0); err != nil {
+		return err
+	}
+
+	cfg, err := app.config()
+	if err != nil {
+		return err
+	}
+	if *dataPath == "" {
+		*dataPath = cfg.Pricing.Dataset
+	}
+	if *dataPath == "" {
+		return usagef("-data or PRICING_DATASET is required")
+	}
+
+	result, err := flightdata.LoadArrivalsCSV(*dataPath)
+	if err != nil {
+		return fmt.Errorf("failed to load %s: %w", *dataPath, err)
+	}
+	slog.Info("loaded flight arrivals", "path", *dataPath, "arrivals", len(result.Arrivals), "skipped", result.Skipped)
+
+	useCase := pricingusecase.NewUseCase(pricing.NewModel(result.Arrivals))
+	q, err := useCase.Quote(pricingusecase.QuoteRequest{
+		Route:          *route,
+		Carrier:        *carrier,
+		PayoutAmount:   *payout,
+		PremiumAmount:  *premium,
+		Confidence:     *confidence,
+		Loading:        *loading,
+		TargetSolvency: *targetSolvency,
+	})
+	if err != nil {
+		return err
+	}
+
+	return app.out.print(q, []string{"FIELD", "VALUE"}, fields(
+		"route", q.Route,
+		"carrier", q.Carrier,
+		"estimateLevel", q.EstimateLevel,
+		"observations", strconv.Itoa(q.Observations),
+		"delayed", strconv.Itoa(q.Delayed),
+		"delayProbability", formatFloat(q.DelayProbability),
+		"pricedProbability", formatFloat(q.PricedProbability),
+		"confidence", formatFloat(q.Confidence),
+		"loading", formatFloat(q.Loading),
+		"targetSolvency", formatFloat(q.TargetSolvency),
+		"premiumAmount", q.PremiumAmount,
+		"payoutAmount", q.PayoutAmount,
+		"expectedLossRatio", formatFloat(q.ExpectedLossRatio),
+		"coverageRatio", formatFloat(q.CoverageRatio),
+		"solvency", formatFloat(q.Solvency),
+		"warnings", strings.Join(q.Warnings, "; "),
+	))
+}
+
+// formatFloat formats a probability or ratio for table and CSV output
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	auditusecase "eventsure-server/application/audit"
+	"eventsure-server/domain/chain"
+)
+
+// reconcileResponse is the JSON form of a reconciliation
+type reconcileResponse struct {
+	Episodes []auditusecase.AuditResponse `json:"episodes"`
+	Passed   bool                         `json:"passed"`
+}
+
+// reconcile indexes and audits episodes against their on-chain state, then brings the progress
+// of the user episodes in step with the stored events. It exits with exitFindings when an audit fails.
+func reconcile(ctx context.Context, app *app, args []string) error {
+	fs := newFlagSet("reconcile")
+	if err := parseFlags(fs, args, 0, -1); err != nil {
+		return err
+	}
+	var addresses []chain.Address
+	for _, arg := range fs.Args() {
+		address, err := chain.ParseAddress(arg)
+		if err != nil {
+			return usagef("invalid episode: %v", err)
+		}
+		addresses = append(addresses, address)
+	}
+
+	if len(addresses) == 0 {
+		episodes, err := app.episodes()
+		if err != nil {
+			return err
+		}
+		all, err := episodes.GetAllEpisodes(ctx)
+		if err != nil {
+			return err
+		}
+		for _, s := range all.Episodes {
+			address, err := chain.ParseAddress(s)
+			if err != nil {
+				return errors.New("failed to parse factory episode: " + err.Error())
+			}
+			addresses = append(addresses, address)
+		}
+	}
+
+	audit, err := app.audit()
+	if err != nil {
+		return err
+	}
+	response := reconcileResponse{Episodes: make([]auditusecase.AuditResponse, 0, len(addresses)), Passed: true}
+	rows := make([][]string, 0, len(addresses))
+	for _, address := range addresses {
+		report, err := audit.AuditEpisode(ctx, address)
+		if err != nil {
+			return err
+		}
+		response.Episodes = append(response.Episodes, *report)
+		response.Passed = response.Passed && report.Passed
+
+		rules := make([]string, len(report.Findings))
+		for i, f := range report.Findings {
+			rules[i] = f.Rule
+		}
+		rows = append(rows, []string{report.Episode, report.State, strconv.Itoa(report.Events), report.TotalPremium, report.Balance,
+			strconv.FormatBool(report.Passed), strings.Join(rules, ",")})
+	}
+
+	progress, err := app.progress()
+	if err != nil {
+		return err
+	}
+	if err := progress.SyncOnce(ctx); err != nil {
+		return errors.New("failed to sync user episode progress: " + err.Error())
+	}
+	slog.Info("synced user episode progress")
+
+	if err := app.out.print(response, []string{"EPISODE", "STATE", "EVENTS", "PREMIUM", "BALANCE", "PASSED", "FINDINGS"}, rows); err != nil {
+		return err
+	}
+	if !response.Passed {
+		return errFindings
+	}
+	return nil
+}
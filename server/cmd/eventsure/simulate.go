@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+
+	simulationusecase "eventsure-server/application/simulation"
+	"eventsure-server/domain/simulation"
+)
+
+// simulate runs a Monte Carlo solvency simulation of episode parameters. It exits with exitFindings
+// when the parameters violate Invariant 2 or 5 of docs/2_spec.md in some of the trials.
+func simulate(_ context.Context, app *app, args []string) error {
+	fs := newFlagSet("simulate")
+	premium := fs.String("premium", "", "premium per member in wei")
+	payout := fs.String("payout", "", "payout per member in wei")
+	members := fs.String("members", "", "member count distribution: fixed:N, uniform:MIN-MAX or poisson:MEAN (at most 1000000)")
+	probability := fs.Float64("p", 0, "probability that the insured event occurs")
+	trials := fs.Int("trials", simulation.DefaultTrials, "number of trials")
+	seed := fs.Int64("seed", 1, "random seed")
+	if err := parseFlags(fs, args, 0, 0); err != nil {
+		return err
+	}
+	if *members == "" {
+		return usagef("-members is required")
+	}
+
+	// The simulation needs no settings, but the configuration sets up logging like the other commands
+	if _, err := app.config(); err != nil {
+		return err
+	}
+
+	useCase := simulationusecase.NewUseCase()
+	response, err := useCase.Simulate(simulationusecase.SimulateRequest{
+		PremiumAmount:    *premium,
+		PayoutAmount:     *payout,
+		Members:          *members,
+		EventProbability: *probability,
+		Trials:           *trials,
+		Seed:             *seed,
+	})
+	if err != nil {
+		return err
+	}
+
+	rows := [][]string{
+		{"members", "", formatFloat(response.MeanMembers), "", "", "", ""},
+		distributionRow("payoutShortfall", formatFloat(response.ShortfallProbability), response.PayoutShortfall),
+		distributionRow("failedClaims", "", response.FailedClaims),
+		distributionRow("surplusPerMember", "", response.SurplusPerMember),
+		distributionRow("strandedFunds", "", response.StrandedFunds),
+	}
+	if err := app.out.print(response, []string{"QUANTITY", "PROBABILITY", "MEAN", "P50", "P95", "P99", "MAX"}, rows); err != nil {
+		return err
+	}
+
+	for _, v := range response.Violations {
+		slog.Warn("invariant violated", "invariant", v.Invariant, "trials", v.Trials,
+			"probability", v.Probability, "description", v.Description)
+	}
+	if len(response.Violations) > 0 {
+		return errFindings
+	}
+	return nil
+}
+
+// distributionRow returns the table row of a simulated distribution
+func distributionRow(name, probability string, d simulationusecase.DistributionDTO) []string {
+	return []string{name, probability, formatFloat(d.Mean), formatFloat(d.P50), formatFloat(d.P95), formatFloat(d.P99), formatFloat(d.Max)}
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"strconv"
+
+	episodeusecase "eventsure-server/application/episode"
+	"eventsure-server/domain/chain"
+	"eventsure-server/domain/membership"
+)
+
+// userEpisodeHeader is the table header of user episodes
+var userEpisodeHeader = []string{"ID", "USER", "EPISODE", "PROGRESS", "CREATED"}
+
+// userEpisodeRow returns the table row of a user episode
+func userEpisodeRow(u episodeusecase.UserEpisodeDTO) []string {
+	progress := ""
+	if u.Progress != nil {
+		progress = *u.Progress
+	}
+	return []string{strconv.FormatInt(u.ID, 10), u.User, u.Episode, progress, u.CreatedAt}
+}
+
+// userEpisodesAdd registers a user episode like POST /api/user-episodes; adding it twice is not an error
+func userEpisodesAdd(ctx context.Context, app *app, args []string) error {
+	fs := newFlagSet("user-episodes add")
+	if err := parseFlags(fs, args, 2, 2); err != nil {
+		return err
+	}
+	user, err := chain.ParseAddress(fs.Arg(0))
+	if err != nil {
+		return usagef("invalid user: %v", err)
+	}
+	episode, err := chain.ParseAddress(fs.Arg(1))
+	if err != nil {
+		return usagef("invalid episode: %v", err)
+	}
+
+	useCase, err := app.episodes()
+	if err != nil {
+		return err
+	}
+	response, _, err := useCase.CreateUserEpisode(ctx, episodeusecase.CreateUserEpisodeRequest{User: user, Episode: episode})
+	if err != nil {
+		return err
+	}
+
+	return app.out.print(response, userEpisodeHeader, [][]string{userEpisodeRow(episodeusecase.UserEpisodeDTO(*response))})
+}
+
+// userEpisodesList lists the episodes of a user or the users of an episode
+func userEpisodesList(ctx context.Context, app *app, args []string) error {
+	fs := newFlagSet("user-episodes list")
+	userFlag := fs.String("user", "", "list the episodes of the user")
+	episodeFlag := fs.String("episode", "", "list the users of the episode")
+	progressFlag := fs.String("progress", "", "only user episodes at the progress (joined, locked, resolved, payout_claimable, surplus_claimable, claimed, withdrawn, closed)")
+	if err := parseFlags(fs, args, 0, 0); err != nil {
+		return err
+	}
+	if (*userFlag == "") == (*episodeFlag == "") {
+		return usagef("exactly one of -user and -episode is required")
+	}
+	var progress membership.Progress
+	if *progressFlag != "" {
+		p, ok := membership.ParseProgress(*progressFlag)
+		if !ok {
+			return usagef("invalid progress: %s", *progressFlag)
+		}
+		progress = p
+	}
+
+	useCase, err := app.episodes()
+	if err != nil {
+		return err
+	}
+
+	var response any
+	var userEpisodes []episodeusecase.UserEpisodeDTO
+	if *userFlag != "" {
+		user, err := chain.ParseAddress(*userFlag)
+		if err != nil {
+			return usagef("invalid user: %v", err)
+		}
+		r, err := useCase.GetUserEpisodes(ctx, user, progress)
+		if err != nil {
+			return err
+		}
+		response, userEpisodes = r, r.Episodes
+	} else {
+		episode, err := chain.ParseAddress(*episodeFlag)
+		if err != nil {
+			return usagef("invalid episode: %v", err)
+		}
+		r, err := useCase.GetEpisodeUsers(ctx, episode, progress)
+		if err != nil {
+			return err
+		}
+		response, userEpisodes = r, r.Users
+	}
+
+	rows := make([][]string, len(userEpisodes))
+	for i, u := range userEpisodes {
+		rows[i] = userEpisodeRow(u)
+	}
+	return app.out.print(response, userEpisodeHeader, rows)
+}
@@ -0,0 +1,217 @@
+package apikey
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+)
+
+// Scope is a permission granted to an API key
+type Scope string
+
+const (
+	// ScopeRead allows GET requests
+	ScopeRead Scope = "read"
+	// ScopeWrite allows POST and PATCH requests
+	ScopeWrite Scope = "write"
+	// ScopeAdmin allows the admin endpoints, and implies read and write
+	ScopeAdmin Scope = "admin"
+)
+
+// Scopes are all scopes, in the order they are listed
+var Scopes = []Scope{ScopeRead, ScopeWrite, ScopeAdmin}
+
+// ParseScope parses a scope name
+func ParseScope(s string) (Scope, bool) {
+	for _, scope := range Scopes {
+		if string(scope) == s {
+			return scope, true
+		}
+	}
+	return "", false
+}
+
+const (
+	// secretPrefix starts every secret, so leaked keys are easy to recognize
+	secretPrefix = "esk_"
+	// prefixLength is the length of the secret prefix kept to identify a key, e.g. esk_1a2b3c4d
+	prefixLength = len(secretPrefix) + 8
+	// maxNameLength bounds the partner name
+	maxNameLength = 100
+)
+
+// ErrInvalidName is returned by NewAPIKey for an empty or overlong name
+var ErrInvalidName = errors.New("name must be 1 to 100 characters")
+
+// ErrNoScopes is returned by NewAPIKey when no scope is granted
+var ErrNoScopes = errors.New("at least one scope is required")
+
+// ErrInvalidRateLimit is returned by NewAPIKey for a negative rate limit
+var ErrInvalidRateLimit = errors.New("rate limit must not be negative")
+
+// APIKey is the Aggregate Root of a partner's API key.
+// Only the SHA-256 hash of the secret is kept; the secret is shown once when the key is issued.
+type APIKey struct {
+	id        int64
+	name      string
+	prefix    string
+	hash      string
+	scopes    []Scope
+	rateLimit int
+	createdAt time.Time
+	revokedAt time.Time
+}
+
+// NewAPIKey creates a new, not yet persisted API key and returns it with its secret.
+// rateLimit is the number of requests per minute, or 0 for the default limit of API keys.
+func NewAPIKey(name string, scopes []Scope, rateLimit int) (*APIKey, string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || len(name) > maxNameLength {
+		return nil, "", ErrInvalidName
+	}
+	if len(scopes) == 0 {
+		return nil, "", ErrNoScopes
+	}
+	if rateLimit < 0 {
+		return nil, "", ErrInvalidRateLimit
+	}
+
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return nil, "", err
+	}
+	secret := secretPrefix + hex.EncodeToString(b)
+
+	return &APIKey{
+		name:      name,
+		prefix:    secret[:prefixLength],
+		hash:      HashSecret(secret),
+		scopes:    uniqueScopes(scopes),
+		rateLimit: rateLimit,
+		createdAt: time.Now().UTC(),
+	}, secret, nil
+}
+
+// RestoreAPIKey rebuilds a persisted API key from storage; revokedAt is zero for an active key
+func RestoreAPIKey(id int64, name, prefix, hash string, scopes []Scope, rateLimit int, createdAt, revokedAt time.Time) *APIKey {
+	return &APIKey{
+		id:        id,
+		name:      name,
+		prefix:    prefix,
+		hash:      hash,
+		scopes:    scopes,
+		rateLimit: rateLimit,
+		createdAt: createdAt,
+		revokedAt: revokedAt,
+	}
+}
+
+// HashSecret returns the hex SHA-256 hash under which a secret is stored and looked up
+func HashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// LooksLikeSecret reports whether s has the form of an API key secret
+func LooksLikeSecret(s string) bool {
+	return strings.HasPrefix(s, secretPrefix) && len(s) > prefixLength
+}
+
+// ID returns the API key ID (zero until persisted)
+func (k *APIKey) ID() int64 {
+	return k.id
+}
+
+// Name returns the name of the partner the key was issued to
+func (k *APIKey) Name() string {
+	return k.name
+}
+
+// Prefix returns the first characters of the secret, which identify the key without revealing it
+func (k *APIKey) Prefix() string {
+	return k.prefix
+}
+
+// Hash returns the SHA-256 hash of the secret
+func (k *APIKey) Hash() string {
+	return k.hash
+}
+
+// Scopes returns the granted scopes
+func (k *APIKey) Scopes() []Scope {
+	return k.scopes
+}
+
+// HasScope reports whether the key grants scope; admin grants every scope
+func (k *APIKey) HasScope(scope Scope) bool {
+	for _, s := range k.scopes {
+		if s == scope || s == ScopeAdmin {
+			return true
+		}
+	}
+	return false
+}
+
+// RateLimit returns the requests per minute allowed to the key, 0 for the default
+func (k *APIKey) RateLimit() int {
+	return k.rateLimit
+}
+
+// CreatedAt returns when the key was issued
+func (k *APIKey) CreatedAt() time.Time {
+	return k.createdAt
+}
+
+// RevokedAt returns when the key was revoked, zero while it is active
+func (k *APIKey) RevokedAt() time.Time {
+	return k.revokedAt
+}
+
+// Revoked reports whether the key was revoked
+func (k *APIKey) Revoked() bool {
+	return !k.revokedAt.IsZero()
+}
+
+// Revoke revokes the key; revoking a revoked key keeps the first revocation time
+func (k *APIKey) Revoke(at time.Time) {
+	if k.revokedAt.IsZero() {
+		k.revokedAt = at.UTC()
+	}
+}
+
+// uniqueScopes returns scopes without duplicates, in the order of Scopes
+func uniqueScopes(scopes []Scope) []Scope {
+	var result []Scope
+	for _, scope := range Scopes {
+		for _, s := range scopes {
+			if s == scope {
+				result = append(result, scope)
+				break
+			}
+		}
+	}
+	return result
+}
+
+// JoinScopes returns scopes as a comma-separated list, the form in which they are stored
+func JoinScopes(scopes []Scope) string {
+	names := make([]string, len(scopes))
+	for i, scope := range scopes {
+		names[i] = string(scope)
+	}
+	return strings.Join(names, ",")
+}
+
+// SplitScopes parses a comma-separated list of scopes, skipping unknown names
+func SplitScopes(s string) []Scope {
+	var scopes []Scope
+	for _, name := range strings.Split(s, ",") {
+		if scope, ok := ParseScope(strings.TrimSpace(name)); ok {
+			scopes = append(scopes, scope)
+		}
+	}
+	return scopes
+}
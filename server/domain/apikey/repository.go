@@ -0,0 +1,20 @@
+package apikey
+
+import (
+	"context"
+)
+
+// Repository defines the interface for APIKey repository.
+// Keys are looked up by the hash of their secret, which is unique.
+type Repository interface {
+	// Create persists a new APIKey and returns it with its assigned ID
+	Create(ctx context.Context, key *APIKey) (*APIKey, error)
+	// Update persists the revocation of an existing APIKey
+	Update(ctx context.Context, key *APIKey) error
+	// FindByID returns nil when no APIKey has the ID
+	FindByID(ctx context.Context, id int64) (*APIKey, error)
+	// FindByHash returns nil when no APIKey has the secret hash
+	FindByHash(ctx context.Context, hash string) (*APIKey, error)
+	// FindAll returns every APIKey, revoked ones included, oldest first
+	FindAll(ctx context.Context) ([]*APIKey, error)
+}
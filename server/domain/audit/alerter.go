@@ -0,0 +1,6 @@
+package audit
+
+// Alerter delivers alerts for newly detected invariant violations
+type Alerter interface {
+	Alert(report *Report, findings []Finding) error
+}
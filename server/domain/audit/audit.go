@@ -0,0 +1,254 @@
+package audit
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+
+	"eventsure-server/domain/episode"
+	"eventsure-server/domain/event"
+)
+
+// Rule identifies a single check of a docs/2_spec.md invariant
+type Rule string
+
+const (
+	// Invariant 1 — 에피소드 간 자금 분리
+	RuleOutflowWithinPremium Rule = "outflow-within-premium"
+	RuleJoinsMatchPremium    Rule = "joins-match-total-premium"
+	RuleBalanceReconciles    Rule = "balance-reconciles"
+	// Invariant 2 — 손실 한도 고정
+	RuleJoinPremium Rule = "join-premium-equals-premium-amount"
+	RuleSingleJoin  Rule = "single-join-per-member"
+	// Invariant 3 — 보장 조건 불변
+	RulePayoutAmount Rule = "payout-equals-payout-amount"
+	// Invariant 4 — 결정론적 결과 확정
+	RuleSingleResolution   Rule = "single-resolution"
+	RuleClaimMatchesResult Rule = "claim-matches-outcome"
+	// Invariant 5 — 조합원 전용 잉여금
+	RuleMemberOnly  Rule = "claims-by-members-only"
+	RuleSingleClaim Rule = "single-claim-per-member"
+	RuleProRata     Rule = "surplus-pro-rata"
+	// Invariant 6 — 단일 정산
+	RuleSingleSettlement  Rule = "single-settlement"
+	RuleSettlementBalance Rule = "settlement-balanced"
+	RuleClaimAfterSettle  Rule = "claims-after-settlement"
+)
+
+// invariants maps each rule to the invariant it checks
+var invariants = map[Rule]int{
+	RuleOutflowWithinPremium: 1,
+	RuleJoinsMatchPremium:    1,
+	RuleBalanceReconciles:    1,
+	RuleJoinPremium:          2,
+	RuleSingleJoin:           2,
+	RulePayoutAmount:         3,
+	RuleSingleResolution:     4,
+	RuleClaimMatchesResult:   4,
+	RuleMemberOnly:           5,
+	RuleSingleClaim:          5,
+	RuleProRata:              5,
+	RuleSingleSettlement:     6,
+	RuleSettlementBalance:    6,
+	RuleClaimAfterSettle:     6,
+}
+
+// Invariant returns the docs/2_spec.md invariant number the rule checks
+func (r Rule) Invariant() int {
+	return invariants[r]
+}
+
+// Snapshot is the on-chain state of an episode at audit time
+type Snapshot struct {
+	PremiumAmount *big.Int
+	PayoutAmount  *big.Int
+	State         episode.State
+	TotalPremium  *big.Int
+	TotalPayout   *big.Int
+	Surplus       *big.Int
+	EventOccurred bool
+	Balance       *big.Int
+}
+
+// Finding is a violation of an invariant rule.
+// TransactionHash is set when the violation is tied to a single event.
+type Finding struct {
+	Rule            Rule
+	Message         string
+	TransactionHash string
+}
+
+// Key identifies the finding across audits, so the same violation is alerted only once
+func (f Finding) Key() string {
+	return string(f.Rule) + "|" + f.TransactionHash + "|" + f.Message
+}
+
+// Report is the outcome of auditing one episode
+type Report struct {
+	Episode   string
+	AuditedAt time.Time
+	Events    int
+	Findings  []Finding
+}
+
+// Passed reports whether no invariant was violated
+func (r *Report) Passed() bool {
+	return len(r.Findings) == 0
+}
+
+// auditor accumulates findings while replaying events
+type auditor struct {
+	findings []Finding
+}
+
+func (a *auditor) fail(rule Rule, txHash, format string, args ...interface{}) {
+	a.findings = append(a.findings, Finding{
+		Rule:            rule,
+		Message:         fmt.Sprintf(format, args...),
+		TransactionHash: txHash,
+	})
+}
+
+// Audit replays the decoded events of an episode, in chain order, against its on-chain
+// snapshot and checks the core invariants of docs/2_spec.md
+func Audit(address string, snapshot Snapshot, events []*event.Event) *Report {
+	a := &auditor{}
+
+	premiumOf := make(map[string]*big.Int)
+	payoutClaimed := make(map[string]bool)
+	surplusClaimed := make(map[string]bool)
+	joined := new(big.Int)
+	outflow := new(big.Int)
+
+	var (
+		resolved *event.Event
+		settled  *event.Event
+	)
+
+	for _, e := range events {
+		switch e.Name {
+		case event.NameMemberJoined:
+			if e.Amount.Cmp(snapshot.PremiumAmount) != 0 {
+				a.fail(RuleJoinPremium, e.TransactionHash,
+					"member %s joined with %s wei, premium amount is %s wei", e.Member, e.Amount, snapshot.PremiumAmount)
+			}
+			if _, ok := premiumOf[e.Member]; ok {
+				a.fail(RuleSingleJoin, e.TransactionHash, "member %s joined more than once", e.Member)
+				premiumOf[e.Member].Add(premiumOf[e.Member], e.Amount)
+			} else {
+				premiumOf[e.Member] = new(big.Int).Set(e.Amount)
+			}
+			joined.Add(joined, e.Amount)
+
+		case event.NameEpisodeResolved:
+			if resolved != nil {
+				a.fail(RuleSingleResolution, e.TransactionHash, "episode resolved more than once")
+			}
+			resolved = e
+
+		case event.NameEpisodeSettled:
+			if settled != nil {
+				a.fail(RuleSingleSettlement, e.TransactionHash, "episode settled more than once")
+				continue
+			}
+			settled = e
+			total := new(big.Int).Add(e.TotalPayout, e.Surplus)
+			if total.Cmp(joined) != 0 {
+				a.fail(RuleSettlementBalance, e.TransactionHash,
+					"settled total payout %s + surplus %s does not equal premiums joined %s", e.TotalPayout, e.Surplus, joined)
+			}
+
+		case event.NamePayoutClaimed:
+			a.checkClaim(e, premiumOf, payoutClaimed, settled, resolved, true)
+			if e.Amount.Cmp(snapshot.PayoutAmount) != 0 {
+				a.fail(RulePayoutAmount, e.TransactionHash,
+					"member %s was paid %s wei, payout amount is %s wei", e.Member, e.Amount, snapshot.PayoutAmount)
+			}
+			outflow.Add(outflow, e.Amount)
+
+		case event.NameSurplusClaimed:
+			a.checkClaim(e, premiumOf, surplusClaimed, settled, resolved, false)
+			if premium, ok := premiumOf[e.Member]; ok && settled != nil && joined.Sign() > 0 {
+				share := new(big.Int).Mul(premium, settled.Surplus)
+				share.Quo(share, joined)
+				if e.Amount.Cmp(share) != 0 {
+					a.fail(RuleProRata, e.TransactionHash,
+						"member %s withdrew %s wei, pro-rata share is %s wei", e.Member, e.Amount, share)
+				}
+			}
+			outflow.Add(outflow, e.Amount)
+		}
+	}
+
+	// The example of the spec: what left the pool can never exceed what was paid into it
+	if outflow.Cmp(snapshot.TotalPremium) > 0 {
+		a.fail(RuleOutflowWithinPremium, "",
+			"payouts and surplus withdrawals %s wei exceed total premium %s wei", outflow, snapshot.TotalPremium)
+	}
+
+	if joined.Cmp(snapshot.TotalPremium) != 0 {
+		a.fail(RuleJoinsMatchPremium, "",
+			"premiums joined %s wei do not match on-chain total premium %s wei", joined, snapshot.TotalPremium)
+	}
+
+	expectedBalance := new(big.Int).Sub(snapshot.TotalPremium, outflow)
+	if snapshot.Balance != nil && snapshot.Balance.Cmp(expectedBalance) != 0 {
+		a.fail(RuleBalanceReconciles, "",
+			"contract balance %s wei does not match total premium minus outflows %s wei", snapshot.Balance, expectedBalance)
+	}
+
+	if resolved != nil && (snapshot.State == episode.StateResolved || snapshot.State == episode.StateSettled || snapshot.State == episode.StateClosed) &&
+		resolved.EventOccurred != snapshot.EventOccurred {
+		a.fail(RuleClaimMatchesResult, resolved.TransactionHash,
+			"resolved outcome %t does not match on-chain eventOccurred %t", resolved.EventOccurred, snapshot.EventOccurred)
+	}
+
+	switch snapshot.State {
+	case episode.StateSettled, episode.StateClosed:
+		if settled == nil {
+			a.fail(RuleSingleSettlement, "", "episode is %s but no EpisodeSettled event was indexed", snapshot.State)
+		} else if settled.TotalPayout.Cmp(snapshot.TotalPayout) != 0 || settled.Surplus.Cmp(snapshot.Surplus) != 0 {
+			a.fail(RuleSingleSettlement, settled.TransactionHash,
+				"on-chain settlement (payout %s, surplus %s) differs from the settled event (payout %s, surplus %s)",
+				snapshot.TotalPayout, snapshot.Surplus, settled.TotalPayout, settled.Surplus)
+		}
+	default:
+		if settled != nil {
+			a.fail(RuleSingleSettlement, settled.TransactionHash, "EpisodeSettled emitted while episode is %s", snapshot.State)
+		}
+	}
+
+	return &Report{
+		Episode:   address,
+		AuditedAt: time.Now().UTC(),
+		Events:    len(events),
+		Findings:  a.findings,
+	}
+}
+
+// checkClaim checks a PayoutClaimed (payout true) or SurplusClaimed event against the
+// membership, settlement and outcome replayed so far
+func (a *auditor) checkClaim(
+	e *event.Event,
+	premiumOf map[string]*big.Int,
+	claimed map[string]bool,
+	settled *event.Event,
+	resolved *event.Event,
+	payout bool,
+) {
+	if _, ok := premiumOf[e.Member]; !ok {
+		a.fail(RuleMemberOnly, e.TransactionHash, "%s paid %s wei to non-member %s", e.Name, e.Amount, e.Member)
+	}
+	if claimed[e.Member] {
+		a.fail(RuleSingleClaim, e.TransactionHash, "member %s received %s more than once", e.Member, e.Name)
+	}
+	claimed[e.Member] = true
+
+	if settled == nil {
+		a.fail(RuleClaimAfterSettle, e.TransactionHash, "%s by %s before settlement", e.Name, e.Member)
+	}
+	if resolved != nil && resolved.EventOccurred != payout {
+		a.fail(RuleClaimMatchesResult, e.TransactionHash,
+			"%s by %s although the resolved outcome was eventOccurred=%t", e.Name, e.Member, resolved.EventOccurred)
+	}
+}
@@ -0,0 +1,114 @@
+package chain
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/sha3"
+)
+
+// ErrInvalidAddress is wrapped by every AddressError
+var ErrInvalidAddress = errors.New("invalid address")
+
+// AddressError describes why a string is not a valid address
+type AddressError struct {
+	Value  string
+	Reason string
+}
+
+func (e *AddressError) Error() string {
+	return fmt.Sprintf("invalid address %q: %s", e.Value, e.Reason)
+}
+
+// Unwrap makes errors.Is(err, ErrInvalidAddress) match
+func (e *AddressError) Unwrap() error {
+	return ErrInvalidAddress
+}
+
+// Address is a 20-byte Ethereum account or contract address.
+// It is kept in canonical lowercase form; the zero value means "no address".
+type Address struct {
+	hex string
+}
+
+// ParseAddress parses a 0x-prefixed, 40 hex digit address.
+// All-lowercase and all-uppercase addresses are accepted as is; mixed-case
+// addresses must carry a valid EIP-55 checksum, so a mistyped character is caught.
+func ParseAddress(s string) (Address, error) {
+	if !strings.HasPrefix(s, "0x") && !strings.HasPrefix(s, "0X") {
+		return Address{}, &AddressError{Value: s, Reason: "missing 0x prefix"}
+	}
+	digits := s[2:]
+	if len(digits) != 40 {
+		return Address{}, &AddressError{Value: s, Reason: fmt.Sprintf("expected 40 hex digits, got %d", len(digits))}
+	}
+	if _, err := hex.DecodeString(digits); err != nil {
+		return Address{}, &AddressError{Value: s, Reason: "contains non-hex characters"}
+	}
+
+	lower := strings.ToLower(digits)
+	if digits != lower && digits != strings.ToUpper(digits) && checksum(lower) != digits {
+		return Address{}, &AddressError{Value: s, Reason: "EIP-55 checksum mismatch"}
+	}
+
+	return Address{hex: "0x" + lower}, nil
+}
+
+// checksum returns the EIP-55 mixed-case form of 40 lowercase hex digits:
+// a letter is uppercased when the matching nibble of keccak256(digits) is 8 or more
+func checksum(lower string) string {
+	h := sha3.NewLegacyKeccak256()
+	h.Write([]byte(lower))
+	hash := h.Sum(nil)
+
+	result := []byte(lower)
+	for i, c := range result {
+		nibble := hash[i/2] >> 4
+		if i%2 == 1 {
+			nibble = hash[i/2] & 0x0f
+		}
+		if c >= 'a' && nibble >= 8 {
+			result[i] = c - 'a' + 'A'
+		}
+	}
+	return string(result)
+}
+
+// String returns the canonical lowercase form, or "" for the zero Address
+func (a Address) String() string {
+	return a.hex
+}
+
+// Checksum returns the EIP-55 checksummed form, or "" for the zero Address
+func (a Address) Checksum() string {
+	if a.IsZero() {
+		return ""
+	}
+	return "0x" + checksum(a.hex[2:])
+}
+
+// IsZero reports whether no address is set
+func (a Address) IsZero() bool {
+	return a.hex == ""
+}
+
+// MarshalText encodes the address in canonical lowercase form
+func (a Address) MarshalText() ([]byte, error) {
+	return []byte(a.hex), nil
+}
+
+// UnmarshalText parses and validates an address; an empty string is the zero Address
+func (a *Address) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*a = Address{}
+		return nil
+	}
+	parsed, err := ParseAddress(string(text))
+	if err != nil {
+		return err
+	}
+	*a = parsed
+	return nil
+}
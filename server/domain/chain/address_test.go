@@ -0,0 +1,105 @@
+package chain
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// eip55Vectors are the mixed-case test vectors from the EIP-55 specification
+var eip55Vectors = []string{
+	"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+	"0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359",
+	"0xdbF03B407c01E7cD3CBea99509d93f8DDDC8C6FB",
+	"0xD1220A0cf47c7B9Be7A2E6BA89F429762e7b9aDb",
+}
+
+func TestParseAddressChecksum(t *testing.T) {
+	for _, vector := range eip55Vectors {
+		address, err := ParseAddress(vector)
+		if err != nil {
+			t.Errorf("ParseAddress(%q) error = %v", vector, err)
+			continue
+		}
+		if address.String() != strings.ToLower(vector) {
+			t.Errorf("ParseAddress(%q) = %s, want the lowercase form", vector, address)
+		}
+		if address.Checksum() != vector {
+			t.Errorf("Checksum() = %s, want %s", address.Checksum(), vector)
+		}
+	}
+}
+
+func TestParseAddressRejectsFlippedCase(t *testing.T) {
+	for _, vector := range eip55Vectors {
+		// Flip the case of the first letter so the address stays mixed case
+		i := strings.IndexAny(vector[2:], "abcdefABCDEF") + 2
+		flipped := []byte(vector)
+		flipped[i] ^= 'a' - 'A'
+
+		_, err := ParseAddress(string(flipped))
+		if !errors.Is(err, ErrInvalidAddress) || !strings.Contains(err.Error(), "checksum") {
+			t.Errorf("ParseAddress(%q) error = %v, want a checksum mismatch", flipped, err)
+		}
+	}
+}
+
+func TestParseAddress(t *testing.T) {
+	tests := []struct {
+		name  string
+		in    string
+		want  string
+		valid bool
+	}{
+		{"all lower case", "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed", "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed", true},
+		{"all upper case", "0x5AAEB6053F3E94C9B9A09F33669435E7EF1BEAED", "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed", true},
+		{"upper case prefix", "0X52908400098527886E0F7030069857D2E4169EE7", "0x52908400098527886e0f7030069857d2e4169ee7", true},
+		{"digits only", "0x1111111111111111111111111111111111111111", "0x1111111111111111111111111111111111111111", true},
+		{"missing prefix", "5aaeb6053f3e94c9b9a09f33669435e7ef1beaed", "", false},
+		{"bad prefix", "1x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed", "", false},
+		{"empty", "", "", false},
+		{"prefix only", "0x", "", false},
+		{"too short", "0x5aaeb6053f3e94c9b9a09f33669435e7ef1bea", "", false},
+		{"too long", "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed00", "", false},
+		{"non-hex character", "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaeg", "", false},
+		{"whitespace", "0x5aaeb6053f3e94c9b9a09f33669435e7ef1bea d", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			address, err := ParseAddress(tt.in)
+			if !tt.valid {
+				var addressErr *AddressError
+				if !errors.As(err, &addressErr) || !errors.Is(err, ErrInvalidAddress) {
+					t.Errorf("ParseAddress(%q) error = %v, want an AddressError", tt.in, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseAddress(%q) error = %v", tt.in, err)
+			}
+			if address.String() != tt.want {
+				t.Errorf("ParseAddress(%q) = %s, want %s", tt.in, address, tt.want)
+			}
+		})
+	}
+}
+
+func TestAddressText(t *testing.T) {
+	var address Address
+	if err := address.UnmarshalText(nil); err != nil || !address.IsZero() {
+		t.Errorf("UnmarshalText(empty) = %s, %v; want the zero Address", address, err)
+	}
+	if address.Checksum() != "" {
+		t.Errorf("zero Address Checksum() = %q, want empty", address.Checksum())
+	}
+	if err := address.UnmarshalText([]byte(eip55Vectors[0])); err != nil {
+		t.Fatalf("UnmarshalText error = %v", err)
+	}
+	text, _ := address.MarshalText()
+	if string(text) != strings.ToLower(eip55Vectors[0]) {
+		t.Errorf("MarshalText() = %s, want the lowercase form", text)
+	}
+	if err := address.UnmarshalText([]byte("0x123")); err == nil {
+		t.Error("UnmarshalText of a short address succeeded")
+	}
+}
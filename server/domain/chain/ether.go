@@ -0,0 +1,33 @@
+package chain
+
+import (
+	"math/big"
+	"strings"
+)
+
+// EtherDecimals is the number of decimals of ether: 1 ETH is 10^18 wei
+const EtherDecimals = 18
+
+// weiPerEther is 10^18
+var weiPerEther = new(big.Int).Exp(big.NewInt(10), big.NewInt(EtherDecimals), nil)
+
+// FormatEther formats an amount of wei as ETH with all 18 decimals, e.g. 0.010000000000000000,
+// so the value is exact and lines up in columns. A nil amount formats as "".
+func FormatEther(wei *big.Int) string {
+	if wei == nil {
+		return ""
+	}
+
+	quotient, remainder := new(big.Int).QuoRem(new(big.Int).Abs(wei), weiPerEther, new(big.Int))
+	fraction := remainder.String()
+
+	var b strings.Builder
+	if wei.Sign() < 0 {
+		b.WriteByte('-')
+	}
+	b.WriteString(quotient.String())
+	b.WriteByte('.')
+	b.WriteString(strings.Repeat("0", EtherDecimals-len(fraction)))
+	b.WriteString(fraction)
+	return b.String()
+}
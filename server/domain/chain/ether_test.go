@@ -0,0 +1,54 @@
+package chain
+
+import (
+	"math/big"
+	"testing"
+)
+
+// wei parses a decimal amount of wei
+func wei(t *testing.T, s string) *big.Int {
+	t.Helper()
+	v, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		t.Fatalf("invalid amount %q", s)
+	}
+	return v
+}
+
+func TestFormatEther(t *testing.T) {
+	tests := []struct {
+		name string
+		wei  string
+		want string
+	}{
+		{"zero", "0", "0.000000000000000000"},
+		{"one wei", "1", "0.000000000000000001"},
+		{"under one ether", "10000000000000000", "0.010000000000000000"},
+		{"just under one ether", "999999999999999999", "0.999999999999999999"},
+		{"one ether", "1000000000000000000", "1.000000000000000000"},
+		{"ether and wei", "1500000000000000001", "1.500000000000000001"},
+		{"beyond int64", "123456789000000000000000000", "123456789.000000000000000000"},
+		{"minus one wei", "-1", "-0.000000000000000001"},
+		{"negative under one ether", "-10000000000000000", "-0.010000000000000000"},
+		{"negative ether", "-2500000000000000000", "-2.500000000000000000"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatEther(wei(t, tt.wei)); got != tt.want {
+				t.Errorf("FormatEther(%s) = %q, want %q", tt.wei, got, tt.want)
+			}
+		})
+	}
+
+	if got := FormatEther(nil); got != "" {
+		t.Errorf("FormatEther(nil) = %q, want empty", got)
+	}
+}
+
+func TestFormatEtherKeepsArgument(t *testing.T) {
+	v := big.NewInt(-5)
+	FormatEther(v)
+	if v.Int64() != -5 {
+		t.Errorf("FormatEther changed its argument to %s", v)
+	}
+}
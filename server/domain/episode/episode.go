@@ -36,6 +36,15 @@ const (
 	CategoryTripCancel  Category = "tripCancel"
 )
 
+// ParseCategory parses a category name
+func ParseCategory(s string) (Category, bool) {
+	switch Category(s) {
+	case CategoryFlightDelay, CategoryWeather, CategoryTripCancel:
+		return Category(s), true
+	}
+	return "", false
+}
+
 // Status represents episode status
 type Status string
 
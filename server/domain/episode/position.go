@@ -0,0 +1,140 @@
+package episode
+
+import (
+	"math/big"
+)
+
+// Position is a member's stake in a single episode as recorded on chain.
+// Claimable amounts follow Episode.claim() and Episode.withdrawSurplus(). The episode state
+// is the one read together with the member flags rather than the summary's, which can be
+// a refresh interval old.
+type Position struct {
+	episode          *Summary
+	member           string
+	state            State
+	joined           bool
+	premiumPaid      *big.Int
+	payoutClaimed    bool
+	surplusWithdrawn bool
+	resolved         bool
+	eventOccurred    bool
+	surplus          *big.Int
+	totalPremium     *big.Int
+}
+
+// NewPosition creates a new Position for member in episode, whose current state is state
+func NewPosition(
+	episode *Summary,
+	member string,
+	state State,
+	joined bool,
+	premiumPaid *big.Int,
+	payoutClaimed bool,
+	surplusWithdrawn bool,
+) *Position {
+	return &Position{
+		episode:          episode,
+		member:           member,
+		state:            state,
+		joined:           joined,
+		premiumPaid:      premiumPaid,
+		payoutClaimed:    payoutClaimed,
+		surplusWithdrawn: surplusWithdrawn,
+		surplus:          new(big.Int),
+		totalPremium:     episode.TotalPremium(),
+	}
+}
+
+// SetResolution sets the oracle outcome, and the surplus and total premium fixed at settlement
+func (p *Position) SetResolution(eventOccurred bool, surplus, totalPremium *big.Int) {
+	p.resolved = true
+	p.eventOccurred = eventOccurred
+	p.surplus = surplus
+	p.totalPremium = totalPremium
+}
+
+// Episode returns the episode summary
+func (p *Position) Episode() *Summary {
+	return p.episode
+}
+
+// Member returns the member address
+func (p *Position) Member() string {
+	return p.member
+}
+
+// State returns the episode state read together with the member flags
+func (p *Position) State() State {
+	return p.state
+}
+
+// Joined reports whether the member joined the episode on chain
+func (p *Position) Joined() bool {
+	return p.joined
+}
+
+// PremiumPaid returns the premium paid by the member in wei
+func (p *Position) PremiumPaid() *big.Int {
+	return p.premiumPaid
+}
+
+// PayoutClaimed reports whether the member claimed the payout
+func (p *Position) PayoutClaimed() bool {
+	return p.payoutClaimed
+}
+
+// SurplusWithdrawn reports whether the member withdrew the surplus share
+func (p *Position) SurplusWithdrawn() bool {
+	return p.surplusWithdrawn
+}
+
+// Resolved reports whether the oracle outcome is known
+func (p *Position) Resolved() bool {
+	return p.resolved
+}
+
+// EventOccurred reports whether the insured event occurred
+func (p *Position) EventOccurred() bool {
+	return p.eventOccurred
+}
+
+// surplusShare returns the member's pro-rata share of the surplus (Invariant 5)
+func (p *Position) surplusShare() *big.Int {
+	if p.totalPremium.Sign() == 0 {
+		return new(big.Int)
+	}
+	share := new(big.Int).Mul(p.premiumPaid, p.surplus)
+	return share.Quo(share, p.totalPremium)
+}
+
+// ClaimablePayout returns the payout the member can currently claim in wei
+func (p *Position) ClaimablePayout() *big.Int {
+	if !p.joined || p.payoutClaimed || p.state != StateSettled || !p.eventOccurred {
+		return new(big.Int)
+	}
+	return new(big.Int).Set(p.episode.PayoutAmount())
+}
+
+// ClaimableSurplus returns the surplus the member can currently withdraw in wei
+func (p *Position) ClaimableSurplus() *big.Int {
+	if !p.joined || p.surplusWithdrawn || p.state != StateSettled || p.eventOccurred {
+		return new(big.Int)
+	}
+	return p.surplusShare()
+}
+
+// PayoutReceived returns the payout the member already received in wei
+func (p *Position) PayoutReceived() *big.Int {
+	if !p.payoutClaimed {
+		return new(big.Int)
+	}
+	return new(big.Int).Set(p.episode.PayoutAmount())
+}
+
+// SurplusReceived returns the surplus the member already withdrew in wei
+func (p *Position) SurplusReceived() *big.Int {
+	if !p.surplusWithdrawn {
+		return new(big.Int)
+	}
+	return p.surplusShare()
+}
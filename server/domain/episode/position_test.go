@@ -0,0 +1,67 @@
+package episode
+
+import (
+	"math/big"
+	"testing"
+	"time"
+)
+
+const (
+	positionEpisode = "0x1111111111111111111111111111111111111111"
+	positionMember  = "0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+)
+
+// lockedSummary returns a summary last refreshed while the episode was locked with 300 wei of premiums
+func lockedSummary() *Summary {
+	s := NewSummary(positionEpisode, "KE902", big.NewInt(100), big.NewInt(500), time.Time{}, time.Time{}, time.Time{}, time.Time{}, 1)
+	s.UpdateOnChainState(StateLocked, big.NewInt(300), big.NewInt(300))
+	return s
+}
+
+func TestPositionClaimableFollowsLiveState(t *testing.T) {
+	tests := []struct {
+		name          string
+		state         State
+		resolved      bool
+		eventOccurred bool
+		claimed       bool
+		withdrawn     bool
+		wantPayout    int64
+		wantSurplus   int64
+	}{
+		{"locked", StateLocked, false, false, false, false, 0, 0},
+		{"resolved, not settled yet", StateResolved, true, true, false, false, 0, 0},
+		{"settled with the event", StateSettled, true, true, false, false, 500, 0},
+		{"settled without the event", StateSettled, true, false, false, false, 0, 200},
+		{"payout claimed", StateSettled, true, true, true, false, 0, 0},
+		{"surplus withdrawn", StateSettled, true, false, false, true, 0, 0},
+		{"closed", StateClosed, true, false, false, false, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The summary still says locked; the live state decides
+			p := NewPosition(lockedSummary(), positionMember, tt.state, true, big.NewInt(100), tt.claimed, tt.withdrawn)
+			if tt.resolved {
+				// 400 wei of premiums were paid by settlement, more than the summary saw
+				p.SetResolution(tt.eventOccurred, big.NewInt(800), big.NewInt(400))
+			}
+			if p.State() != tt.state {
+				t.Errorf("State = %s, want %s", p.State(), tt.state)
+			}
+			if got := p.ClaimablePayout().Int64(); got != tt.wantPayout {
+				t.Errorf("ClaimablePayout = %d, want %d", got, tt.wantPayout)
+			}
+			if got := p.ClaimableSurplus().Int64(); got != tt.wantSurplus {
+				t.Errorf("ClaimableSurplus = %d, want %d", got, tt.wantSurplus)
+			}
+		})
+	}
+}
+
+func TestPositionNotJoined(t *testing.T) {
+	p := NewPosition(lockedSummary(), positionMember, StateSettled, false, new(big.Int), false, false)
+	p.SetResolution(true, big.NewInt(800), big.NewInt(400))
+	if p.ClaimablePayout().Sign() != 0 || p.ClaimableSurplus().Sign() != 0 {
+		t.Errorf("non-member can claim %s payout, %s surplus", p.ClaimablePayout(), p.ClaimableSurplus())
+	}
+}
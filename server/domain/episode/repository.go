@@ -1,5 +1,12 @@
 package episode
 
+import (
+	"errors"
+)
+
+// ErrNotEpisode is returned when an address is not an episode created by the factory
+var ErrNotEpisode = errors.New("address is not an episode of the factory")
+
 // Repository defines the interface for Episode repository
 type Repository interface {
 	FindByID(id string) (*Episode, error)
@@ -9,3 +16,11 @@ type Repository interface {
 	FindByStatusAndCategory(status Status, category Category) ([]*Episode, error)
 	Save(episode *Episode) error
 }
+
+// SummaryRepository defines the interface for the episode Summary read model repository
+type SummaryRepository interface {
+	FindByAddress(address string) (*Summary, error)
+	FindAll() ([]*Summary, error)
+	FindByQuery(query SummaryQuery) ([]*Summary, error)
+	Save(summary *Summary) error
+}
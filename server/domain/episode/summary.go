@@ -0,0 +1,334 @@
+package episode
+
+import (
+	"math/big"
+	"sort"
+	"strings"
+	"time"
+)
+
+// State represents the on-chain lifecycle state of an Episode contract
+type State string
+
+const (
+	StateCreated  State = "created"
+	StateOpen     State = "open"
+	StateLocked   State = "locked"
+	StateResolved State = "resolved"
+	StateSettled  State = "settled"
+	StateClosed   State = "closed"
+)
+
+// states lists the states in IEpisode.EpisodeState enum order
+var states = []State{
+	StateCreated,
+	StateOpen,
+	StateLocked,
+	StateResolved,
+	StateSettled,
+	StateClosed,
+}
+
+// StateFromIndex maps an IEpisode.EpisodeState enum ordinal to a State
+func StateFromIndex(index uint8) (State, bool) {
+	if int(index) >= len(states) {
+		return "", false
+	}
+	return states[index], true
+}
+
+// ParseState parses a state name
+func ParseState(s string) (State, bool) {
+	for _, state := range states {
+		if string(state) == s {
+			return state, true
+		}
+	}
+	return "", false
+}
+
+// Status maps the on-chain state to the product-level episode status
+func (s State) Status() Status {
+	switch s {
+	case StateCreated, StateOpen:
+		return StatusRecruiting
+	case StateLocked:
+		return StatusActive
+	case StateResolved, StateSettled:
+		return StatusSettling
+	default:
+		return StatusCompleted
+	}
+}
+
+// SortField represents a field episode summaries can be sorted by
+type SortField string
+
+const (
+	SortByCreationBlock SortField = "created"
+	SortByDeparture     SortField = "departure"
+	SortByTVL           SortField = "tvl"
+)
+
+// ParseSortField parses a sort field name
+func ParseSortField(s string) (SortField, bool) {
+	switch SortField(s) {
+	case SortByCreationBlock, SortByDeparture, SortByTVL:
+		return SortField(s), true
+	}
+	return "", false
+}
+
+// Summary is a read model of an Episode contract deployed by the EpisodeFactory.
+// Terms are fixed at creation (Invariant 3); state and balances are refreshed from chain.
+type Summary struct {
+	address              string
+	category             Category
+	flightName           string
+	premiumAmount        *big.Int
+	payoutAmount         *big.Int
+	departureTime        time.Time
+	estimatedArrivalTime time.Time
+	signupStart          time.Time
+	signupEnd            time.Time
+	creationBlock        int64
+	state                State
+	totalPremium         *big.Int
+	tvl                  *big.Int
+	updatedAt            time.Time
+}
+
+// NewSummary creates a new Summary from the immutable episode terms
+func NewSummary(
+	address string,
+	flightName string,
+	premiumAmount *big.Int,
+	payoutAmount *big.Int,
+	departureTime time.Time,
+	estimatedArrivalTime time.Time,
+	signupStart time.Time,
+	signupEnd time.Time,
+	creationBlock int64,
+) *Summary {
+	return &Summary{
+		address:              address,
+		category:             CategoryFlightDelay,
+		flightName:           flightName,
+		premiumAmount:        premiumAmount,
+		payoutAmount:         payoutAmount,
+		departureTime:        departureTime,
+		estimatedArrivalTime: estimatedArrivalTime,
+		signupStart:          signupStart,
+		signupEnd:            signupEnd,
+		creationBlock:        creationBlock,
+		state:                StateCreated,
+		totalPremium:         new(big.Int),
+		tvl:                  new(big.Int),
+		updatedAt:            time.Now(),
+	}
+}
+
+// Address returns the episode contract address
+func (s *Summary) Address() string {
+	return s.address
+}
+
+// Category returns the episode category
+func (s *Summary) Category() Category {
+	return s.category
+}
+
+// FlightName returns the insured flight name
+func (s *Summary) FlightName() string {
+	return s.flightName
+}
+
+// PremiumAmount returns the premium per member in wei
+func (s *Summary) PremiumAmount() *big.Int {
+	return s.premiumAmount
+}
+
+// PayoutAmount returns the payout per member in wei
+func (s *Summary) PayoutAmount() *big.Int {
+	return s.payoutAmount
+}
+
+// DepartureTime returns the scheduled departure time
+func (s *Summary) DepartureTime() time.Time {
+	return s.departureTime
+}
+
+// EstimatedArrivalTime returns the scheduled arrival time
+func (s *Summary) EstimatedArrivalTime() time.Time {
+	return s.estimatedArrivalTime
+}
+
+// SignupStart returns the start of the signup window
+func (s *Summary) SignupStart() time.Time {
+	return s.signupStart
+}
+
+// SignupEnd returns the end of the signup window
+func (s *Summary) SignupEnd() time.Time {
+	return s.signupEnd
+}
+
+// CreationBlock returns the block the episode was created in
+func (s *Summary) CreationBlock() int64 {
+	return s.creationBlock
+}
+
+// SetCreationBlock sets the block the episode was created in
+func (s *Summary) SetCreationBlock(block int64) {
+	s.creationBlock = block
+}
+
+// State returns the on-chain state
+func (s *Summary) State() State {
+	return s.state
+}
+
+// Status returns the product-level status derived from the on-chain state
+func (s *Summary) Status() Status {
+	return s.state.Status()
+}
+
+// TotalPremium returns the total premium collected in wei
+func (s *Summary) TotalPremium() *big.Int {
+	return s.totalPremium
+}
+
+// TVL returns the ether currently held by the episode contract in wei
+func (s *Summary) TVL() *big.Int {
+	return s.tvl
+}
+
+// MemberCount returns the number of members.
+// Every member pays exactly the premium amount once, so it is derived from the total premium.
+func (s *Summary) MemberCount() int {
+	if s.premiumAmount == nil || s.premiumAmount.Sign() == 0 {
+		return 0
+	}
+	return int(new(big.Int).Quo(s.totalPremium, s.premiumAmount).Int64())
+}
+
+// UpdatedAt returns the time the on-chain state was last refreshed
+func (s *Summary) UpdatedAt() time.Time {
+	return s.updatedAt
+}
+
+// UpdateOnChainState updates the mutable on-chain state
+func (s *Summary) UpdateOnChainState(state State, totalPremium, tvl *big.Int) {
+	s.state = state
+	s.totalPremium = totalPremium
+	s.tvl = tvl
+	s.updatedAt = time.Now()
+}
+
+// RestoreOnChainState restores the on-chain state last refreshed at updatedAt from storage
+func (s *Summary) RestoreOnChainState(state State, totalPremium, tvl *big.Int, updatedAt time.Time) {
+	s.state = state
+	s.totalPremium = totalPremium
+	s.tvl = tvl
+	s.updatedAt = updatedAt
+}
+
+// SortKey returns the value of field used for ordering and cursors
+func (s *Summary) SortKey(field SortField) *big.Int {
+	switch field {
+	case SortByDeparture:
+		return big.NewInt(s.departureTime.Unix())
+	case SortByTVL:
+		return new(big.Int).Set(s.tvl)
+	default:
+		return big.NewInt(s.creationBlock)
+	}
+}
+
+// SummaryCursor marks the position of the last summary of a page
+type SummaryCursor struct {
+	SortKey *big.Int
+	Address string
+}
+
+// SummaryQuery represents filter, sort and pagination criteria for episode summaries.
+// Zero values mean "no filter".
+type SummaryQuery struct {
+	State         State
+	Category      Category
+	FlightName    string
+	DepartureFrom *time.Time
+	DepartureTo   *time.Time
+	// DepartureBefore is an exclusive upper bound, used for date-only bounds
+	DepartureBefore *time.Time
+	SortBy          SortField
+	Descending      bool
+	After           *SummaryCursor
+	Limit           int
+}
+
+// Matches reports whether s passes the query filters
+func (q SummaryQuery) Matches(s *Summary) bool {
+	if q.State != "" && s.State() != q.State {
+		return false
+	}
+	if q.Category != "" && s.Category() != q.Category {
+		return false
+	}
+	if q.FlightName != "" && !strings.EqualFold(s.FlightName(), q.FlightName) {
+		return false
+	}
+	if q.DepartureFrom != nil && s.DepartureTime().Before(*q.DepartureFrom) {
+		return false
+	}
+	if q.DepartureTo != nil && s.DepartureTime().After(*q.DepartureTo) {
+		return false
+	}
+	if q.DepartureBefore != nil && !s.DepartureTime().Before(*q.DepartureBefore) {
+		return false
+	}
+	return true
+}
+
+// Apply filters, sorts and paginates summaries in memory.
+// Ties on the sort key are broken by address so the order is stable across calls.
+func (q SummaryQuery) Apply(summaries []*Summary) []*Summary {
+	var result []*Summary
+	for _, s := range summaries {
+		if q.Matches(s) {
+			result = append(result, s)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return q.compare(result[i], result[j].SortKey(q.SortBy), result[j].Address()) < 0
+	})
+
+	if q.After != nil {
+		start := len(result)
+		for i, s := range result {
+			if q.compare(s, q.After.SortKey, strings.ToLower(q.After.Address)) > 0 {
+				start = i
+				break
+			}
+		}
+		result = result[start:]
+	}
+
+	if q.Limit > 0 && len(result) > q.Limit {
+		result = result[:q.Limit]
+	}
+	return result
+}
+
+// compare orders s against a sort key and address in the query direction
+func (q SummaryQuery) compare(s *Summary, key *big.Int, address string) int {
+	c := s.SortKey(q.SortBy).Cmp(key)
+	if c == 0 {
+		c = strings.Compare(s.Address(), address)
+	}
+	if q.Descending {
+		return -c
+	}
+	return c
+}
@@ -0,0 +1,52 @@
+package event
+
+import (
+	"math/big"
+	"time"
+)
+
+// Name represents the name of an event emitted by an Episode contract
+type Name string
+
+const (
+	NameEpisodeCreated  Name = "EpisodeCreated"
+	NameEpisodeOpened   Name = "EpisodeOpened"
+	NameEpisodeLocked   Name = "EpisodeLocked"
+	NameEpisodeResolved Name = "EpisodeResolved"
+	NameEpisodeSettled  Name = "EpisodeSettled"
+	NameEpisodeClosed   Name = "EpisodeClosed"
+	NameMemberJoined    Name = "MemberJoined"
+	NamePayoutClaimed   Name = "PayoutClaimed"
+	NameSurplusClaimed  Name = "SurplusClaimed"
+)
+
+// Event is a decoded Episode contract log.
+// Only the fields carried by the event's Solidity signature are set:
+//   - MemberJoined, PayoutClaimed, SurplusClaimed: Member, Amount
+//   - EpisodeResolved: EventOccurred, FinalArrivalTime
+//   - EpisodeSettled: TotalPayout, Surplus
+type Event struct {
+	// Sequence is assigned by the store in append order and is used for incremental consumers
+	Sequence        int64
+	Episode         string
+	Name            Name
+	BlockNumber     int64
+	LogIndex        int64
+	TransactionHash string
+	Timestamp       time.Time
+
+	Member           string
+	Amount           *big.Int
+	EventOccurred    bool
+	FinalArrivalTime uint64
+	TotalPayout      *big.Int
+	Surplus          *big.Int
+}
+
+// Before reports whether e was emitted before other on chain
+func (e *Event) Before(other *Event) bool {
+	if e.BlockNumber != other.BlockNumber {
+		return e.BlockNumber < other.BlockNumber
+	}
+	return e.LogIndex < other.LogIndex
+}
@@ -0,0 +1,18 @@
+package event
+
+import "context"
+
+// Store defines the interface for the persisted store of decoded episode events.
+// Appending is idempotent on (TransactionHash, LogIndex).
+type Store interface {
+	Append(ctx context.Context, events []*Event) error
+	FindByEpisode(ctx context.Context, episode string) ([]*Event, error)
+	FindAfterSequence(ctx context.Context, sequence int64, limit int) ([]*Event, error)
+}
+
+// CheckpointRepository defines the interface for checkpoints, the last block
+// fully indexed (or event sequence processed) for a named stream
+type CheckpointRepository interface {
+	FindCheckpoint(ctx context.Context, name string) (int64, error)
+	SaveCheckpoint(ctx context.Context, name string, block int64) error
+}
@@ -0,0 +1,115 @@
+package ledger
+
+import (
+	"math/big"
+
+	"eventsure-server/domain/event"
+)
+
+// Kind is the kind of money movement of a ledger entry
+type Kind string
+
+const (
+	// KindPremium is a premium paid in by a joining member (MemberJoined)
+	KindPremium Kind = "premium"
+	// KindPayout is a payout claimed by a member (PayoutClaimed)
+	KindPayout Kind = "payout"
+	// KindSurplus is a surplus withdrawal by a member (SurplusClaimed)
+	KindSurplus Kind = "surplus"
+)
+
+// Entry is one money movement of an episode
+type Entry struct {
+	Event *event.Event
+	Kind  Kind
+	// Amount is positive for premiums and negative for payouts and surplus withdrawals
+	Amount *big.Int
+	// Balance is the running balance of the episode after the entry
+	Balance *big.Int
+}
+
+// Ledger is the money movements of an episode in chain order with their running balance
+type Ledger struct {
+	Episode    string
+	Entries    []Entry
+	PremiumIn  *big.Int
+	PayoutOut  *big.Int
+	SurplusOut *big.Int
+}
+
+// Build builds the ledger of an episode from its events in chain order.
+// Events that move no money are skipped.
+func Build(episode string, events []*event.Event) *Ledger {
+	l := &Ledger{
+		Episode:    episode,
+		Entries:    []Entry{},
+		PremiumIn:  new(big.Int),
+		PayoutOut:  new(big.Int),
+		SurplusOut: new(big.Int),
+	}
+
+	balance := new(big.Int)
+	for _, e := range events {
+		if e.Amount == nil {
+			continue
+		}
+
+		amount := new(big.Int).Set(e.Amount)
+		var kind Kind
+		switch e.Name {
+		case event.NameMemberJoined:
+			kind = KindPremium
+			l.PremiumIn.Add(l.PremiumIn, e.Amount)
+		case event.NamePayoutClaimed:
+			kind = KindPayout
+			amount.Neg(amount)
+			l.PayoutOut.Add(l.PayoutOut, e.Amount)
+		case event.NameSurplusClaimed:
+			kind = KindSurplus
+			amount.Neg(amount)
+			l.SurplusOut.Add(l.SurplusOut, e.Amount)
+		default:
+			continue
+		}
+
+		balance.Add(balance, amount)
+		l.Entries = append(l.Entries, Entry{
+			Event:   e,
+			Kind:    kind,
+			Amount:  amount,
+			Balance: new(big.Int).Set(balance),
+		})
+	}
+	return l
+}
+
+// Balance returns the balance after the last entry: premiums in minus payouts and surplus out
+func (l *Ledger) Balance() *big.Int {
+	balance := new(big.Int).Sub(l.PremiumIn, l.PayoutOut)
+	return balance.Sub(balance, l.SurplusOut)
+}
+
+// Reconciliation compares the ledger balance with the balance of the contract
+type Reconciliation struct {
+	LedgerBalance  *big.Int
+	OnChainBalance *big.Int
+	// Difference is the on-chain balance minus the ledger balance. Besides a violation of
+	// Invariant 1, it can be events the indexer has not reached yet, or ether sent to the
+	// contract outside of joins.
+	Difference *big.Int
+}
+
+// Reconcile reconciles the ledger with the on-chain balance of the contract
+func (l *Ledger) Reconcile(onChainBalance *big.Int) Reconciliation {
+	ledgerBalance := l.Balance()
+	return Reconciliation{
+		LedgerBalance:  ledgerBalance,
+		OnChainBalance: onChainBalance,
+		Difference:     new(big.Int).Sub(onChainBalance, ledgerBalance),
+	}
+}
+
+// Reconciled reports whether the ledger balance equals the on-chain balance
+func (r Reconciliation) Reconciled() bool {
+	return r.Difference.Sign() == 0
+}
@@ -0,0 +1,153 @@
+package ledger
+
+import (
+	"math/big"
+	"testing"
+
+	"eventsure-server/domain/event"
+)
+
+const episodeAddress = "0x1111111111111111111111111111111111111111"
+
+// moneyEvent returns an event of name moving amount wei, or no money when amount is negative
+func moneyEvent(name event.Name, amount int64) *event.Event {
+	e := &event.Event{Episode: episodeAddress, Name: name}
+	if amount >= 0 {
+		e.Amount = big.NewInt(amount)
+	}
+	return e
+}
+
+func TestBuild(t *testing.T) {
+	joined := func(amount int64) *event.Event { return moneyEvent(event.NameMemberJoined, amount) }
+	payout := func(amount int64) *event.Event { return moneyEvent(event.NamePayoutClaimed, amount) }
+	surplus := func(amount int64) *event.Event { return moneyEvent(event.NameSurplusClaimed, amount) }
+
+	tests := []struct {
+		name         string
+		events       []*event.Event
+		wantKinds    []Kind
+		wantAmounts  []int64
+		wantBalances []int64
+		wantIn       int64
+		wantPayout   int64
+		wantSurplus  int64
+	}{
+		{
+			name: "no events",
+		},
+		{
+			name:         "premiums",
+			events:       []*event.Event{joined(100), joined(250)},
+			wantKinds:    []Kind{KindPremium, KindPremium},
+			wantAmounts:  []int64{100, 250},
+			wantBalances: []int64{100, 350},
+			wantIn:       350,
+		},
+		{
+			name:         "payouts and surplus are negative",
+			events:       []*event.Event{joined(100), joined(100), payout(150), surplus(30)},
+			wantKinds:    []Kind{KindPremium, KindPremium, KindPayout, KindSurplus},
+			wantAmounts:  []int64{100, 100, -150, -30},
+			wantBalances: []int64{100, 200, 50, 20},
+			wantIn:       200,
+			wantPayout:   150,
+			wantSurplus:  30,
+		},
+		{
+			name:         "balance below zero",
+			events:       []*event.Event{joined(10), payout(25)},
+			wantKinds:    []Kind{KindPremium, KindPayout},
+			wantAmounts:  []int64{10, -25},
+			wantBalances: []int64{10, -15},
+			wantIn:       10,
+			wantPayout:   25,
+		},
+		{
+			name:         "zero amounts are entries",
+			events:       []*event.Event{joined(0), surplus(0)},
+			wantKinds:    []Kind{KindPremium, KindSurplus},
+			wantAmounts:  []int64{0, 0},
+			wantBalances: []int64{0, 0},
+		},
+		{
+			name: "events moving no money are skipped",
+			events: []*event.Event{
+				joined(100),
+				moneyEvent(event.NameEpisodeLocked, -1),
+				moneyEvent(event.NameEpisodeSettled, 40),
+				payout(-1),
+				surplus(60),
+			},
+			wantKinds:    []Kind{KindPremium, KindSurplus},
+			wantAmounts:  []int64{100, -60},
+			wantBalances: []int64{100, 40},
+			wantIn:       100,
+			wantSurplus:  60,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := Build(episodeAddress, tt.events)
+			if len(l.Entries) != len(tt.wantKinds) {
+				t.Fatalf("%d entries, want %d", len(l.Entries), len(tt.wantKinds))
+			}
+			for i, entry := range l.Entries {
+				if entry.Kind != tt.wantKinds[i] || entry.Amount.Int64() != tt.wantAmounts[i] || entry.Balance.Int64() != tt.wantBalances[i] {
+					t.Errorf("entry %d = %s %s balance %s, want %s %d balance %d",
+						i, entry.Kind, entry.Amount, entry.Balance, tt.wantKinds[i], tt.wantAmounts[i], tt.wantBalances[i])
+				}
+			}
+			if l.PremiumIn.Int64() != tt.wantIn || l.PayoutOut.Int64() != tt.wantPayout || l.SurplusOut.Int64() != tt.wantSurplus {
+				t.Errorf("totals in %s, payout %s, surplus %s; want %d, %d, %d",
+					l.PremiumIn, l.PayoutOut, l.SurplusOut, tt.wantIn, tt.wantPayout, tt.wantSurplus)
+			}
+			wantBalance := tt.wantIn - tt.wantPayout - tt.wantSurplus
+			if l.Balance().Int64() != wantBalance {
+				t.Errorf("Balance = %s, want %d", l.Balance(), wantBalance)
+			}
+		})
+	}
+}
+
+func TestBuildKeepsEventAmounts(t *testing.T) {
+	payout := moneyEvent(event.NamePayoutClaimed, 70)
+	l := Build(episodeAddress, []*event.Event{moneyEvent(event.NameMemberJoined, 100), payout})
+	if payout.Amount.Int64() != 70 {
+		t.Errorf("Build changed the event amount to %s", payout.Amount)
+	}
+	l.Entries[0].Balance.SetInt64(0)
+	if l.Entries[1].Balance.Int64() != 30 {
+		t.Errorf("entries share their balance: %s", l.Entries[1].Balance)
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	tests := []struct {
+		name           string
+		events         []*event.Event
+		onChain        int64
+		wantDifference int64
+	}{
+		{"empty and empty", nil, 0, 0},
+		{"matching", []*event.Event{moneyEvent(event.NameMemberJoined, 100), moneyEvent(event.NamePayoutClaimed, 40)}, 60, 0},
+		{"events not indexed yet", []*event.Event{moneyEvent(event.NameMemberJoined, 100)}, 250, 150},
+		{"missing ether", []*event.Event{moneyEvent(event.NameMemberJoined, 100)}, 30, -70},
+		{"drained contract", []*event.Event{moneyEvent(event.NameMemberJoined, 100)}, 0, -100},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := Build(episodeAddress, tt.events)
+			r := l.Reconcile(big.NewInt(tt.onChain))
+			if r.Difference.Int64() != tt.wantDifference {
+				t.Errorf("Difference = %s, want %d", r.Difference, tt.wantDifference)
+			}
+			if r.LedgerBalance.Cmp(l.Balance()) != 0 || r.OnChainBalance.Int64() != tt.onChain {
+				t.Errorf("balances ledger %s, on-chain %s; want %s, %d", r.LedgerBalance, r.OnChainBalance, l.Balance(), tt.onChain)
+			}
+			if r.Reconciled() != (tt.wantDifference == 0) {
+				t.Errorf("Reconciled = %v with difference %d", r.Reconciled(), tt.wantDifference)
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package membership
+
+import (
+	"strings"
+
+	"eventsure-server/domain/event"
+)
+
+// Progress is a member's position in the episode lifecycle:
+// joined → locked → resolved → payout_claimable / surplus_claimable → claimed / withdrawn,
+// or closed when the episode closes before the member claims
+type Progress string
+
+const (
+	ProgressJoined           Progress = "joined"
+	ProgressLocked           Progress = "locked"
+	ProgressResolved         Progress = "resolved"
+	ProgressPayoutClaimable  Progress = "payout_claimable"
+	ProgressSurplusClaimable Progress = "surplus_claimable"
+	ProgressClaimed          Progress = "claimed"
+	ProgressWithdrawn        Progress = "withdrawn"
+	// ProgressClosed is a member who neither claimed nor withdrew before the episode closed;
+	// a closed episode accepts no claims, so nothing is claimable anymore
+	ProgressClosed Progress = "closed"
+)
+
+// ParseProgress parses a progress name
+func ParseProgress(s string) (Progress, bool) {
+	switch Progress(s) {
+	case ProgressJoined, ProgressLocked, ProgressResolved, ProgressPayoutClaimable,
+		ProgressSurplusClaimable, ProgressClaimed, ProgressWithdrawn, ProgressClosed:
+		return Progress(s), true
+	}
+	return "", false
+}
+
+// episodeProgress is the per-episode state of the projection
+type episodeProgress struct {
+	locked        bool
+	resolved      bool
+	eventOccurred bool
+	settled       bool
+	closed        bool
+	joined        map[string]bool
+	claimed       map[string]bool
+	withdrawn     map[string]bool
+}
+
+// ProgressProjection incrementally derives member progress from episode events.
+// Lifecycle events and claims only ever move progress forward, so events may be
+// applied in store sequence order rather than chain order.
+type ProgressProjection struct {
+	lastSequence int64
+	episodes     map[string]*episodeProgress
+}
+
+// NewProgressProjection creates an empty ProgressProjection
+func NewProgressProjection() *ProgressProjection {
+	return &ProgressProjection{
+		episodes: make(map[string]*episodeProgress),
+	}
+}
+
+// LastSequence returns the sequence of the last applied event
+func (p *ProgressProjection) LastSequence() int64 {
+	return p.lastSequence
+}
+
+// Apply folds an event into the projection
+func (p *ProgressProjection) Apply(e *event.Event) {
+	if e.Sequence > p.lastSequence {
+		p.lastSequence = e.Sequence
+	}
+
+	ep := p.episode(e.Episode)
+	member := strings.ToLower(e.Member)
+
+	switch e.Name {
+	case event.NameEpisodeLocked:
+		ep.locked = true
+	case event.NameEpisodeResolved:
+		ep.resolved = true
+		ep.eventOccurred = e.EventOccurred
+	case event.NameEpisodeSettled:
+		ep.settled = true
+	case event.NameEpisodeClosed:
+		ep.closed = true
+	case event.NameMemberJoined:
+		ep.joined[member] = true
+	case event.NamePayoutClaimed:
+		ep.claimed[member] = true
+	case event.NameSurplusClaimed:
+		ep.withdrawn[member] = true
+	}
+}
+
+// episode returns the per-episode progress, creating it on first use
+func (p *ProgressProjection) episode(address string) *episodeProgress {
+	address = strings.ToLower(address)
+	ep, ok := p.episodes[address]
+	if !ok {
+		ep = &episodeProgress{
+			joined:    make(map[string]bool),
+			claimed:   make(map[string]bool),
+			withdrawn: make(map[string]bool),
+		}
+		p.episodes[address] = ep
+	}
+	return ep
+}
+
+// Episodes returns the addresses of the episodes seen
+func (p *ProgressProjection) Episodes() []string {
+	addresses := make([]string, 0, len(p.episodes))
+	for address := range p.episodes {
+		addresses = append(addresses, address)
+	}
+	return addresses
+}
+
+// Progress returns member's progress in the episode.
+// ok is false while no MemberJoined event of the member has been applied.
+func (p *ProgressProjection) Progress(episode, member string) (progress Progress, ok bool) {
+	ep, found := p.episodes[strings.ToLower(episode)]
+	member = strings.ToLower(member)
+	if !found || !ep.joined[member] {
+		return "", false
+	}
+
+	switch {
+	case ep.claimed[member]:
+		return ProgressClaimed, true
+	case ep.withdrawn[member]:
+		return ProgressWithdrawn, true
+	case ep.closed:
+		return ProgressClosed, true
+	case ep.settled && ep.eventOccurred:
+		return ProgressPayoutClaimable, true
+	case ep.settled:
+		return ProgressSurplusClaimable, true
+	case ep.resolved:
+		return ProgressResolved, true
+	case ep.locked:
+		return ProgressLocked, true
+	}
+	return ProgressJoined, true
+}
@@ -0,0 +1,76 @@
+package membership
+
+import (
+	"testing"
+
+	"eventsure-server/domain/event"
+)
+
+const (
+	episodeAddress = "0x1111111111111111111111111111111111111111"
+	member         = "0xAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA"
+)
+
+func lifecycle(names ...event.Name) []*event.Event {
+	events := make([]*event.Event, len(names))
+	for i, name := range names {
+		events[i] = &event.Event{Sequence: int64(i + 1), Episode: episodeAddress, Name: name}
+	}
+	return events
+}
+
+func TestProgressProjection(t *testing.T) {
+	joined := &event.Event{Episode: episodeAddress, Name: event.NameMemberJoined, Member: member}
+	locked := &event.Event{Episode: episodeAddress, Name: event.NameEpisodeLocked}
+	resolved := func(occurred bool) *event.Event {
+		return &event.Event{Episode: episodeAddress, Name: event.NameEpisodeResolved, EventOccurred: occurred}
+	}
+	settled := &event.Event{Episode: episodeAddress, Name: event.NameEpisodeSettled}
+	closed := &event.Event{Episode: episodeAddress, Name: event.NameEpisodeClosed}
+	claimed := &event.Event{Episode: episodeAddress, Name: event.NamePayoutClaimed, Member: member}
+	withdrawn := &event.Event{Episode: episodeAddress, Name: event.NameSurplusClaimed, Member: member}
+
+	tests := []struct {
+		name   string
+		events []*event.Event
+		want   Progress
+	}{
+		{"joined", []*event.Event{joined}, ProgressJoined},
+		{"locked", []*event.Event{joined, locked}, ProgressLocked},
+		{"resolved", []*event.Event{joined, locked, resolved(true)}, ProgressResolved},
+		{"payout claimable", []*event.Event{joined, locked, resolved(true), settled}, ProgressPayoutClaimable},
+		{"surplus claimable", []*event.Event{joined, locked, resolved(false), settled}, ProgressSurplusClaimable},
+		{"claimed", []*event.Event{joined, locked, resolved(true), settled, claimed}, ProgressClaimed},
+		{"withdrawn", []*event.Event{joined, locked, resolved(false), settled, withdrawn}, ProgressWithdrawn},
+		{"unclaimed payout after close", []*event.Event{joined, locked, resolved(true), settled, closed}, ProgressClosed},
+		{"unwithdrawn surplus after close", []*event.Event{joined, locked, resolved(false), settled, closed}, ProgressClosed},
+		{"claimed before close", []*event.Event{joined, locked, resolved(true), settled, claimed, closed}, ProgressClaimed},
+		{"withdrawn before close", []*event.Event{joined, locked, resolved(false), settled, withdrawn, closed}, ProgressWithdrawn},
+		{"close applied before the claim in store order", []*event.Event{closed, joined, claimed}, ProgressClaimed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			projection := NewProgressProjection()
+			for _, e := range tt.events {
+				projection.Apply(e)
+			}
+			got, ok := projection.Progress(episodeAddress, member)
+			if !ok || got != tt.want {
+				t.Errorf("Progress = %q, %v; want %q", got, ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestProgressProjectionRequiresJoin(t *testing.T) {
+	projection := NewProgressProjection()
+	for _, e := range lifecycle(event.NameEpisodeLocked, event.NameEpisodeSettled, event.NameEpisodeClosed) {
+		projection.Apply(e)
+	}
+	if got, ok := projection.Progress(episodeAddress, member); ok {
+		t.Errorf("Progress of a non-member = %q, want none", got)
+	}
+	if projection.LastSequence() != 3 {
+		t.Errorf("LastSequence = %d, want 3", projection.LastSequence())
+	}
+}
@@ -0,0 +1,26 @@
+package membership
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrDuplicate is returned by Create when the user already has a UserEpisode for the episode
+var ErrDuplicate = errors.New("user episode already exists")
+
+// Repository defines the interface for UserEpisode repository.
+// Addresses are stored and matched in lowercase; (user, episode) is unique.
+type Repository interface {
+	// Create persists a new UserEpisode and returns it with its assigned ID and creation time
+	Create(ctx context.Context, userEpisode *UserEpisode) (*UserEpisode, error)
+	// Update persists the progress of an existing UserEpisode
+	Update(ctx context.Context, userEpisode *UserEpisode) error
+	// FindByID returns nil when no UserEpisode has the ID
+	FindByID(ctx context.Context, id int64) (*UserEpisode, error)
+	// FindByUserAndEpisode returns nil when the user has no UserEpisode for the episode
+	FindByUserAndEpisode(ctx context.Context, user, episode string) (*UserEpisode, error)
+	FindByUser(ctx context.Context, user string) ([]*UserEpisode, error)
+	FindByEpisode(ctx context.Context, episode string) ([]*UserEpisode, error)
+	// FindWithoutProgress returns the UserEpisodes whose progress has not been set yet
+	FindWithoutProgress(ctx context.Context) ([]*UserEpisode, error)
+}
@@ -0,0 +1,67 @@
+package membership
+
+import (
+	"time"
+
+	"eventsure-server/domain/chain"
+)
+
+// UserEpisode is the Aggregate Root linking a user (wallet address) to an episode they joined
+type UserEpisode struct {
+	id        int64
+	user      string
+	episode   string
+	progress  Progress
+	createdAt time.Time
+}
+
+// NewUserEpisode creates a new, not yet persisted UserEpisode.
+// Addresses are kept in canonical lowercase so case differences never identify different wallets or episodes.
+func NewUserEpisode(user, episode chain.Address) *UserEpisode {
+	return &UserEpisode{
+		user:      user.String(),
+		episode:   episode.String(),
+		createdAt: time.Now().UTC(),
+	}
+}
+
+// RestoreUserEpisode rebuilds a persisted UserEpisode from storage
+func RestoreUserEpisode(id int64, user, episode string, progress Progress, createdAt time.Time) *UserEpisode {
+	return &UserEpisode{
+		id:        id,
+		user:      user,
+		episode:   episode,
+		progress:  progress,
+		createdAt: createdAt,
+	}
+}
+
+// ID returns the user episode ID (zero until persisted)
+func (u *UserEpisode) ID() int64 {
+	return u.id
+}
+
+// User returns the user address
+func (u *UserEpisode) User() string {
+	return u.user
+}
+
+// Episode returns the episode contract address
+func (u *UserEpisode) Episode() string {
+	return u.episode
+}
+
+// Progress returns the user's progress in the episode, empty until the join is indexed
+func (u *UserEpisode) Progress() Progress {
+	return u.progress
+}
+
+// SetProgress sets the user's progress in the episode
+func (u *UserEpisode) SetProgress(progress Progress) {
+	u.progress = progress
+}
+
+// CreatedAt returns the creation time
+func (u *UserEpisode) CreatedAt() time.Time {
+	return u.createdAt
+}
@@ -0,0 +1,166 @@
+package pricing
+
+import (
+	"strings"
+	"time"
+)
+
+// DelayThreshold is the arrival delay beyond which FlightOracle.resolveEpisode
+// reports the insured event as occurred
+const DelayThreshold = 2 * time.Hour
+
+// priorStrength is the number of pseudo-observations the broader group's rate contributes
+// to a group's estimate, so routes with few flights are pulled towards the carrier
+// and overall rates instead of reporting 0% or 100%
+const priorStrength = 10.0
+
+// Arrival is a historical flight arrival record
+type Arrival struct {
+	Route            string
+	Carrier          string
+	FlightNumber     string
+	ScheduledArrival time.Time
+	ActualArrival    time.Time
+}
+
+// Delay returns how late the flight arrived
+func (a Arrival) Delay() time.Duration {
+	return a.ActualArrival.Sub(a.ScheduledArrival)
+}
+
+// Delayed reports whether the arrival would have triggered a payout
+func (a Arrival) Delayed() bool {
+	return a.Delay() > DelayThreshold
+}
+
+// Level identifies the dataset group an estimate was primarily derived from
+type Level string
+
+const (
+	LevelRouteCarrier Level = "route+carrier"
+	LevelRoute        Level = "route"
+	LevelCarrier      Level = "carrier"
+	LevelAll          Level = "all"
+)
+
+// counts holds observation counts of a dataset group
+type counts struct {
+	observations int
+	delayed      int
+}
+
+// Estimate is the estimated probability that a flight exceeds DelayThreshold.
+// The probability follows a Beta(Alpha, Beta) posterior.
+type Estimate struct {
+	Route        string
+	Carrier      string
+	Level        Level
+	Observations int
+	Delayed      int
+	Alpha        float64
+	Beta         float64
+}
+
+// Probability returns the posterior mean delay probability
+func (e Estimate) Probability() float64 {
+	return e.Alpha / (e.Alpha + e.Beta)
+}
+
+// Quantile returns the q-quantile of the delay probability
+func (e Estimate) Quantile(q float64) float64 {
+	return betaQuantile(q, e.Alpha, e.Beta)
+}
+
+// Model estimates delay probabilities from historical arrivals grouped by route and carrier
+type Model struct {
+	all            counts
+	byRoute        map[string]*counts
+	byCarrier      map[string]*counts
+	byRouteCarrier map[string]*counts
+}
+
+// NewModel builds a Model from historical arrivals
+func NewModel(arrivals []Arrival) *Model {
+	m := &Model{
+		byRoute:        make(map[string]*counts),
+		byCarrier:      make(map[string]*counts),
+		byRouteCarrier: make(map[string]*counts),
+	}
+	for _, a := range arrivals {
+		route := normalize(a.Route)
+		carrier := normalize(a.Carrier)
+		for _, c := range []*counts{
+			&m.all,
+			group(m.byRoute, route),
+			group(m.byCarrier, carrier),
+			group(m.byRouteCarrier, route+"|"+carrier),
+		} {
+			c.observations++
+			if a.Delayed() {
+				c.delayed++
+			}
+		}
+	}
+	return m
+}
+
+// Observations returns the number of arrivals in the model
+func (m *Model) Observations() int {
+	return m.all.observations
+}
+
+// Estimate returns the delay probability for a route and carrier.
+// Each level uses the next broader level as its prior: all → carrier → route → route+carrier.
+func (m *Model) Estimate(route, carrier string) Estimate {
+	route = normalize(route)
+	carrier = normalize(carrier)
+
+	// The overall rate starts from a uniform Beta(1, 1) prior
+	level := LevelAll
+	observations, delayed := m.all.observations, m.all.delayed
+	alpha := 1 + float64(delayed)
+	beta := 1 + float64(observations-delayed)
+
+	for _, step := range []struct {
+		level Level
+		c     *counts
+	}{
+		{LevelCarrier, m.byCarrier[carrier]},
+		{LevelRoute, m.byRoute[route]},
+		{LevelRouteCarrier, m.byRouteCarrier[route+"|"+carrier]},
+	} {
+		if step.c == nil || step.c.observations == 0 {
+			continue
+		}
+		prior := alpha / (alpha + beta)
+		alpha = priorStrength*prior + float64(step.c.delayed)
+		beta = priorStrength*(1-prior) + float64(step.c.observations-step.c.delayed)
+		level = step.level
+		observations, delayed = step.c.observations, step.c.delayed
+	}
+
+	return Estimate{
+		Route:        route,
+		Carrier:      carrier,
+		Level:        level,
+		Observations: observations,
+		Delayed:      delayed,
+		Alpha:        alpha,
+		Beta:         beta,
+	}
+}
+
+// group returns the counts for key, creating them on first use
+func group(groups map[string]*counts, key string) *counts {
+	c, ok := groups[key]
+	if !ok {
+		c = &counts{}
+		groups[key] = c
+	}
+	return c
+}
+
+// normalize normalizes a route or carrier code for grouping
+func normalize(s string) string {
+	return strings.ToUpper(strings.TrimSpace(s))
+}
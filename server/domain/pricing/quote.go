@@ -0,0 +1,142 @@
+package pricing
+
+import (
+	"errors"
+	"math/big"
+)
+
+// Quote is a recommended premium/payout pair for an episode
+type Quote struct {
+	Estimate Estimate
+	// Premium and Payout are per member, in wei
+	Premium *big.Int
+	Payout  *big.Int
+	// PricedProbability is the Confidence-quantile of the delay probability the premium is priced at
+	PricedProbability float64
+	Confidence        float64
+	Loading           float64
+	// TargetSolvency is the pool solvency the quote was asked for, 0 when none was
+	TargetSolvency float64
+	// ExpectedLossRatio is the expected payout divided by the premium at the mean delay probability
+	ExpectedLossRatio float64
+	// CoverageRatio is the share of the promised payout the pool can fund when the event occurs.
+	// All members of an episode insure the same flight, so Episode.settle() caps the
+	// total payout at the total premium, i.e. at Premium/Payout per member.
+	CoverageRatio float64
+	// Solvency is the probability that the pool pays every claim, at the Confidence level of the estimate.
+	// Claims are all or nothing, so it is 1 when the premium funds the payout and 1 - PricedProbability otherwise.
+	// It is at least TargetSolvency.
+	Solvency float64
+}
+
+// QuoteParams are the inputs of a quote. Exactly one of Premium and Payout must be set;
+// the other is derived from it.
+type QuoteParams struct {
+	Premium *big.Int
+	Payout  *big.Int
+	// Confidence is the probability, over the uncertainty of the estimate, that the true delay
+	// probability does not exceed the one the premium is priced at, in (0, 1).
+	// It bounds estimation error only; pool solvency is reported by Quote.Solvency.
+	Confidence float64
+	// Loading is the safety margin added on top of the expected loss, e.g. 0.1 for 10%
+	Loading float64
+	// TargetSolvency is the least probability, in [0, 1], that the pool pays every claim; 0 for none.
+	// Members of an episode insure the same flight, so below 1 - PricedProbability it can only be
+	// reached by a premium funding the whole payout.
+	TargetSolvency float64
+}
+
+// NewQuote prices a premium/payout pair so that, with probability Confidence over the
+// uncertainty of the estimate, the premium covers the expected loss plus loading:
+//
+//	premium = payout × quantile(Confidence) × (1 + loading)
+//
+// The expected loss is what a member costs an insurer pooling many independent flights.
+// An episode pools a single flight, so such a premium is below the payout and the pool
+// cannot pay every claim when the event occurs; CoverageRatio and Solvency report by how much.
+// When that solvency is below TargetSolvency, the pair is priced to fund the whole payout:
+// the premium is raised to the payout, or the payout lowered to the premium.
+func NewQuote(estimate Estimate, params QuoteParams) (*Quote, error) {
+	if (params.Premium == nil) == (params.Payout == nil) {
+		return nil, errors.New("exactly one of premium and payout must be given")
+	}
+	if params.Premium != nil && params.Premium.Sign() <= 0 {
+		return nil, errors.New("premium must be positive")
+	}
+	if params.Payout != nil && params.Payout.Sign() <= 0 {
+		return nil, errors.New("payout must be positive")
+	}
+	if params.Confidence <= 0 || params.Confidence >= 1 {
+		return nil, errors.New("confidence must be between 0 and 1")
+	}
+	if params.Loading < 0 {
+		return nil, errors.New("loading must not be negative")
+	}
+	if params.TargetSolvency < 0 || params.TargetSolvency > 1 {
+		return nil, errors.New("target solvency must be between 0 and 1")
+	}
+
+	priced := estimate.Quantile(params.Confidence)
+	rate := new(big.Rat).SetFloat64(priced * (1 + params.Loading))
+	if rate == nil || rate.Sign() == 0 {
+		return nil, errors.New("estimated delay probability is zero")
+	}
+
+	quote := &Quote{
+		Estimate:          estimate,
+		PricedProbability: priced,
+		Confidence:        params.Confidence,
+		Loading:           params.Loading,
+		TargetSolvency:    params.TargetSolvency,
+	}
+
+	if params.Payout != nil {
+		// Round the premium up so the pool is never under-priced by rounding
+		quote.Payout = new(big.Int).Set(params.Payout)
+		quote.Premium = ceilRat(new(big.Rat).Mul(new(big.Rat).SetInt(params.Payout), rate))
+	} else {
+		// Round the payout down for the same reason
+		quote.Premium = new(big.Int).Set(params.Premium)
+		quote.Payout = floorRat(new(big.Rat).Quo(new(big.Rat).SetInt(params.Premium), rate))
+	}
+
+	// Without full funding the pool pays every claim only when the flight is not delayed
+	if quote.Premium.Cmp(quote.Payout) < 0 && 1-priced < params.TargetSolvency {
+		if params.Payout != nil {
+			quote.Premium = new(big.Int).Set(quote.Payout)
+		} else {
+			quote.Payout = new(big.Int).Set(quote.Premium)
+		}
+	}
+
+	if quote.Premium.Sign() > 0 {
+		expected := new(big.Rat).Mul(new(big.Rat).SetInt(quote.Payout), new(big.Rat).SetFloat64(estimate.Probability()))
+		quote.ExpectedLossRatio, _ = expected.Quo(expected, new(big.Rat).SetInt(quote.Premium)).Float64()
+	}
+	if quote.Payout.Sign() > 0 {
+		quote.CoverageRatio, _ = new(big.Rat).SetFrac(quote.Premium, quote.Payout).Float64()
+		if quote.CoverageRatio > 1 {
+			quote.CoverageRatio = 1
+		}
+	}
+	quote.Solvency = 1
+	if quote.CoverageRatio < 1 {
+		quote.Solvency = 1 - priced
+	}
+
+	return quote, nil
+}
+
+// ceilRat rounds a non-negative rational up to an integer
+func ceilRat(r *big.Rat) *big.Int {
+	q, m := new(big.Int).QuoRem(r.Num(), r.Denom(), new(big.Int))
+	if m.Sign() > 0 {
+		q.Add(q, big.NewInt(1))
+	}
+	return q
+}
+
+// floorRat rounds a non-negative rational down to an integer
+func floorRat(r *big.Rat) *big.Int {
+	return new(big.Int).Quo(r.Num(), r.Denom())
+}
@@ -0,0 +1,215 @@
+package pricing
+
+import (
+	"math"
+	"math/big"
+	"testing"
+)
+
+// ether is 10^18 wei
+var ether = new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil)
+
+func TestNewQuotePremiumFromPayout(t *testing.T) {
+	estimate := Estimate{Alpha: 9, Beta: 91}
+	tests := []struct {
+		name       string
+		confidence float64
+		loading    float64
+	}{
+		{"median, no loading", 0.5, 0},
+		{"default confidence", 0.95, 0},
+		{"loading", 0.95, 0.1},
+		{"high confidence", 0.99, 0.25},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			quote, err := NewQuote(estimate, QuoteParams{Payout: ether, Confidence: tt.confidence, Loading: tt.loading})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if quote.Payout.Cmp(ether) != 0 {
+				t.Errorf("payout = %s, want %s", quote.Payout, ether)
+			}
+
+			priced := estimate.Quantile(tt.confidence)
+			if quote.PricedProbability != priced {
+				t.Errorf("priced probability = %v, want %v", quote.PricedProbability, priced)
+			}
+
+			// premium = ceil(payout × quantile × (1 + loading))
+			exact := new(big.Rat).Mul(new(big.Rat).SetInt(ether), new(big.Rat).SetFloat64(priced*(1+tt.loading)))
+			if new(big.Rat).SetInt(quote.Premium).Cmp(exact) < 0 {
+				t.Errorf("premium %s is below payout × rate %s", quote.Premium, exact.FloatString(0))
+			}
+			below := new(big.Int).Sub(quote.Premium, big.NewInt(1))
+			if new(big.Rat).SetInt(below).Cmp(exact) >= 0 {
+				t.Errorf("premium %s is not rounded up to the nearest wei of %s", quote.Premium, exact.FloatString(2))
+			}
+
+			premium, _ := new(big.Rat).SetInt(quote.Premium).Float64()
+			wantLossRatio := 1e18 * estimate.Probability() / premium
+			if math.Abs(quote.ExpectedLossRatio-wantLossRatio) > 1e-9 {
+				t.Errorf("expected loss ratio = %v, want %v", quote.ExpectedLossRatio, wantLossRatio)
+			}
+		})
+	}
+}
+
+func TestNewQuotePayoutFromPremium(t *testing.T) {
+	estimate := Estimate{Alpha: 9, Beta: 91}
+	premium := big.NewInt(1_000_000_007)
+	quote, err := NewQuote(estimate, QuoteParams{Premium: premium, Confidence: 0.95, Loading: 0.1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if quote.Premium.Cmp(premium) != 0 {
+		t.Errorf("premium = %s, want %s", quote.Premium, premium)
+	}
+
+	// payout = floor(premium / (quantile × (1 + loading)))
+	rate := new(big.Rat).SetFloat64(quote.PricedProbability * 1.1)
+	exact := new(big.Rat).Quo(new(big.Rat).SetInt(premium), rate)
+	if new(big.Rat).SetInt(quote.Payout).Cmp(exact) > 0 {
+		t.Errorf("payout %s exceeds premium / rate %s", quote.Payout, exact.FloatString(2))
+	}
+	above := new(big.Int).Add(quote.Payout, big.NewInt(1))
+	if new(big.Rat).SetInt(above).Cmp(exact) <= 0 {
+		t.Errorf("payout %s is not rounded down to the nearest wei of %s", quote.Payout, exact.FloatString(2))
+	}
+}
+
+func TestNewQuoteConfidenceRaisesPremium(t *testing.T) {
+	estimate := Estimate{Alpha: 3, Beta: 27}
+	var previous *big.Int
+	for _, confidence := range []float64{0.5, 0.8, 0.95, 0.99} {
+		quote, err := NewQuote(estimate, QuoteParams{Payout: ether, Confidence: confidence})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if previous != nil && quote.Premium.Cmp(previous) <= 0 {
+			t.Errorf("premium at confidence %v = %s, want more than %s", confidence, quote.Premium, previous)
+		}
+		previous = quote.Premium
+	}
+}
+
+func TestNewQuotePooledCap(t *testing.T) {
+	tests := []struct {
+		name         string
+		estimate     Estimate
+		loading      float64
+		wantCoverage func(q *Quote) float64
+		wantSolvency func(q *Quote) float64
+	}{
+		{
+			// The premium is a fraction of the payout: the pool funds that fraction of the claims
+			// and pays them all only when the flight is not delayed
+			name:     "premium below payout",
+			estimate: Estimate{Alpha: 9, Beta: 91},
+			wantCoverage: func(q *Quote) float64 {
+				r, _ := new(big.Rat).SetFrac(q.Premium, q.Payout).Float64()
+				return r
+			},
+			wantSolvency: func(q *Quote) float64 { return 1 - q.PricedProbability },
+		},
+		{
+			// A delay is so likely that the loaded premium exceeds the payout
+			name:         "premium above payout",
+			estimate:     Estimate{Alpha: 95, Beta: 5},
+			loading:      0.5,
+			wantCoverage: func(*Quote) float64 { return 1 },
+			wantSolvency: func(*Quote) float64 { return 1 },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			quote, err := NewQuote(tt.estimate, QuoteParams{Payout: ether, Confidence: 0.95, Loading: tt.loading})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := tt.wantCoverage(quote); quote.CoverageRatio != want {
+				t.Errorf("coverage ratio = %v, want %v", quote.CoverageRatio, want)
+			}
+			if want := tt.wantSolvency(quote); quote.Solvency != want {
+				t.Errorf("solvency = %v, want %v", quote.Solvency, want)
+			}
+		})
+	}
+}
+
+func TestNewQuoteTargetSolvency(t *testing.T) {
+	// The priced probability at 95% confidence is about 0.14, so without full funding the pool is ~86% solvent
+	estimate := Estimate{Alpha: 9, Beta: 91}
+	premium := big.NewInt(1_000_000_007)
+
+	tests := []struct {
+		name           string
+		params         QuoteParams
+		wantFunded     bool
+		wantPremiumSet bool
+	}{
+		{"no target", QuoteParams{Payout: ether}, false, false},
+		{"target met by the expected loss premium", QuoteParams{Payout: ether, TargetSolvency: 0.8}, false, false},
+		{"target above the no-delay probability raises the premium", QuoteParams{Payout: ether, TargetSolvency: 0.95}, true, false},
+		{"full solvency raises the premium", QuoteParams{Payout: ether, TargetSolvency: 1}, true, false},
+		{"target above the no-delay probability lowers the payout", QuoteParams{Premium: premium, TargetSolvency: 0.95}, true, true},
+		{"target met from the premium", QuoteParams{Premium: premium, TargetSolvency: 0.5}, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.params.Confidence = 0.95
+			quote, err := NewQuote(estimate, tt.params)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if quote.TargetSolvency != tt.params.TargetSolvency {
+				t.Errorf("target solvency = %v, want %v", quote.TargetSolvency, tt.params.TargetSolvency)
+			}
+			if quote.Solvency < tt.params.TargetSolvency {
+				t.Errorf("solvency %v is below the target %v", quote.Solvency, tt.params.TargetSolvency)
+			}
+			if tt.wantPremiumSet && quote.Premium.Cmp(premium) != 0 {
+				t.Errorf("premium = %s, want the given %s", quote.Premium, premium)
+			}
+			if !tt.wantPremiumSet && quote.Payout.Cmp(ether) != 0 {
+				t.Errorf("payout = %s, want the given %s", quote.Payout, ether)
+			}
+
+			funded := quote.Premium.Cmp(quote.Payout) == 0
+			if funded != tt.wantFunded {
+				t.Fatalf("premium %s, payout %s: fully funded = %v, want %v", quote.Premium, quote.Payout, funded, tt.wantFunded)
+			}
+			if funded && (quote.Solvency != 1 || quote.CoverageRatio != 1) {
+				t.Errorf("fully funded quote has solvency %v, coverage %v; want 1, 1", quote.Solvency, quote.CoverageRatio)
+			}
+			if !funded && quote.Solvency != 1-quote.PricedProbability {
+				t.Errorf("solvency = %v, want %v", quote.Solvency, 1-quote.PricedProbability)
+			}
+		})
+	}
+}
+
+func TestNewQuoteRejectsInvalidParams(t *testing.T) {
+	estimate := Estimate{Alpha: 9, Beta: 91}
+	tests := []struct {
+		name   string
+		params QuoteParams
+	}{
+		{"neither amount", QuoteParams{Confidence: 0.95}},
+		{"both amounts", QuoteParams{Premium: ether, Payout: ether, Confidence: 0.95}},
+		{"zero payout", QuoteParams{Payout: big.NewInt(0), Confidence: 0.95}},
+		{"negative premium", QuoteParams{Premium: big.NewInt(-1), Confidence: 0.95}},
+		{"zero confidence", QuoteParams{Payout: ether}},
+		{"confidence of one", QuoteParams{Payout: ether, Confidence: 1}},
+		{"negative loading", QuoteParams{Payout: ether, Confidence: 0.95, Loading: -0.1}},
+		{"negative target solvency", QuoteParams{Payout: ether, Confidence: 0.95, TargetSolvency: -0.1}},
+		{"target solvency above one", QuoteParams{Payout: ether, Confidence: 0.95, TargetSolvency: 1.5}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewQuote(estimate, tt.params); err == nil {
+				t.Error("NewQuote succeeded, want an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package simulation
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
+)
+
+// MemberDistribution is the distribution of the number of members joining an episode
+type MemberDistribution interface {
+	Sample(r *rand.Rand) int
+	String() string
+}
+
+// FixedMembers always yields the same member count
+type FixedMembers int
+
+// Sample returns the fixed member count
+func (f FixedMembers) Sample(_ *rand.Rand) int {
+	return int(f)
+}
+
+func (f FixedMembers) String() string {
+	return fmt.Sprintf("fixed(%d)", int(f))
+}
+
+// UniformMembers yields a member count uniformly in [Min, Max]
+type UniformMembers struct {
+	Min int
+	Max int
+}
+
+// Sample draws a member count
+func (u UniformMembers) Sample(r *rand.Rand) int {
+	return u.Min + r.Intn(u.Max-u.Min+1)
+}
+
+func (u UniformMembers) String() string {
+	return fmt.Sprintf("uniform(%d,%d)", u.Min, u.Max)
+}
+
+// PoissonMembers yields a Poisson distributed member count with the given mean
+type PoissonMembers struct {
+	Mean float64
+}
+
+// Sample draws a member count using Knuth's algorithm for small means
+// and a rounded normal approximation for large ones
+func (p PoissonMembers) Sample(r *rand.Rand) int {
+	if p.Mean > 500 {
+		n := int(math.Round(p.Mean + math.Sqrt(p.Mean)*r.NormFloat64()))
+		if n < 0 {
+			return 0
+		}
+		return n
+	}
+
+	limit := math.Exp(-p.Mean)
+	n := 0
+	for product := r.Float64(); product > limit; product *= r.Float64() {
+		n++
+	}
+	return n
+}
+
+func (p PoissonMembers) String() string {
+	return fmt.Sprintf("poisson(%g)", p.Mean)
+}
+
+// MaxMembers bounds the member counts and the Poisson mean a distribution accepts,
+// so that sampling cannot overflow and trials stay affordable
+const MaxMembers = 1_000_000
+
+// ParseMemberDistribution parses "fixed:N", "uniform:MIN-MAX" or "poisson:MEAN",
+// with counts and mean between 0 and MaxMembers
+func ParseMemberDistribution(s string) (MemberDistribution, error) {
+	kind, value, _ := strings.Cut(s, ":")
+	switch kind {
+	case "fixed":
+		n, err := parseMemberCount(value)
+		if err != nil {
+			return nil, err
+		}
+		return FixedMembers(n), nil
+	case "uniform":
+		low, high, ok := strings.Cut(value, "-")
+		if !ok {
+			break
+		}
+		min, err := parseMemberCount(low)
+		if err != nil {
+			return nil, err
+		}
+		max, err := parseMemberCount(high)
+		if err != nil {
+			return nil, err
+		}
+		if max < min {
+			return nil, fmt.Errorf("uniform member range must satisfy min <= max")
+		}
+		return UniformMembers{Min: min, Max: max}, nil
+	case "poisson":
+		mean, err := strconv.ParseFloat(value, 64)
+		if err != nil || math.IsNaN(mean) {
+			break
+		}
+		if mean < 0 || mean > MaxMembers {
+			return nil, fmt.Errorf("poisson mean must be between 0 and %d", MaxMembers)
+		}
+		return PoissonMembers{Mean: mean}, nil
+	}
+	return nil, fmt.Errorf("invalid member distribution %q (expected fixed:N, uniform:MIN-MAX or poisson:MEAN)", s)
+}
+
+// parseMemberCount parses a member count between 0 and MaxMembers
+func parseMemberCount(s string) (int, error) {
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid member count %q", s)
+	}
+	if n < 0 || n > MaxMembers {
+		return 0, fmt.Errorf("member count must be between 0 and %d", MaxMembers)
+	}
+	return n, nil
+}
@@ -0,0 +1,85 @@
+package simulation
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestParseMemberDistribution(t *testing.T) {
+	tests := []struct {
+		in   string
+		want MemberDistribution
+	}{
+		{"fixed:0", FixedMembers(0)},
+		{"fixed:20", FixedMembers(20)},
+		{"fixed:1000000", FixedMembers(MaxMembers)},
+		{"uniform:5-30", UniformMembers{Min: 5, Max: 30}},
+		{"uniform:7-7", UniformMembers{Min: 7, Max: 7}},
+		{"uniform:0-1000000", UniformMembers{Min: 0, Max: MaxMembers}},
+		{"poisson:20", PoissonMembers{Mean: 20}},
+		{"poisson:0.5", PoissonMembers{Mean: 0.5}},
+		{"poisson:1e6", PoissonMembers{Mean: MaxMembers}},
+	}
+	for _, tt := range tests {
+		got, err := ParseMemberDistribution(tt.in)
+		if err != nil {
+			t.Errorf("ParseMemberDistribution(%q) error = %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseMemberDistribution(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseMemberDistributionRejects(t *testing.T) {
+	for _, in := range []string{
+		"",
+		"fixed",
+		"fixed:",
+		"fixed:-1",
+		"fixed:1000001",
+		"fixed:20x",
+		"fixed:2.5",
+		"fixed: 20",
+		"uniform:5",
+		"uniform:30-5",
+		"uniform:-5-30",
+		"uniform:0-9223372036854775807",
+		"uniform:5-30-40",
+		"poisson:-1",
+		"poisson:1000001",
+		"poisson:NaN",
+		"poisson:Inf",
+		"poisson:+Inf",
+		"poisson:20x",
+		"binomial:20",
+		"20",
+	} {
+		if got, err := ParseMemberDistribution(in); err == nil {
+			t.Errorf("ParseMemberDistribution(%q) = %v, want error", in, got)
+		}
+	}
+}
+
+func TestMemberDistributionSample(t *testing.T) {
+	tests := []struct {
+		dist     MemberDistribution
+		min, max int
+	}{
+		{FixedMembers(12), 12, 12},
+		{UniformMembers{Min: 5, Max: 30}, 5, 30},
+		{UniformMembers{Min: 0, Max: MaxMembers}, 0, MaxMembers},
+		{PoissonMembers{Mean: 0}, 0, 0},
+		{PoissonMembers{Mean: 20}, 0, 200},
+		{PoissonMembers{Mean: MaxMembers}, MaxMembers - 10000, MaxMembers + 10000},
+	}
+	for _, tt := range tests {
+		r := rand.New(rand.NewSource(1))
+		for i := 0; i < 1000; i++ {
+			if n := tt.dist.Sample(r); n < tt.min || n > tt.max {
+				t.Fatalf("%v.Sample() = %d, want within [%d, %d]", tt.dist, n, tt.min, tt.max)
+			}
+		}
+	}
+}
@@ -0,0 +1,252 @@
+package simulation
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"math/rand"
+	"sort"
+)
+
+// DefaultTrials is the number of trials run when none is given
+const DefaultTrials = 10000
+
+// Params are the episode parameters to simulate
+type Params struct {
+	Premium          *big.Int
+	Payout           *big.Int
+	Members          MemberDistribution
+	EventProbability float64
+	Trials           int
+	Seed             int64
+}
+
+// Trial is the outcome of a single join/settle/claim run.
+// It follows Episode.settle(), Episode.claim() and Episode.withdrawSurplus():
+// settle() caps totalPayout at totalPremium, but claim() always sends PAYOUT_AMOUNT,
+// so once the balance runs out the remaining claims revert with TransferFailed.
+// withdrawSurplus() reverts when the event occurred, so any balance left after claims stays locked.
+type Trial struct {
+	Members          int
+	EventOccurred    bool
+	TotalPremium     *big.Int
+	TotalPayout      *big.Int
+	Surplus          *big.Int
+	PayoutPaid       *big.Int
+	PayoutShortfall  *big.Int
+	SuccessfulClaims int
+	FailedClaims     int
+	SurplusPerMember *big.Int
+	Stranded         *big.Int
+}
+
+// RunTrial simulates a single episode with the given member count and outcome
+func RunTrial(premium, payout *big.Int, members int, eventOccurred bool) Trial {
+	n := big.NewInt(int64(members))
+	totalPremium := new(big.Int).Mul(premium, n)
+
+	trial := Trial{
+		Members:          members,
+		EventOccurred:    eventOccurred,
+		TotalPremium:     totalPremium,
+		TotalPayout:      new(big.Int),
+		Surplus:          new(big.Int),
+		PayoutPaid:       new(big.Int),
+		PayoutShortfall:  new(big.Int),
+		SurplusPerMember: new(big.Int),
+		Stranded:         new(big.Int),
+	}
+
+	if !eventOccurred {
+		trial.Surplus.Set(totalPremium)
+		if members == 0 {
+			return trial
+		}
+		// amount = premiumOf[member] * surplus / totalPremium
+		trial.SurplusPerMember.Mul(premium, trial.Surplus)
+		trial.SurplusPerMember.Quo(trial.SurplusPerMember, totalPremium)
+		withdrawn := new(big.Int).Mul(trial.SurplusPerMember, n)
+		trial.Stranded.Sub(totalPremium, withdrawn)
+		return trial
+	}
+
+	potentialPayout := new(big.Int).Mul(payout, n)
+	if potentialPayout.Cmp(totalPremium) > 0 {
+		trial.TotalPayout.Set(totalPremium)
+	} else {
+		trial.TotalPayout.Set(potentialPayout)
+	}
+	trial.Surplus.Sub(totalPremium, trial.TotalPayout)
+
+	// Every member claims PAYOUT_AMOUNT until the balance can no longer cover it
+	paid := members
+	if payout.Sign() > 0 {
+		affordable := new(big.Int).Quo(totalPremium, payout)
+		if affordable.Cmp(n) < 0 {
+			paid = int(affordable.Int64())
+		}
+	}
+	trial.SuccessfulClaims = paid
+	trial.FailedClaims = members - paid
+	trial.PayoutPaid.Mul(payout, big.NewInt(int64(paid)))
+	trial.PayoutShortfall.Sub(potentialPayout, trial.PayoutPaid)
+	trial.Stranded.Sub(totalPremium, trial.PayoutPaid)
+
+	return trial
+}
+
+// Distribution summarises a sample of values
+type Distribution struct {
+	Mean float64
+	P50  float64
+	P95  float64
+	P99  float64
+	Max  float64
+}
+
+// newDistribution computes the distribution of values; values is sorted in place
+func newDistribution(values []float64) Distribution {
+	if len(values) == 0 {
+		return Distribution{}
+	}
+	sort.Float64s(values)
+
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+
+	return Distribution{
+		Mean: sum / float64(len(values)),
+		P50:  percentile(values, 0.50),
+		P95:  percentile(values, 0.95),
+		P99:  percentile(values, 0.99),
+		Max:  values[len(values)-1],
+	}
+}
+
+// percentile returns the nearest-rank percentile of sorted values
+func percentile(sorted []float64, p float64) float64 {
+	rank := int(math.Ceil(p*float64(len(sorted)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	return sorted[rank]
+}
+
+// Violation reports a docs/2_spec.md invariant broken in some of the trials
+type Violation struct {
+	Invariant   int
+	Description string
+	Trials      int
+	Probability float64
+}
+
+// Report is the result of a simulation run.
+// Amounts are in wei; shortfall and failed claims are over trials where the event occurred,
+// surplus per member over trials where it did not.
+type Report struct {
+	Params               Params
+	Trials               int
+	EventTrials          int
+	MeanMembers          float64
+	PayoutShortfall      Distribution
+	FailedClaims         Distribution
+	SurplusPerMember     Distribution
+	Stranded             Distribution
+	ShortfallProbability float64
+	Violations           []Violation
+}
+
+// Run runs params.Trials trials of join/settle/claim and reports the outcome distributions
+func Run(params Params) (*Report, error) {
+	if params.Premium == nil || params.Premium.Sign() <= 0 {
+		return nil, errors.New("premium must be positive")
+	}
+	if params.Payout == nil || params.Payout.Sign() <= 0 {
+		return nil, errors.New("payout must be positive")
+	}
+	if params.Members == nil {
+		return nil, errors.New("member distribution is required")
+	}
+	if params.EventProbability < 0 || params.EventProbability > 1 {
+		return nil, errors.New("event probability must be between 0 and 1")
+	}
+	if params.Trials <= 0 {
+		params.Trials = DefaultTrials
+	}
+
+	r := rand.New(rand.NewSource(params.Seed))
+
+	var (
+		shortfalls, failedClaims, surplusShares, stranded  []float64
+		memberSum                                          int
+		shortfallTrials, failedClaimTrials, strandedTrials int
+	)
+
+	for i := 0; i < params.Trials; i++ {
+		members := params.Members.Sample(r)
+		eventOccurred := r.Float64() < params.EventProbability
+		trial := RunTrial(params.Premium, params.Payout, members, eventOccurred)
+
+		memberSum += members
+		stranded = append(stranded, toFloat(trial.Stranded))
+		if trial.Stranded.Sign() > 0 {
+			strandedTrials++
+		}
+
+		if eventOccurred {
+			shortfalls = append(shortfalls, toFloat(trial.PayoutShortfall))
+			failedClaims = append(failedClaims, float64(trial.FailedClaims))
+			if trial.PayoutShortfall.Sign() > 0 {
+				shortfallTrials++
+			}
+			if trial.FailedClaims > 0 {
+				failedClaimTrials++
+			}
+		} else if members > 0 {
+			surplusShares = append(surplusShares, toFloat(trial.SurplusPerMember))
+		}
+	}
+
+	report := &Report{
+		Params:           params,
+		Trials:           params.Trials,
+		EventTrials:      len(shortfalls),
+		MeanMembers:      float64(memberSum) / float64(params.Trials),
+		PayoutShortfall:  newDistribution(shortfalls),
+		FailedClaims:     newDistribution(failedClaims),
+		SurplusPerMember: newDistribution(surplusShares),
+		Stranded:         newDistribution(stranded),
+		Violations:       []Violation{},
+	}
+	report.ShortfallProbability = float64(shortfallTrials) / float64(params.Trials)
+
+	if failedClaimTrials > 0 {
+		report.Violations = append(report.Violations, Violation{
+			Invariant: 2,
+			Description: "payout exceeds premium and the pool cannot cover every claim: " +
+				"claim() sends the fixed payout until the balance runs out, so late claimers are left " +
+				"uncovered and the loss is borne by claim order rather than fixed at join",
+			Trials:      failedClaimTrials,
+			Probability: float64(failedClaimTrials) / float64(params.Trials),
+		})
+	}
+	if strandedTrials > 0 {
+		report.Violations = append(report.Violations, Violation{
+			Invariant: 5,
+			Description: "funds remain in the episode after settlement that no member can withdraw: " +
+				"withdrawSurplus() reverts when the event occurred, so the unpaid balance is never distributed pro-rata",
+			Trials:      strandedTrials,
+			Probability: float64(strandedTrials) / float64(params.Trials),
+		})
+	}
+
+	return report, nil
+}
+
+// toFloat converts a wei amount to float64 for statistics
+func toFloat(v *big.Int) float64 {
+	f, _ := new(big.Float).SetInt(v).Float64()
+	return f
+}
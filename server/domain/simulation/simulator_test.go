@@ -0,0 +1,163 @@
+package simulation
+
+import (
+	"math"
+	"math/big"
+	"reflect"
+	"testing"
+)
+
+func TestRunTrial(t *testing.T) {
+	tests := []struct {
+		name             string
+		premium, payout  int64
+		members          int
+		eventOccurred    bool
+		totalPayout      int64
+		payoutPaid       int64
+		shortfall        int64
+		successfulClaims int
+		failedClaims     int
+		surplusPerMember int64
+		stranded         int64
+	}{
+		{
+			name: "no event returns every premium", premium: 10, payout: 50, members: 4,
+			surplusPerMember: 10,
+		},
+		{
+			name: "no members", premium: 10, payout: 50, members: 0,
+		},
+		{
+			name: "covered payout", premium: 10, payout: 8, members: 5, eventOccurred: true,
+			totalPayout: 40, payoutPaid: 40, successfulClaims: 5, stranded: 10,
+		},
+		{
+			name: "payout exceeds the pool", premium: 10, payout: 25, members: 5, eventOccurred: true,
+			totalPayout: 50, payoutPaid: 50, shortfall: 75, successfulClaims: 2, failedClaims: 3,
+		},
+		{
+			name: "last claim partly covered", premium: 10, payout: 30, members: 4, eventOccurred: true,
+			totalPayout: 40, payoutPaid: 30, shortfall: 90, successfulClaims: 1, failedClaims: 3, stranded: 10,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trial := RunTrial(big.NewInt(tt.premium), big.NewInt(tt.payout), tt.members, tt.eventOccurred)
+
+			amounts := []struct {
+				field string
+				got   *big.Int
+				want  int64
+			}{
+				{"TotalPremium", trial.TotalPremium, tt.premium * int64(tt.members)},
+				{"TotalPayout", trial.TotalPayout, tt.totalPayout},
+				{"PayoutPaid", trial.PayoutPaid, tt.payoutPaid},
+				{"PayoutShortfall", trial.PayoutShortfall, tt.shortfall},
+				{"SurplusPerMember", trial.SurplusPerMember, tt.surplusPerMember},
+				{"Stranded", trial.Stranded, tt.stranded},
+			}
+			for _, a := range amounts {
+				if a.got.Cmp(big.NewInt(a.want)) != 0 {
+					t.Errorf("%s = %s, want %d", a.field, a.got, a.want)
+				}
+			}
+			if trial.SuccessfulClaims != tt.successfulClaims || trial.FailedClaims != tt.failedClaims {
+				t.Errorf("claims = %d/%d, want %d/%d",
+					trial.SuccessfulClaims, trial.FailedClaims, tt.successfulClaims, tt.failedClaims)
+			}
+		})
+	}
+}
+
+func TestRunSeeded(t *testing.T) {
+	params := Params{
+		Premium:          big.NewInt(10),
+		Payout:           big.NewInt(50),
+		Members:          FixedMembers(10),
+		EventProbability: 0.3,
+		Trials:           20000,
+		Seed:             1,
+	}
+
+	report, err := Run(params)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	again, err := Run(params)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if !reflect.DeepEqual(report, again) {
+		t.Error("Run() with the same seed produced different reports")
+	}
+
+	// Every trial where the event occurs is short: 10 members pay 100 wei but are owed 500
+	if report.EventTrials == 0 || report.ShortfallProbability != float64(report.EventTrials)/float64(report.Trials) {
+		t.Errorf("ShortfallProbability = %v with %d event trials of %d",
+			report.ShortfallProbability, report.EventTrials, report.Trials)
+	}
+	if math.Abs(report.ShortfallProbability-0.3) > 0.02 {
+		t.Errorf("ShortfallProbability = %v, want about 0.3", report.ShortfallProbability)
+	}
+	if report.PayoutShortfall.Max != 400 || report.FailedClaims.Max != 8 {
+		t.Errorf("PayoutShortfall.Max = %v, FailedClaims.Max = %v, want 400 and 8",
+			report.PayoutShortfall.Max, report.FailedClaims.Max)
+	}
+	if report.MeanMembers != 10 {
+		t.Errorf("MeanMembers = %v, want 10", report.MeanMembers)
+	}
+	if len(report.Violations) != 1 || report.Violations[0].Invariant != 2 {
+		t.Errorf("Violations = %+v, want invariant 2 only", report.Violations)
+	}
+}
+
+func TestRunSolvent(t *testing.T) {
+	report, err := Run(Params{
+		Premium:          big.NewInt(50),
+		Payout:           big.NewInt(50),
+		Members:          UniformMembers{Min: 1, Max: 30},
+		EventProbability: 0.5,
+		Trials:           5000,
+		Seed:             7,
+	})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if report.ShortfallProbability != 0 || report.FailedClaims.Max != 0 {
+		t.Errorf("ShortfallProbability = %v, FailedClaims.Max = %v, want 0",
+			report.ShortfallProbability, report.FailedClaims.Max)
+	}
+	if len(report.Violations) != 0 {
+		t.Errorf("Violations = %+v, want none", report.Violations)
+	}
+}
+
+func TestRunRejectsInvalidParams(t *testing.T) {
+	valid := Params{
+		Premium:          big.NewInt(10),
+		Payout:           big.NewInt(50),
+		Members:          FixedMembers(10),
+		EventProbability: 0.3,
+	}
+	tests := []struct {
+		name   string
+		modify func(p *Params)
+	}{
+		{"missing premium", func(p *Params) { p.Premium = nil }},
+		{"zero premium", func(p *Params) { p.Premium = big.NewInt(0) }},
+		{"negative payout", func(p *Params) { p.Payout = big.NewInt(-1) }},
+		{"missing members", func(p *Params) { p.Members = nil }},
+		{"negative probability", func(p *Params) { p.EventProbability = -0.1 }},
+		{"probability above one", func(p *Params) { p.EventProbability = 1.1 }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := valid
+			tt.modify(&params)
+			if _, err := Run(params); err == nil {
+				t.Error("Run() error = nil, want error")
+			}
+		})
+	}
+}
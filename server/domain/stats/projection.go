@@ -0,0 +1,251 @@
+package stats
+
+import (
+	"math/big"
+	"sort"
+	"time"
+
+	"eventsure-server/domain/episode"
+	"eventsure-server/domain/event"
+)
+
+// Interval represents the width of a time-series bucket
+type Interval string
+
+const (
+	IntervalDaily  Interval = "daily"
+	IntervalWeekly Interval = "weekly"
+)
+
+// ParseInterval parses an interval name
+func ParseInterval(s string) (Interval, bool) {
+	switch Interval(s) {
+	case IntervalDaily, IntervalWeekly:
+		return Interval(s), true
+	}
+	return "", false
+}
+
+// BucketStart returns the start of the bucket containing t.
+// Days start at 00:00 UTC and weeks on Monday 00:00 UTC.
+func (i Interval) BucketStart(t time.Time) time.Time {
+	day := t.UTC().Truncate(24 * time.Hour)
+	if i == IntervalWeekly {
+		return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	}
+	return day
+}
+
+// Bucket aggregates joins and payouts within a time bucket
+type Bucket struct {
+	Start        time.Time
+	Joins        int
+	JoinAmount   *big.Int
+	Payouts      int
+	PayoutAmount *big.Int
+}
+
+// episodeStats is the per-episode state of the projection
+type episodeStats struct {
+	state           episode.State
+	lastTransition  *event.Event
+	premiums        *big.Int
+	payouts         *big.Int
+	surplusReturned *big.Int
+	settled         bool
+	settledPayout   *big.Int
+}
+
+// Projection incrementally aggregates protocol-wide statistics from episode events.
+// Events are applied in store sequence order; LastSequence tells consumers where to resume.
+type Projection struct {
+	lastSequence int64
+	episodes     map[string]*episodeStats
+	members      map[string]bool
+	buckets      map[Interval]map[int64]*Bucket
+}
+
+// NewProjection creates an empty Projection
+func NewProjection() *Projection {
+	return &Projection{
+		episodes: make(map[string]*episodeStats),
+		members:  make(map[string]bool),
+		buckets: map[Interval]map[int64]*Bucket{
+			IntervalDaily:  make(map[int64]*Bucket),
+			IntervalWeekly: make(map[int64]*Bucket),
+		},
+	}
+}
+
+// LastSequence returns the sequence of the last applied event
+func (p *Projection) LastSequence() int64 {
+	return p.lastSequence
+}
+
+// transitions maps lifecycle events to the state they move an episode into
+var transitions = map[event.Name]episode.State{
+	event.NameEpisodeCreated:  episode.StateCreated,
+	event.NameEpisodeOpened:   episode.StateOpen,
+	event.NameEpisodeLocked:   episode.StateLocked,
+	event.NameEpisodeResolved: episode.StateResolved,
+	event.NameEpisodeSettled:  episode.StateSettled,
+	event.NameEpisodeClosed:   episode.StateClosed,
+}
+
+// Apply folds an event into the projection
+func (p *Projection) Apply(e *event.Event) {
+	if e.Sequence > p.lastSequence {
+		p.lastSequence = e.Sequence
+	}
+
+	ep := p.episode(e.Episode)
+
+	if state, ok := transitions[e.Name]; ok {
+		if ep.lastTransition == nil || ep.lastTransition.Before(e) {
+			ep.state = state
+			ep.lastTransition = e
+		}
+	}
+
+	switch e.Name {
+	case event.NameMemberJoined:
+		ep.premiums.Add(ep.premiums, e.Amount)
+		p.members[e.Member] = true
+		for interval := range p.buckets {
+			b := p.bucket(interval, e.Timestamp)
+			b.Joins++
+			b.JoinAmount.Add(b.JoinAmount, e.Amount)
+		}
+	case event.NamePayoutClaimed:
+		ep.payouts.Add(ep.payouts, e.Amount)
+		for interval := range p.buckets {
+			b := p.bucket(interval, e.Timestamp)
+			b.Payouts++
+			b.PayoutAmount.Add(b.PayoutAmount, e.Amount)
+		}
+	case event.NameSurplusClaimed:
+		ep.surplusReturned.Add(ep.surplusReturned, e.Amount)
+	case event.NameEpisodeSettled:
+		ep.settled = true
+		ep.settledPayout = new(big.Int).Set(e.TotalPayout)
+	}
+}
+
+// episode returns the per-episode stats, creating them on first use
+func (p *Projection) episode(address string) *episodeStats {
+	ep, ok := p.episodes[address]
+	if !ok {
+		ep = &episodeStats{
+			state:           episode.StateCreated,
+			premiums:        new(big.Int),
+			payouts:         new(big.Int),
+			surplusReturned: new(big.Int),
+			settledPayout:   new(big.Int),
+		}
+		p.episodes[address] = ep
+	}
+	return ep
+}
+
+// bucket returns the bucket of interval containing t, creating it on first use
+func (p *Projection) bucket(interval Interval, t time.Time) *Bucket {
+	start := interval.BucketStart(t)
+	b, ok := p.buckets[interval][start.Unix()]
+	if !ok {
+		b = &Bucket{
+			Start:        start,
+			JoinAmount:   new(big.Int),
+			PayoutAmount: new(big.Int),
+		}
+		p.buckets[interval][start.Unix()] = b
+	}
+	return b
+}
+
+// EpisodeCount returns the number of episodes seen
+func (p *Projection) EpisodeCount() int {
+	return len(p.episodes)
+}
+
+// EpisodesByState returns the number of episodes in each state
+func (p *Projection) EpisodesByState() map[episode.State]int {
+	counts := make(map[episode.State]int)
+	for _, ep := range p.episodes {
+		counts[ep.state]++
+	}
+	return counts
+}
+
+// TVLByState returns the value held by episodes in each state in wei:
+// premiums in minus payouts and surplus paid out
+func (p *Projection) TVLByState() map[episode.State]*big.Int {
+	tvl := make(map[episode.State]*big.Int)
+	for _, ep := range p.episodes {
+		if _, ok := tvl[ep.state]; !ok {
+			tvl[ep.state] = new(big.Int)
+		}
+		tvl[ep.state].Add(tvl[ep.state], ep.premiums)
+		tvl[ep.state].Sub(tvl[ep.state], ep.payouts)
+		tvl[ep.state].Sub(tvl[ep.state], ep.surplusReturned)
+	}
+	return tvl
+}
+
+// TotalPremiums returns all premiums collected in wei
+func (p *Projection) TotalPremiums() *big.Int {
+	return p.sum(func(ep *episodeStats) *big.Int { return ep.premiums })
+}
+
+// TotalPayouts returns all payouts claimed in wei
+func (p *Projection) TotalPayouts() *big.Int {
+	return p.sum(func(ep *episodeStats) *big.Int { return ep.payouts })
+}
+
+// SurplusReturned returns all surplus withdrawn by members in wei
+func (p *Projection) SurplusReturned() *big.Int {
+	return p.sum(func(ep *episodeStats) *big.Int { return ep.surplusReturned })
+}
+
+// LossRatio returns the total payout fixed at settlement divided by the premiums
+// of settled episodes, or 0 when no episode has been settled
+func (p *Projection) LossRatio() float64 {
+	premiums := new(big.Int)
+	payouts := new(big.Int)
+	for _, ep := range p.episodes {
+		if ep.settled {
+			premiums.Add(premiums, ep.premiums)
+			payouts.Add(payouts, ep.settledPayout)
+		}
+	}
+	if premiums.Sign() == 0 {
+		return 0
+	}
+	ratio, _ := new(big.Rat).SetFrac(payouts, premiums).Float64()
+	return ratio
+}
+
+// UniqueMembers returns the number of distinct member addresses across all episodes
+func (p *Projection) UniqueMembers() int {
+	return len(p.members)
+}
+
+// Series returns the buckets of interval in chronological order
+func (p *Projection) Series(interval Interval) []Bucket {
+	series := make([]Bucket, 0, len(p.buckets[interval]))
+	for _, b := range p.buckets[interval] {
+		series = append(series, *b)
+	}
+	sort.Slice(series, func(i, j int) bool {
+		return series[i].Start.Before(series[j].Start)
+	})
+	return series
+}
+
+// sum adds up a per-episode amount
+func (p *Projection) sum(amount func(ep *episodeStats) *big.Int) *big.Int {
+	total := new(big.Int)
+	for _, ep := range p.episodes {
+		total.Add(total, amount(ep))
+	}
+	return total
+}
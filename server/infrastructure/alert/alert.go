@@ -0,0 +1,111 @@
+package alert
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"eventsure-server/domain/audit"
+	"eventsure-server/infrastructure/config"
+)
+
+// LogAlerter writes invariant violations to the server log
+type LogAlerter struct{}
+
+// NewLogAlerter creates a new LogAlerter
+func NewLogAlerter() *LogAlerter {
+	return &LogAlerter{}
+}
+
+// Alert logs each finding
+func (a *LogAlerter) Alert(report *audit.Report, findings []audit.Finding) error {
+	for _, f := range findings {
+		slog.Warn("invariant violated",
+			"invariant", f.Rule.Invariant(),
+			"rule", string(f.Rule),
+			"episode", report.Episode,
+			"message", f.Message,
+		)
+	}
+	return nil
+}
+
+// WebhookAlerter posts invariant violations to a Slack-compatible incoming webhook
+type WebhookAlerter struct {
+	url        string
+	httpClient *http.Client
+}
+
+// NewWebhookAlerter creates a new WebhookAlerter posting to the configured webhook URL
+func NewWebhookAlerter(cfg config.Alert) (*WebhookAlerter, error) {
+	if cfg.WebhookURL == "" {
+		return nil, fmt.Errorf("ALERT_WEBHOOK_URL is not set")
+	}
+
+	return &WebhookAlerter{
+		url: cfg.WebhookURL,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}, nil
+}
+
+// webhookMessage is the JSON payload of an incoming webhook
+type webhookMessage struct {
+	Text string `json:"text"`
+}
+
+// Alert posts all findings of the report as a single message
+func (a *WebhookAlerter) Alert(report *audit.Report, findings []audit.Finding) error {
+	var text strings.Builder
+	fmt.Fprintf(&text, "EventSure invariant violations on episode %s:", report.Episode)
+	for _, f := range findings {
+		fmt.Fprintf(&text, "\n• Invariant %d [%s] %s", f.Rule.Invariant(), f.Rule, f.Message)
+		if f.TransactionHash != "" {
+			fmt.Fprintf(&text, " (tx %s)", f.TransactionHash)
+		}
+	}
+
+	body, err := json.Marshal(webhookMessage{Text: text.String()})
+	if err != nil {
+		return fmt.Errorf("failed to marshal alert: %w", err)
+	}
+
+	resp, err := a.httpClient.Post(a.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to post alert: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("alert webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// MultiAlerter delivers alerts to several alerters
+type MultiAlerter struct {
+	alerters []audit.Alerter
+}
+
+// NewMultiAlerter creates a new MultiAlerter
+func NewMultiAlerter(alerters ...audit.Alerter) *MultiAlerter {
+	return &MultiAlerter{
+		alerters: alerters,
+	}
+}
+
+// Alert delivers the alert to every alerter and returns the first error
+func (a *MultiAlerter) Alert(report *audit.Report, findings []audit.Finding) error {
+	var firstErr error
+	for _, alerter := range a.alerters {
+		if err := alerter.Alert(report, findings); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
@@ -0,0 +1,357 @@
+package config
+
+import (
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"eventsure-server/domain/chain"
+	"eventsure-server/infrastructure/logging"
+)
+
+// Config is the configuration of the server and the command line tools.
+// Load reads it from defaults, an optional YAML file, a .env file and the environment.
+type Config struct {
+	Server    Server
+	CORS      CORS
+	Security  Security
+	Auth      Auth
+	RateLimit RateLimit
+	Log       Log
+	Storage   Storage
+	Supabase  Supabase
+	Etherscan Etherscan
+	Contracts Contracts
+	Indexer   Indexer
+	Summaries Summaries
+	Audit     Audit
+	Alert     Alert
+	Pricing   Pricing
+	Health    Health
+	Tracing   Tracing
+
+	// file and dotEnvFile are the YAML and .env files read, empty when none was
+	file       string
+	dotEnvFile string
+	// sources maps each setting key to where its value came from
+	sources map[string]string
+}
+
+// Server configures the HTTP server
+type Server struct {
+	Port int
+	// DegradedMode starts the server even when critical dependencies are down
+	DegradedMode bool
+	// IdempotencyWindow is how long a response is replayed for a retried Idempotency-Key
+	IdempotencyWindow time.Duration
+	// IdempotencyMaxEntries is how many Idempotency-Key responses are kept; the least recently used are evicted
+	IdempotencyMaxEntries int
+	// APIV1Sunset is when the v1 API is removed; zero for 180 days after its deprecation
+	APIV1Sunset time.Time
+	// ReadTimeout, WriteTimeout and IdleTimeout bound reading a request, writing its response
+	// and keeping an idle keep-alive connection open
+	ReadTimeout  time.Duration
+	WriteTimeout time.Duration
+	IdleTimeout  time.Duration
+	// ShutdownTimeout is how long in-flight requests and background workers get to finish on SIGTERM
+	ShutdownTimeout time.Duration
+	// MaxBodyBytes is the largest request body accepted; larger bodies get 413 Payload Too Large
+	MaxBodyBytes int64
+}
+
+// CORS configures which browser origins may call the API
+type CORS struct {
+	// AllowedOrigins are origins such as https://app.example.com, https://*.example.com or *
+	AllowedOrigins []string
+	AllowedMethods []string
+	AllowedHeaders []string
+	// AllowCredentials lets browsers send cookies and authorization headers; not allowed with origin *
+	AllowCredentials bool
+	// MaxAge is how long browsers cache a preflight response
+	MaxAge time.Duration
+}
+
+// Security configures the security headers
+type Security struct {
+	// HSTSMaxAge is the max-age of Strict-Transport-Security on HTTPS requests; zero disables it
+	HSTSMaxAge time.Duration
+}
+
+// Auth configures API key authentication
+type Auth struct {
+	// AdminAPIKey is a bootstrap key with every scope, used to issue the first partner keys
+	AdminAPIKey string
+}
+
+// RateLimit configures the token bucket limits of the API, in requests per minute.
+// Each bucket holds a minute of requests, so a client may burst up to its limit.
+type RateLimit struct {
+	// KeyPerMinute is the limit of each API key without a limit of its own
+	KeyPerMinute int
+	// AnonymousPerMinute is the limit of each client IP without an API key
+	AnonymousPerMinute int
+	// BrowserPerMinute is the lower limit of each client IP sending browser requests without an API key
+	BrowserPerMinute int
+	// AuthFailuresPerMinute is the number of requests with an unknown or revoked API key allowed
+	// to each client IP, after which requests with an API key are rejected before authentication
+	AuthFailuresPerMinute int
+	// TrustProxy takes the client IP from the last X-Forwarded-For entry, for servers behind a proxy
+	TrustProxy bool
+}
+
+// Log configures the logger
+type Log struct {
+	Level string
+}
+
+// Storage selects the storage backend: postgres://..., sqlite://path, supabase, memory,
+// or empty for Supabase when configured and memory otherwise
+type Storage struct {
+	URL string
+}
+
+// Supabase configures the Supabase REST client
+type Supabase struct {
+	ProjectURL string
+	APIKey     string
+}
+
+// Configured reports whether the Supabase project URL and API key are set
+func (s Supabase) Configured() bool {
+	return s.ProjectURL != "" && s.APIKey != ""
+}
+
+// Etherscan configures the Etherscan client
+type Etherscan struct {
+	APIKey1 string
+	APIKey2 string
+	ChainID string
+}
+
+// APIKeys returns the API keys that are set
+func (e Etherscan) APIKeys() []string {
+	var keys []string
+	for _, key := range []string{e.APIKey1, e.APIKey2} {
+		if key != "" {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// Contracts holds the addresses of the deployed contracts
+type Contracts struct {
+	EpisodeFactory string
+}
+
+// Indexer configures the event indexer and the progress sync
+type Indexer struct {
+	Interval time.Duration
+}
+
+// Summaries configures the background refresh of the episode summaries served by the listing
+type Summaries struct {
+	Interval time.Duration
+}
+
+// Audit configures the invariant monitor
+type Audit struct {
+	Interval time.Duration
+}
+
+// Alert configures where invariant violations are sent besides the log
+type Alert struct {
+	WebhookURL string
+}
+
+// Pricing configures the pricing model
+type Pricing struct {
+	Dataset string
+}
+
+// Health configures the dependency checks
+type Health struct {
+	MaxIndexerLag int64
+}
+
+// Tracing configures the trace exporter. The other OTEL_* variables are read by the OpenTelemetry SDK.
+type Tracing struct {
+	// Exporter is otlp, stdout or none; empty selects otlp when Endpoint is set and none otherwise
+	Exporter string
+	// Endpoint is the base URL of the OTLP/HTTP collector, e.g. http://localhost:4318
+	Endpoint string
+}
+
+// Default returns the configuration used for settings that are not set
+func Default() *Config {
+	return &Config{
+		Server: Server{
+			Port:                  3000,
+			IdempotencyWindow:     24 * time.Hour,
+			IdempotencyMaxEntries: 10000,
+			ReadTimeout:           15 * time.Second,
+			WriteTimeout:          60 * time.Second,
+			IdleTimeout:           120 * time.Second,
+			ShutdownTimeout:       30 * time.Second,
+			MaxBodyBytes:          64 << 10,
+		},
+		CORS: CORS{
+			AllowedOrigins: []string{"*"},
+			AllowedMethods: []string{"GET", "POST", "PATCH", "DELETE", "OPTIONS"},
+			AllowedHeaders: []string{"Content-Type", "Authorization", "X-API-Key", "Idempotency-Key", "X-Request-ID", "traceparent", "tracestate"},
+			MaxAge:         10 * time.Minute,
+		},
+		Security: Security{HSTSMaxAge: 180 * 24 * time.Hour},
+		RateLimit: RateLimit{
+			KeyPerMinute:          600,
+			AnonymousPerMinute:    120,
+			BrowserPerMinute:      60,
+			AuthFailuresPerMinute: 10,
+		},
+		Log: Log{Level: "info"},
+		Etherscan: Etherscan{
+			ChainID: "1",
+		},
+		Indexer:   Indexer{Interval: time.Minute},
+		Summaries: Summaries{Interval: 30 * time.Second},
+		Audit:     Audit{Interval: 5 * time.Minute},
+		Health:    Health{MaxIndexerLag: 1000},
+	}
+}
+
+// Validate checks the values that are set. Settings the server can start without,
+// such as the Etherscan keys, are reported by Missing instead.
+func (c *Config) Validate() error {
+	var errs []error
+	add := func(env, message string) {
+		errs = append(errs, errors.New(env+": "+message))
+	}
+
+	if c.Server.Port < 1 || c.Server.Port > 65535 {
+		add("PORT", "must be between 1 and 65535")
+	}
+	if c.Server.MaxBodyBytes < 1 {
+		add("HTTP_MAX_BODY_BYTES", "must be positive")
+	}
+	if c.Server.IdempotencyMaxEntries < 1 {
+		add("IDEMPOTENCY_MAX_ENTRIES", "must be positive")
+	}
+	for _, origin := range c.CORS.AllowedOrigins {
+		switch {
+		case origin == "*":
+			if c.CORS.AllowCredentials {
+				add("CORS_ALLOW_CREDENTIALS", "cannot be true when CORS_ALLOWED_ORIGINS contains *, list the origins instead")
+			}
+		case !isOrigin(origin):
+			add("CORS_ALLOWED_ORIGINS", "invalid origin "+origin+", expected scheme://host[:port] without a path, or *")
+		}
+	}
+	for _, method := range c.CORS.AllowedMethods {
+		if method != strings.ToUpper(method) || strings.ContainsAny(method, " ,") {
+			add("CORS_ALLOWED_METHODS", "invalid method "+method+", expected upper case names such as GET")
+		}
+	}
+	if c.Auth.AdminAPIKey != "" && len(c.Auth.AdminAPIKey) < 32 {
+		add("ADMIN_API_KEY", "must be at least 32 characters")
+	}
+	for _, limit := range []struct {
+		env   string
+		value int
+	}{
+		{"RATE_LIMIT_KEY_PER_MINUTE", c.RateLimit.KeyPerMinute},
+		{"RATE_LIMIT_ANONYMOUS_PER_MINUTE", c.RateLimit.AnonymousPerMinute},
+		{"RATE_LIMIT_BROWSER_PER_MINUTE", c.RateLimit.BrowserPerMinute},
+		{"RATE_LIMIT_AUTH_FAILURES_PER_MINUTE", c.RateLimit.AuthFailuresPerMinute},
+	} {
+		if limit.value < 1 {
+			add(limit.env, "must be positive")
+		}
+	}
+	if _, err := logging.ParseLevel(c.Log.Level); err != nil {
+		add("LOG_LEVEL", "expected debug, info, warn or error")
+	}
+
+	switch u := c.Storage.URL; {
+	case u == "", u == "memory":
+	case u == "supabase":
+		if !c.Supabase.Configured() {
+			add("STORAGE", "supabase requires SUPABASE_PROJECT_URL and SUPABASE_API_KEY")
+		}
+	case strings.HasPrefix(u, "postgres://"), strings.HasPrefix(u, "postgresql://"):
+	case strings.HasPrefix(u, "sqlite://"):
+		if strings.TrimPrefix(u, "sqlite://") == "" {
+			add("STORAGE", "sqlite:// is missing the database file path")
+		}
+	default:
+		add("STORAGE", "expected postgres://..., sqlite://..., supabase or memory")
+	}
+
+	if c.Supabase.ProjectURL != "" && !isHTTPURL(c.Supabase.ProjectURL) {
+		add("SUPABASE_PROJECT_URL", "must be an http(s) URL")
+	}
+	if _, err := strconv.ParseUint(c.Etherscan.ChainID, 10, 64); err != nil {
+		add("ETHERSCAN_CHAIN_ID", "must be a chain ID number")
+	}
+	if c.Contracts.EpisodeFactory != "" {
+		if _, err := chain.ParseAddress(c.Contracts.EpisodeFactory); err != nil {
+			add("EPISODE_CONTRACT_FACTORY", err.Error())
+		}
+	}
+	if c.Alert.WebhookURL != "" && !isHTTPURL(c.Alert.WebhookURL) {
+		add("ALERT_WEBHOOK_URL", "must be an http(s) URL")
+	}
+	if c.Health.MaxIndexerLag < 0 {
+		add("HEALTH_MAX_INDEXER_LAG", "must not be negative")
+	}
+
+	switch c.Tracing.Exporter {
+	case "", "otlp", "stdout", "none":
+	default:
+		add("OTEL_TRACES_EXPORTER", "expected otlp, stdout or none")
+	}
+	if c.Tracing.Endpoint != "" && !isHTTPURL(c.Tracing.Endpoint) {
+		add("OTEL_EXPORTER_OTLP_ENDPOINT", "must be an http(s) URL")
+	}
+
+	return errors.Join(errs...)
+}
+
+// Missing returns the environment variable names of the settings the server needs
+// to read episodes from the chain that are not set
+func (c *Config) Missing() []string {
+	var missing []string
+	if len(c.Etherscan.APIKeys()) == 0 {
+		missing = append(missing, "ETHERSCAN_API_KEY_1")
+	}
+	if c.Contracts.EpisodeFactory == "" {
+		missing = append(missing, "EPISODE_CONTRACT_FACTORY")
+	}
+	return missing
+}
+
+// File returns the YAML file the configuration was read from, or an empty string
+func (c *Config) File() string {
+	return c.file
+}
+
+// DotEnvFile returns the .env file the configuration was read from, or an empty string
+func (c *Config) DotEnvFile() string {
+	return c.dotEnvFile
+}
+
+func isHTTPURL(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// isOrigin reports whether s is an http(s) origin without a path, whose host may start with one *. wildcard
+func isOrigin(s string) bool {
+	if strings.Count(s, "*") > 1 {
+		return false
+	}
+	u, err := url.Parse(strings.Replace(s, "://*.", "://", 1))
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "" &&
+		u.Path == "" && u.RawQuery == "" && u.Fragment == "" && u.User == nil
+}
@@ -0,0 +1,283 @@
+package config
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"eventsure-server/infrastructure/logging"
+)
+
+// Sources of a setting, from lowest to highest precedence
+const (
+	SourceDefault = "default"
+	SourceFile    = "file"
+	SourceDotEnv  = ".env"
+	SourceEnv     = "env"
+)
+
+// field is one setting: its key in the YAML file (section.name), its environment variable,
+// how its value is parsed, formatted and masked, and whether the whole value is a secret
+type field struct {
+	key    string
+	env    string
+	set    func(c *Config, s string) error
+	get    func(c *Config) string
+	mask   func(s string) string
+	secret bool
+	// list is set for comma-separated settings, which the YAML file may also give as a sequence
+	list bool
+}
+
+// fields are all settings, in the order they are listed and dumped
+var fields = []field{
+	intField("server.port", "PORT", func(c *Config) *int { return &c.Server.Port }),
+	boolField("server.degradedMode", "DEGRADED_MODE", func(c *Config) *bool { return &c.Server.DegradedMode }),
+	durationField("server.idempotencyWindow", "IDEMPOTENCY_WINDOW", func(c *Config) *time.Duration { return &c.Server.IdempotencyWindow }),
+	intField("server.idempotencyMaxEntries", "IDEMPOTENCY_MAX_ENTRIES", func(c *Config) *int { return &c.Server.IdempotencyMaxEntries }),
+	dateField("server.apiV1Sunset", "API_V1_SUNSET", func(c *Config) *time.Time { return &c.Server.APIV1Sunset }),
+	durationField("server.readTimeout", "HTTP_READ_TIMEOUT", func(c *Config) *time.Duration { return &c.Server.ReadTimeout }),
+	durationField("server.writeTimeout", "HTTP_WRITE_TIMEOUT", func(c *Config) *time.Duration { return &c.Server.WriteTimeout }),
+	durationField("server.idleTimeout", "HTTP_IDLE_TIMEOUT", func(c *Config) *time.Duration { return &c.Server.IdleTimeout }),
+	durationField("server.shutdownTimeout", "SHUTDOWN_TIMEOUT", func(c *Config) *time.Duration { return &c.Server.ShutdownTimeout }),
+	int64Field("server.maxBodyBytes", "HTTP_MAX_BODY_BYTES", func(c *Config) *int64 { return &c.Server.MaxBodyBytes }),
+	listField("cors.allowedOrigins", "CORS_ALLOWED_ORIGINS", func(c *Config) *[]string { return &c.CORS.AllowedOrigins }),
+	listField("cors.allowedMethods", "CORS_ALLOWED_METHODS", func(c *Config) *[]string { return &c.CORS.AllowedMethods }),
+	listField("cors.allowedHeaders", "CORS_ALLOWED_HEADERS", func(c *Config) *[]string { return &c.CORS.AllowedHeaders }),
+	boolField("cors.allowCredentials", "CORS_ALLOW_CREDENTIALS", func(c *Config) *bool { return &c.CORS.AllowCredentials }),
+	durationField("cors.maxAge", "CORS_MAX_AGE", func(c *Config) *time.Duration { return &c.CORS.MaxAge }),
+	optionalDurationField("security.hstsMaxAge", "HSTS_MAX_AGE", func(c *Config) *time.Duration { return &c.Security.HSTSMaxAge }),
+	secretField("auth.adminApiKey", "ADMIN_API_KEY", func(c *Config) *string { return &c.Auth.AdminAPIKey }),
+	intField("rateLimit.keyPerMinute", "RATE_LIMIT_KEY_PER_MINUTE", func(c *Config) *int { return &c.RateLimit.KeyPerMinute }),
+	intField("rateLimit.anonymousPerMinute", "RATE_LIMIT_ANONYMOUS_PER_MINUTE", func(c *Config) *int { return &c.RateLimit.AnonymousPerMinute }),
+	intField("rateLimit.browserPerMinute", "RATE_LIMIT_BROWSER_PER_MINUTE", func(c *Config) *int { return &c.RateLimit.BrowserPerMinute }),
+	intField("rateLimit.authFailuresPerMinute", "RATE_LIMIT_AUTH_FAILURES_PER_MINUTE", func(c *Config) *int { return &c.RateLimit.AuthFailuresPerMinute }),
+	boolField("rateLimit.trustProxy", "RATE_LIMIT_TRUST_PROXY", func(c *Config) *bool { return &c.RateLimit.TrustProxy }),
+	stringField("log.level", "LOG_LEVEL", func(c *Config) *string { return &c.Log.Level }),
+	urlField("storage.url", "STORAGE", func(c *Config) *string { return &c.Storage.URL }),
+	stringField("supabase.projectUrl", "SUPABASE_PROJECT_URL", func(c *Config) *string { return &c.Supabase.ProjectURL }),
+	secretField("supabase.apiKey", "SUPABASE_API_KEY", func(c *Config) *string { return &c.Supabase.APIKey }),
+	secretField("etherscan.apiKey1", "ETHERSCAN_API_KEY_1", func(c *Config) *string { return &c.Etherscan.APIKey1 }),
+	secretField("etherscan.apiKey2", "ETHERSCAN_API_KEY_2", func(c *Config) *string { return &c.Etherscan.APIKey2 }),
+	stringField("etherscan.chainId", "ETHERSCAN_CHAIN_ID", func(c *Config) *string { return &c.Etherscan.ChainID }),
+	stringField("contracts.episodeFactory", "EPISODE_CONTRACT_FACTORY", func(c *Config) *string { return &c.Contracts.EpisodeFactory }),
+	durationField("indexer.interval", "INDEXER_INTERVAL", func(c *Config) *time.Duration { return &c.Indexer.Interval }),
+	durationField("summaries.interval", "SUMMARY_SYNC_INTERVAL", func(c *Config) *time.Duration { return &c.Summaries.Interval }),
+	durationField("audit.interval", "AUDIT_INTERVAL", func(c *Config) *time.Duration { return &c.Audit.Interval }),
+	secretField("alert.webhookUrl", "ALERT_WEBHOOK_URL", func(c *Config) *string { return &c.Alert.WebhookURL }),
+	stringField("pricing.dataset", "PRICING_DATASET", func(c *Config) *string { return &c.Pricing.Dataset }),
+	int64Field("health.maxIndexerLag", "HEALTH_MAX_INDEXER_LAG", func(c *Config) *int64 { return &c.Health.MaxIndexerLag }),
+	stringField("tracing.exporter", "OTEL_TRACES_EXPORTER", func(c *Config) *string { return &c.Tracing.Exporter }),
+	stringField("tracing.endpoint", "OTEL_EXPORTER_OTLP_ENDPOINT", func(c *Config) *string { return &c.Tracing.Endpoint }),
+}
+
+func stringField(key, env string, p func(*Config) *string) field {
+	return field{
+		key:  key,
+		env:  env,
+		set:  func(c *Config, s string) error { *p(c) = s; return nil },
+		get:  func(c *Config) string { return *p(c) },
+		mask: func(s string) string { return s },
+	}
+}
+
+// secretField is a string setting that is masked in dumps and redacted from logs
+func secretField(key, env string, p func(*Config) *string) field {
+	f := stringField(key, env, p)
+	f.mask = maskSecret
+	f.secret = true
+	return f
+}
+
+// urlField is a string setting whose URL password is masked in dumps and redacted from logs
+func urlField(key, env string, p func(*Config) *string) field {
+	f := stringField(key, env, p)
+	f.mask = logging.Redact
+	return f
+}
+
+func intField(key, env string, p func(*Config) *int) field {
+	return field{
+		key: key,
+		env: env,
+		set: func(c *Config, s string) error {
+			n, err := strconv.Atoi(s)
+			if err != nil {
+				return errors.New("expected an integer")
+			}
+			*p(c) = n
+			return nil
+		},
+		get:  func(c *Config) string { return strconv.Itoa(*p(c)) },
+		mask: func(s string) string { return s },
+	}
+}
+
+func int64Field(key, env string, p func(*Config) *int64) field {
+	return field{
+		key: key,
+		env: env,
+		set: func(c *Config, s string) error {
+			n, err := strconv.ParseInt(s, 10, 64)
+			if err != nil {
+				return errors.New("expected an integer")
+			}
+			*p(c) = n
+			return nil
+		},
+		get:  func(c *Config) string { return strconv.FormatInt(*p(c), 10) },
+		mask: func(s string) string { return s },
+	}
+}
+
+func boolField(key, env string, p func(*Config) *bool) field {
+	return field{
+		key: key,
+		env: env,
+		set: func(c *Config, s string) error {
+			b, err := strconv.ParseBool(s)
+			if err != nil {
+				return errors.New("expected true or false")
+			}
+			*p(c) = b
+			return nil
+		},
+		get:  func(c *Config) string { return strconv.FormatBool(*p(c)) },
+		mask: func(s string) string { return s },
+	}
+}
+
+func durationField(key, env string, p func(*Config) *time.Duration) field {
+	return field{
+		key: key,
+		env: env,
+		set: func(c *Config, s string) error {
+			d, err := time.ParseDuration(s)
+			if err != nil || d <= 0 {
+				return errors.New("expected a positive duration such as 30s or 5m")
+			}
+			*p(c) = d
+			return nil
+		},
+		get:  func(c *Config) string { return p(c).String() },
+		mask: func(s string) string { return s },
+	}
+}
+
+// optionalDurationField is a duration setting where 0 disables the feature it configures
+func optionalDurationField(key, env string, p func(*Config) *time.Duration) field {
+	f := durationField(key, env, p)
+	f.set = func(c *Config, s string) error {
+		d, err := time.ParseDuration(s)
+		if err != nil || d < 0 {
+			return errors.New("expected a duration such as 4320h, or 0 to disable")
+		}
+		*p(c) = d
+		return nil
+	}
+	return f
+}
+
+// listField is a comma-separated list; surrounding spaces and empty items are dropped
+func listField(key, env string, p func(*Config) *[]string) field {
+	return field{
+		key: key,
+		env: env,
+		set: func(c *Config, s string) error {
+			var items []string
+			for _, item := range strings.Split(s, ",") {
+				if item = strings.TrimSpace(item); item != "" {
+					items = append(items, item)
+				}
+			}
+			*p(c) = items
+			return nil
+		},
+		get:  func(c *Config) string { return strings.Join(*p(c), ",") },
+		mask: func(s string) string { return s },
+		list: true,
+	}
+}
+
+// dateField is a YYYY-MM-DD date (UTC midnight) or RFC 3339 timestamp; zero when not set
+func dateField(key, env string, p func(*Config) *time.Time) field {
+	return field{
+		key: key,
+		env: env,
+		set: func(c *Config, s string) error {
+			if t, err := time.Parse("2006-01-02", s); err == nil {
+				*p(c) = t
+				return nil
+			}
+			t, err := time.Parse(time.RFC3339, s)
+			if err != nil {
+				return errors.New("expected YYYY-MM-DD or an RFC 3339 timestamp")
+			}
+			*p(c) = t
+			return nil
+		},
+		get: func(c *Config) string {
+			if p(c).IsZero() {
+				return ""
+			}
+			return p(c).Format(time.RFC3339)
+		},
+		mask: func(s string) string { return s },
+	}
+}
+
+// maskSecret keeps the last four characters of long secrets so the key in use can be recognized
+func maskSecret(s string) string {
+	switch {
+	case s == "":
+		return ""
+	case len(s) < 12:
+		return "****"
+	}
+	return "****" + s[len(s)-4:]
+}
+
+// Entry is one setting with its masked value and the source of the value
+type Entry struct {
+	Key    string `json:"key"`
+	Env    string `json:"env"`
+	Value  string `json:"value"`
+	Source string `json:"source"`
+}
+
+// Entries returns every setting with secrets masked
+func (c *Config) Entries() []Entry {
+	entries := make([]Entry, 0, len(fields))
+	for _, f := range fields {
+		source := c.sources[f.key]
+		if source == "" {
+			source = SourceDefault
+		}
+		entries = append(entries, Entry{
+			Key:    f.key,
+			Env:    f.env,
+			Value:  f.mask(f.get(c)),
+			Source: source,
+		})
+	}
+	return entries
+}
+
+// secrets returns the values of the secret settings that are set
+func (c *Config) secrets() []string {
+	var values []string
+	for _, f := range fields {
+		value := f.get(c)
+		if f.secret && value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// section returns the section and name of a key
+func section(key string) (string, string) {
+	i := strings.IndexByte(key, '.')
+	return key[:i], key[i+1:]
+}
@@ -0,0 +1,215 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"eventsure-server/infrastructure/logging"
+
+	"github.com/joho/godotenv"
+	"go.yaml.in/yaml/v2"
+)
+
+// Options selects the files Load reads
+type Options struct {
+	// File is the YAML file to read. When empty, CONFIG_FILE is used; no file is read when both are empty.
+	File string
+	// SkipDotEnv disables reading .env
+	SkipDotEnv bool
+}
+
+// Load reads the configuration. Later sources override earlier ones:
+// defaults, the YAML file, the first .env found in the working directory or its two parents,
+// and the environment. The .env variables are also exported to the environment, where the
+// OpenTelemetry SDK reads its own OTEL_* settings. The values of secret settings are
+// registered with logging.Redact. Invalid values and failed validation return an error
+// together with the configuration read so far.
+func Load(opts Options) (*Config, error) {
+	cfg := Default()
+	cfg.sources = make(map[string]string)
+
+	// Read before .env is exported, so variables that were already set keep precedence and source
+	environment := make(map[string]string)
+	for _, f := range fields {
+		if value, ok := os.LookupEnv(f.env); ok {
+			environment[f.env] = value
+		}
+	}
+
+	var dotEnv map[string]string
+	if !opts.SkipDotEnv {
+		if path := findDotEnv(); path != "" {
+			values, err := godotenv.Read(path)
+			if err != nil {
+				return cfg, errors.New("failed to read " + path + ": " + err.Error())
+			}
+			if err := godotenv.Load(path); err != nil {
+				return cfg, errors.New("failed to load " + path + ": " + err.Error())
+			}
+			dotEnv = values
+			cfg.dotEnvFile = path
+		}
+	}
+
+	var errs []error
+
+	file := opts.File
+	if file == "" {
+		file = os.Getenv("CONFIG_FILE")
+	}
+	if file != "" {
+		values, err := readFile(file)
+		if err != nil {
+			return cfg, err
+		}
+		cfg.file = file
+		for _, f := range fields {
+			if value, ok := values[f.key]; ok {
+				errs = append(errs, cfg.set(f, value, SourceFile))
+			}
+		}
+	}
+
+	for _, f := range fields {
+		if value, ok := dotEnv[f.env]; ok && value != "" {
+			if _, overridden := environment[f.env]; !overridden {
+				errs = append(errs, cfg.set(f, value, SourceDotEnv))
+			}
+		}
+		if value, ok := environment[f.env]; ok && value != "" {
+			errs = append(errs, cfg.set(f, value, SourceEnv))
+		}
+	}
+
+	logging.AddSecrets(cfg.secrets()...)
+
+	if err := errors.Join(errs...); err != nil {
+		return cfg, err
+	}
+	return cfg, cfg.Validate()
+}
+
+// set sets field f from a value of source
+func (c *Config) set(f field, value, source string) error {
+	if err := f.set(c, value); err != nil {
+		return fmt.Errorf("%s: invalid value %q from %s: %v", f.env, f.mask(value), source, err)
+	}
+	c.sources[f.key] = source
+	return nil
+}
+
+// findDotEnv returns the first .env file in the working directory or its two parents
+func findDotEnv() string {
+	workDir, err := os.Getwd()
+	if err != nil {
+		return ""
+	}
+
+	for _, dir := range []string{workDir, filepath.Join(workDir, ".."), filepath.Join(workDir, "..", "..")} {
+		path := filepath.Join(dir, ".env")
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return filepath.Clean(path)
+		}
+	}
+	return ""
+}
+
+// readFile reads a YAML file of sections of settings into values keyed by section.name.
+// Unknown sections and settings are rejected, so typos do not go unnoticed.
+func readFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.New("failed to read config file: " + err.Error())
+	}
+
+	var sections map[string]map[string]interface{}
+	if err := yaml.Unmarshal(data, &sections); err != nil {
+		return nil, errors.New("failed to parse config file " + path + ": " + err.Error())
+	}
+
+	known := make(map[string]field, len(fields))
+	for _, f := range fields {
+		known[f.key] = f
+	}
+
+	values := make(map[string]string)
+	var errs []error
+	for name, settings := range sections {
+		for setting, value := range settings {
+			key := name + "." + setting
+			f, ok := known[key]
+			if !ok {
+				errs = append(errs, fmt.Errorf("%s: unknown setting %s", path, key))
+				continue
+			}
+			switch v := value.(type) {
+			case nil:
+				continue
+			case []interface{}:
+				if !f.list {
+					errs = append(errs, fmt.Errorf("%s: %s must be a single value", path, key))
+					continue
+				}
+				items := make([]string, len(v))
+				for i, item := range v {
+					items[i] = fmt.Sprint(item)
+				}
+				values[key] = strings.Join(items, ",")
+				continue
+			case map[interface{}]interface{}:
+				errs = append(errs, fmt.Errorf("%s: %s must be a single value", path, key))
+				continue
+			}
+			values[key] = fmt.Sprint(value)
+		}
+	}
+	return values, errors.Join(errs...)
+}
+
+// WriteYAML writes every setting that is set as a YAML config file, with secrets masked
+func (c *Config) WriteYAML(w io.Writer) error {
+	var doc yaml.MapSlice
+	index := make(map[string]int)
+	for _, f := range fields {
+		value := f.get(c)
+		if value == "" {
+			continue
+		}
+
+		name, setting := section(f.key)
+		i, ok := index[name]
+		if !ok {
+			i = len(doc)
+			index[name] = i
+			doc = append(doc, yaml.MapItem{Key: name, Value: yaml.MapSlice{}})
+		}
+		var item interface{} = scalar(f.mask(value))
+		if f.list {
+			item = strings.Split(f.mask(value), ",")
+		}
+		doc[i].Value = append(doc[i].Value.(yaml.MapSlice), yaml.MapItem{Key: setting, Value: item})
+	}
+
+	data, err := yaml.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// scalar returns s as an integer or boolean when it is one, so the YAML file is not quoted
+func scalar(s string) interface{} {
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return n
+	}
+	if b, err := strconv.ParseBool(s); err == nil && (s == "true" || s == "false") {
+		return b
+	}
+	return s
+}
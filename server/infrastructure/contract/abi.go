@@ -0,0 +1,141 @@
+package contract
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"golang.org/x/crypto/sha3"
+)
+
+// wordSize is the size in bytes of a single ABI encoded word
+const wordSize = 32
+
+// Caller executes read-only contract calls (eth_call)
+type Caller interface {
+	EthCall(to, data string) (string, error)
+}
+
+// Keccak256 returns the Keccak-256 hash of data
+func Keccak256(data []byte) []byte {
+	h := sha3.NewLegacyKeccak256()
+	h.Write(data)
+	return h.Sum(nil)
+}
+
+// selector returns the hex encoded 4-byte function selector for a function signature
+// such as "premiumOf(address)"
+func selector(signature string) string {
+	return "0x" + hex.EncodeToString(Keccak256([]byte(signature))[:4])
+}
+
+// addressHexLength is the number of hex digits of an address
+const addressHexLength = 40
+
+// encodeAddress ABI encodes an address argument
+func encodeAddress(address string) (string, error) {
+	addr := strings.ToLower(strings.TrimPrefix(address, "0x"))
+	if len(addr) != addressHexLength {
+		return "", fmt.Errorf("invalid address %q: want %d hex digits", address, addressHexLength)
+	}
+	if _, err := hex.DecodeString(addr); err != nil {
+		return "", fmt.Errorf("invalid address %q: %w", address, err)
+	}
+	return strings.Repeat("0", 2*wordSize-len(addr)) + addr, nil
+}
+
+// encodeUint ABI encodes an unsigned integer argument
+func encodeUint(value uint64) string {
+	return fmt.Sprintf("%064x", value)
+}
+
+// returnData decodes the hex encoded return data of a call
+// and checks that it holds at least minWords words
+func returnData(hexData string, minWords int) ([]byte, error) {
+	data, err := hex.DecodeString(strings.TrimPrefix(hexData, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid return data: %w", err)
+	}
+	if len(data) < minWords*wordSize {
+		return nil, fmt.Errorf("return data too short: got %d bytes, want at least %d", len(data), minWords*wordSize)
+	}
+	return data, nil
+}
+
+// word returns the i-th 32-byte word of data
+func word(data []byte, i int) []byte {
+	return data[i*wordSize : (i+1)*wordSize]
+}
+
+// decodeUint decodes a uint256 word
+func decodeUint(w []byte) *big.Int {
+	return new(big.Int).SetBytes(w)
+}
+
+// decodeUint64 decodes a word holding a uint64 or smaller unsigned integer
+func decodeUint64(w []byte) uint64 {
+	return new(big.Int).SetBytes(w).Uint64()
+}
+
+// decodeBool decodes a bool word
+func decodeBool(w []byte) bool {
+	return w[wordSize-1] != 0
+}
+
+// decodeAddress decodes an address word into its lowercase hex form
+func decodeAddress(w []byte) string {
+	return "0x" + hex.EncodeToString(w[wordSize-20:])
+}
+
+// decodeSize decodes a word holding an offset or a length, reporting false when it does not fit in a uint64
+func decodeSize(w []byte) (uint64, bool) {
+	v := new(big.Int).SetBytes(w)
+	return v.Uint64(), v.IsUint64()
+}
+
+// dynamicData returns the start of the data of the dynamic value whose head word (the offset) is at
+// word index i, and its length word. The offset and the length come from untrusted return data, so
+// they are compared with the size of data before any arithmetic that could overflow.
+func dynamicData(data []byte, i int) (start, length uint64, err error) {
+	size := uint64(len(data))
+	offset, ok := decodeSize(word(data, i))
+	if !ok || size < wordSize || offset > size-wordSize {
+		return 0, 0, fmt.Errorf("offset %s out of range", decodeUint(word(data, i)))
+	}
+	start = offset + wordSize
+	length, ok = decodeSize(data[offset:start])
+	if !ok {
+		return 0, 0, fmt.Errorf("length %s out of range", decodeUint(data[offset:start]))
+	}
+	return start, length, nil
+}
+
+// decodeString decodes a dynamic string whose head word (the offset) is at word index i
+func decodeString(data []byte, i int) (string, error) {
+	start, length, err := dynamicData(data, i)
+	if err != nil {
+		return "", fmt.Errorf("string %w", err)
+	}
+	if length > uint64(len(data))-start {
+		return "", fmt.Errorf("string length %d out of range", length)
+	}
+	return string(data[start : start+length]), nil
+}
+
+// decodeAddressArray decodes a dynamic address[] whose head word (the offset) is at word index i
+func decodeAddressArray(data []byte, i int) ([]string, error) {
+	start, length, err := dynamicData(data, i)
+	if err != nil {
+		return nil, fmt.Errorf("array %w", err)
+	}
+	if length > (uint64(len(data))-start)/wordSize {
+		return nil, fmt.Errorf("array length %d out of range", length)
+	}
+
+	addresses := make([]string, length)
+	for j := range addresses {
+		addresses[j] = decodeAddress(data[start+uint64(j)*wordSize : start+uint64(j+1)*wordSize])
+	}
+	return addresses, nil
+}
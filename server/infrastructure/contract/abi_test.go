@@ -0,0 +1,156 @@
+package contract
+
+import (
+	"encoding/hex"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+// words concatenates ABI words given as big integers in decimal, or as raw 32-byte hex
+func words(t *testing.T, values ...string) []byte {
+	t.Helper()
+	var data []byte
+	for _, v := range values {
+		if strings.HasPrefix(v, "0x") {
+			w, err := hex.DecodeString(strings.TrimPrefix(v, "0x"))
+			if err != nil || len(w) != wordSize {
+				t.Fatalf("invalid word %q", v)
+			}
+			data = append(data, w...)
+			continue
+		}
+		n, ok := new(big.Int).SetString(v, 10)
+		if !ok {
+			t.Fatalf("invalid word %q", v)
+		}
+		data = append(data, n.FillBytes(make([]byte, wordSize))...)
+	}
+	return data
+}
+
+// maxUint64 and nearMaxUint64 are offsets and lengths that overflow when added to or multiplied
+const (
+	maxUint64     = "18446744073709551615"
+	nearMaxUint64 = "18446744073709551600"
+	twoPow64      = "18446744073709551648"
+	twoPow59      = "576460752303423488"
+)
+
+func TestDecodeString(t *testing.T) {
+	hello := "0x" + hex.EncodeToString([]byte("hello")) + strings.Repeat("00", wordSize-5)
+
+	tests := []struct {
+		name    string
+		data    []string
+		want    string
+		wantErr string
+	}{
+		{"valid", []string{"32", "5", hello}, "hello", ""},
+		{"empty", []string{"32", "0"}, "", ""},
+		{"offset past the data", []string{"64", "5"}, "", "string offset 64 out of range"},
+		{"offset wrapping around", []string{nearMaxUint64, "5"}, "", "string offset"},
+		{"offset beyond uint64", []string{twoPow64, "5"}, "", "string offset"},
+		{"length past the data", []string{"32", "33", hello}, "", "string length 33 out of range"},
+		{"length wrapping around", []string{"32", maxUint64, hello}, "", "string length"},
+		{"length beyond uint64", []string{"32", twoPow64, hello}, "", "string length"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodeString(words(t, tt.data...), 0)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Fatalf("decodeString = %q, %v; want %q", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeAddressArray(t *testing.T) {
+	first := "0x000000000000000000000000" + strings.Repeat("11", 20)
+	second := "0x000000000000000000000000" + strings.Repeat("ab", 20)
+
+	tests := []struct {
+		name    string
+		data    []string
+		want    []string
+		wantErr string
+	}{
+		{"valid", []string{"32", "2", first, second}, []string{"0x" + strings.Repeat("11", 20), "0x" + strings.Repeat("ab", 20)}, ""},
+		{"empty", []string{"32", "0"}, []string{}, ""},
+		{"offset past the data", []string{"96", "0"}, nil, "array offset 96 out of range"},
+		{"offset wrapping around", []string{nearMaxUint64, "1", first}, nil, "array offset"},
+		{"offset beyond uint64", []string{twoPow64, "1", first}, nil, "array offset"},
+		{"length past the data", []string{"32", "3", first, second}, nil, "array length 3 out of range"},
+		{"length times word size wrapping around", []string{"32", twoPow59, first}, nil, "array length"},
+		{"length wrapping around", []string{"32", maxUint64, first}, nil, "array length"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodeAddressArray(words(t, tt.data...), 0)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || strings.Join(got, ",") != strings.Join(tt.want, ",") || len(got) != len(tt.want) {
+				t.Fatalf("decodeAddressArray = %v, %v; want %v", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestFactoryRejectsMalformedReturnData(t *testing.T) {
+	tests := []struct {
+		name string
+		data []string
+	}{
+		{"array offset wrapping around", []string{nearMaxUint64, "1"}},
+		{"array length wrapping around", []string{"32", twoPow59}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			caller := fixedCaller("0x" + hex.EncodeToString(words(t, tt.data...)))
+			if addresses, err := NewFactory(caller, "0x"+strings.Repeat("22", 20)).AllEpisodes(); err == nil {
+				t.Fatalf("AllEpisodes = %v, want an error", addresses)
+			}
+		})
+	}
+}
+
+func TestEncodeAddress(t *testing.T) {
+	tests := []struct {
+		name    string
+		address string
+		want    string
+		wantErr bool
+	}{
+		{"lower case", "0x" + strings.Repeat("ab", 20), strings.Repeat("0", 24) + strings.Repeat("ab", 20), false},
+		{"upper case", "0x" + strings.Repeat("AB", 20), strings.Repeat("0", 24) + strings.Repeat("ab", 20), false},
+		{"without prefix", strings.Repeat("ab", 20), strings.Repeat("0", 24) + strings.Repeat("ab", 20), false},
+		{"too short", "0x1234", "", true},
+		{"longer than a word", "0x" + strings.Repeat("ab", 40), "", true},
+		{"not hex", "0x" + strings.Repeat("zz", 20), "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := encodeAddress(tt.address)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("encodeAddress(%q) = %q, %v; want %q, error %v", tt.address, got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+// fixedCaller answers every call with the same return data
+type fixedCaller string
+
+func (c fixedCaller) EthCall(to, data string) (string, error) {
+	return string(c), nil
+}
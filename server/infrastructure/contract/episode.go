@@ -0,0 +1,170 @@
+package contract
+
+import (
+	"fmt"
+	"math/big"
+)
+
+var (
+	selectorState            = selector("state()")
+	selectorPremiumAmount    = selector("premiumAmount()")
+	selectorPayoutAmount     = selector("payoutAmount()")
+	selectorTotalPremium     = selector("totalPremium()")
+	selectorTotalPayout      = selector("totalPayout()")
+	selectorSurplus          = selector("surplus()")
+	selectorEventOccurred    = selector("eventOccurred()")
+	selectorPremiumOf        = selector("premiumOf(address)")
+	selectorClaimed          = selector("claimed(address)")
+	selectorSurplusWithdrawn = selector("surplusWithdrawn(address)")
+	selectorMembers          = selector("members(address)")
+)
+
+// Member mirrors the Episode.Member struct
+type Member struct {
+	Joined         bool
+	PayoutClaimed  bool
+	SurplusClaimed bool
+}
+
+// Episode is a read-only binding of an Episode contract
+type Episode struct {
+	caller  Caller
+	address string
+}
+
+// NewEpisode creates a new Episode binding for the contract at address
+func NewEpisode(caller Caller, address string) *Episode {
+	return &Episode{
+		caller:  caller,
+		address: address,
+	}
+}
+
+// Address returns the episode contract address
+func (e *Episode) Address() string {
+	return e.address
+}
+
+// call executes a call and returns at least minWords words of return data
+func (e *Episode) call(name, calldata string, minWords int) ([]byte, error) {
+	result, err := e.caller.EthCall(e.address, calldata)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+
+	data, err := returnData(result, minWords)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return data, nil
+}
+
+// memberCalldata returns the calldata of the call name taking member as its only argument
+func memberCalldata(name, selector, member string) (string, error) {
+	arg, err := encodeAddress(member)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", name, err)
+	}
+	return selector + arg, nil
+}
+
+// callUint executes a call returning a single uint256
+func (e *Episode) callUint(name, calldata string) (*big.Int, error) {
+	data, err := e.call(name, calldata, 1)
+	if err != nil {
+		return nil, err
+	}
+	return decodeUint(word(data, 0)), nil
+}
+
+// callBool executes a call returning a single bool
+func (e *Episode) callBool(name, calldata string) (bool, error) {
+	data, err := e.call(name, calldata, 1)
+	if err != nil {
+		return false, err
+	}
+	return decodeBool(word(data, 0)), nil
+}
+
+// State returns the IEpisode.EpisodeState ordinal
+func (e *Episode) State() (uint8, error) {
+	data, err := e.call("state", selectorState, 1)
+	if err != nil {
+		return 0, err
+	}
+	return uint8(decodeUint64(word(data, 0))), nil
+}
+
+// PremiumAmount returns the premium per member fixed at creation
+func (e *Episode) PremiumAmount() (*big.Int, error) {
+	return e.callUint("premiumAmount", selectorPremiumAmount)
+}
+
+// PayoutAmount returns the payout per member fixed at creation
+func (e *Episode) PayoutAmount() (*big.Int, error) {
+	return e.callUint("payoutAmount", selectorPayoutAmount)
+}
+
+// TotalPremium returns the sum of all premiums paid into the episode
+func (e *Episode) TotalPremium() (*big.Int, error) {
+	return e.callUint("totalPremium", selectorTotalPremium)
+}
+
+// TotalPayout returns the total payout fixed at settlement
+func (e *Episode) TotalPayout() (*big.Int, error) {
+	return e.callUint("totalPayout", selectorTotalPayout)
+}
+
+// Surplus returns the surplus fixed at settlement
+func (e *Episode) Surplus() (*big.Int, error) {
+	return e.callUint("surplus", selectorSurplus)
+}
+
+// EventOccurred reports whether the oracle resolved the insured event as occurred
+func (e *Episode) EventOccurred() (bool, error) {
+	return e.callBool("eventOccurred", selectorEventOccurred)
+}
+
+// PremiumOf returns the premium paid by member
+func (e *Episode) PremiumOf(member string) (*big.Int, error) {
+	calldata, err := memberCalldata("premiumOf", selectorPremiumOf, member)
+	if err != nil {
+		return nil, err
+	}
+	return e.callUint("premiumOf", calldata)
+}
+
+// Claimed reports whether member has claimed the payout
+func (e *Episode) Claimed(member string) (bool, error) {
+	calldata, err := memberCalldata("claimed", selectorClaimed, member)
+	if err != nil {
+		return false, err
+	}
+	return e.callBool("claimed", calldata)
+}
+
+// SurplusWithdrawn reports whether member has withdrawn the surplus share
+func (e *Episode) SurplusWithdrawn(member string) (bool, error) {
+	calldata, err := memberCalldata("surplusWithdrawn", selectorSurplusWithdrawn, member)
+	if err != nil {
+		return false, err
+	}
+	return e.callBool("surplusWithdrawn", calldata)
+}
+
+// Members returns the membership record of member
+func (e *Episode) Members(member string) (*Member, error) {
+	calldata, err := memberCalldata("members", selectorMembers, member)
+	if err != nil {
+		return nil, err
+	}
+	data, err := e.call("members", calldata, 3)
+	if err != nil {
+		return nil, err
+	}
+	return &Member{
+		Joined:         decodeBool(word(data, 0)),
+		PayoutClaimed:  decodeBool(word(data, 1)),
+		SurplusClaimed: decodeBool(word(data, 2)),
+	}, nil
+}
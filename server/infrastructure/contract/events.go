@@ -0,0 +1,103 @@
+package contract
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"eventsure-server/domain/event"
+	"eventsure-server/infrastructure/etherscan"
+)
+
+// eventNames maps Episode event signature hashes to domain event names
+var eventNames = map[string]event.Name{
+	etherscan.EventHashCreated:        event.NameEpisodeCreated,
+	etherscan.EventHashOpen:           event.NameEpisodeOpened,
+	etherscan.EventHashLocked:         event.NameEpisodeLocked,
+	etherscan.EventHashResolved:       event.NameEpisodeResolved,
+	etherscan.EventHashSettled:        event.NameEpisodeSettled,
+	etherscan.EventHashClosed:         event.NameEpisodeClosed,
+	etherscan.EventHashJoin:           event.NameMemberJoined,
+	etherscan.EventHashPayoutClaimed:  event.NamePayoutClaimed,
+	etherscan.EventHashSurplusClaimed: event.NameSurplusClaimed,
+}
+
+// DecodeEpisodeLog decodes an Episode contract log into a domain event.
+// ok is false for logs that are not Episode events.
+func DecodeEpisodeLog(log etherscan.EventLog) (e *event.Event, ok bool, err error) {
+	if len(log.Topics) == 0 {
+		return nil, false, nil
+	}
+
+	name, found := eventNames[strings.ToLower(log.Topics[0])]
+	if !found {
+		return nil, false, nil
+	}
+
+	blockNumber, err := parseHexInt(log.BlockNumber)
+	if err != nil {
+		return nil, false, fmt.Errorf("invalid block number %q: %w", log.BlockNumber, err)
+	}
+	logIndex, err := parseHexInt(log.LogIndex)
+	if err != nil {
+		return nil, false, fmt.Errorf("invalid log index %q: %w", log.LogIndex, err)
+	}
+	timestamp, err := parseHexInt(log.TimeStamp)
+	if err != nil {
+		return nil, false, fmt.Errorf("invalid timestamp %q: %w", log.TimeStamp, err)
+	}
+
+	e = &event.Event{
+		Episode:         strings.ToLower(log.Address),
+		Name:            name,
+		BlockNumber:     blockNumber,
+		LogIndex:        logIndex,
+		TransactionHash: strings.ToLower(log.TransactionHash),
+		Timestamp:       time.Unix(timestamp, 0).UTC(),
+	}
+
+	switch name {
+	case event.NameMemberJoined, event.NamePayoutClaimed, event.NameSurplusClaimed:
+		if len(log.Topics) < 2 {
+			return nil, false, fmt.Errorf("%s log %s is missing the member topic", name, log.TransactionHash)
+		}
+		topic, err := returnData(log.Topics[1], 1)
+		if err != nil {
+			return nil, false, fmt.Errorf("%s member topic: %w", name, err)
+		}
+		data, err := returnData(log.Data, 1)
+		if err != nil {
+			return nil, false, fmt.Errorf("%s data: %w", name, err)
+		}
+		e.Member = decodeAddress(word(topic, 0))
+		e.Amount = decodeUint(word(data, 0))
+
+	case event.NameEpisodeResolved:
+		data, err := returnData(log.Data, 2)
+		if err != nil {
+			return nil, false, fmt.Errorf("%s data: %w", name, err)
+		}
+		e.EventOccurred = decodeBool(word(data, 0))
+		e.FinalArrivalTime = decodeUint64(word(data, 1))
+
+	case event.NameEpisodeSettled:
+		data, err := returnData(log.Data, 2)
+		if err != nil {
+			return nil, false, fmt.Errorf("%s data: %w", name, err)
+		}
+		e.TotalPayout = decodeUint(word(data, 0))
+		e.Surplus = decodeUint(word(data, 1))
+	}
+
+	return e, true, nil
+}
+
+// parseHexInt parses a 0x-prefixed hex quantity; "0x" and "" are zero
+func parseHexInt(s string) (int64, error) {
+	s = strings.TrimPrefix(s, "0x")
+	if s == "" {
+		return 0, nil
+	}
+	return strconv.ParseInt(s, 16, 64)
+}
@@ -0,0 +1,107 @@
+package contract
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+)
+
+var (
+	selectorAllEpisodes = selector("allEpisodes()")
+	selectorEpisodes    = selector("episodes(uint256)")
+	selectorIsEpisode   = selector("isEpisode(address)")
+)
+
+// EpisodeInfo mirrors the EpisodeFactory.EpisodeInfo struct recorded at episode creation
+type EpisodeInfo struct {
+	Episode              string
+	ProductID            string
+	SignupStart          uint64
+	SignupEnd            uint64
+	PremiumAmount        *big.Int
+	PayoutAmount         *big.Int
+	FlightName           string
+	DepartureTime        uint64
+	EstimatedArrivalTime uint64
+}
+
+// Factory is a read-only binding of the EpisodeFactory contract
+type Factory struct {
+	caller  Caller
+	address string
+}
+
+// NewFactory creates a new Factory binding for the contract at address
+func NewFactory(caller Caller, address string) *Factory {
+	return &Factory{
+		caller:  caller,
+		address: address,
+	}
+}
+
+// Address returns the factory contract address
+func (f *Factory) Address() string {
+	return f.address
+}
+
+// AllEpisodes returns the addresses of all episodes in creation order
+func (f *Factory) AllEpisodes() ([]string, error) {
+	result, err := f.caller.EthCall(f.address, selectorAllEpisodes)
+	if err != nil {
+		return nil, fmt.Errorf("allEpisodes: %w", err)
+	}
+
+	data, err := returnData(result, 2)
+	if err != nil {
+		return nil, fmt.Errorf("allEpisodes: %w", err)
+	}
+	return decodeAddressArray(data, 0)
+}
+
+// Episodes returns the creation parameters of the episode at index
+func (f *Factory) Episodes(index int) (*EpisodeInfo, error) {
+	result, err := f.caller.EthCall(f.address, selectorEpisodes+encodeUint(uint64(index)))
+	if err != nil {
+		return nil, fmt.Errorf("episodes(%d): %w", index, err)
+	}
+
+	data, err := returnData(result, 9)
+	if err != nil {
+		return nil, fmt.Errorf("episodes(%d): %w", index, err)
+	}
+
+	flightName, err := decodeString(data, 6)
+	if err != nil {
+		return nil, fmt.Errorf("episodes(%d): %w", index, err)
+	}
+
+	return &EpisodeInfo{
+		Episode:              decodeAddress(word(data, 0)),
+		ProductID:            "0x" + hex.EncodeToString(word(data, 1)),
+		SignupStart:          decodeUint64(word(data, 2)),
+		SignupEnd:            decodeUint64(word(data, 3)),
+		PremiumAmount:        decodeUint(word(data, 4)),
+		PayoutAmount:         decodeUint(word(data, 5)),
+		FlightName:           flightName,
+		DepartureTime:        decodeUint64(word(data, 7)),
+		EstimatedArrivalTime: decodeUint64(word(data, 8)),
+	}, nil
+}
+
+// IsEpisode reports whether address is an episode deployed by this factory
+func (f *Factory) IsEpisode(address string) (bool, error) {
+	arg, err := encodeAddress(address)
+	if err != nil {
+		return false, fmt.Errorf("isEpisode: %w", err)
+	}
+	result, err := f.caller.EthCall(f.address, selectorIsEpisode+arg)
+	if err != nil {
+		return false, fmt.Errorf("isEpisode: %w", err)
+	}
+
+	data, err := returnData(result, 1)
+	if err != nil {
+		return false, fmt.Errorf("isEpisode: %w", err)
+	}
+	return decodeBool(word(data, 0)), nil
+}
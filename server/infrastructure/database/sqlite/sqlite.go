@@ -0,0 +1,34 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+
+	// Registers the pure-Go "sqlite" database/sql driver
+	_ "modernc.org/sqlite"
+)
+
+// pragmas are applied to the connection on open
+var pragmas = []string{
+	"PRAGMA foreign_keys = ON",
+	"PRAGMA journal_mode = WAL",
+	"PRAGMA busy_timeout = 5000",
+}
+
+// Open opens and verifies the SQLite database file at path, creating it when missing.
+// SQLite allows a single writer, so the pool is limited to one connection.
+func Open(path string) (*sql.DB, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open sqlite: %w", err)
+	}
+	db.SetMaxOpenConns(1)
+
+	for _, pragma := range pragmas {
+		if _, err := db.Exec(pragma); err != nil {
+			db.Close()
+			return nil, fmt.Errorf("failed to configure sqlite: %w", err)
+		}
+	}
+	return db, nil
+}
@@ -0,0 +1,111 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"eventsure-server/domain/apikey"
+)
+
+// APIKeyRepository is the SQL implementation of the apikey Repository
+type APIKeyRepository struct {
+	db *sql.DB
+}
+
+// NewAPIKeyRepository creates a new APIKeyRepository
+func NewAPIKeyRepository(db *sql.DB) *APIKeyRepository {
+	return &APIKeyRepository{
+		db: db,
+	}
+}
+
+// Create inserts a new api_keys row
+func (r *APIKeyRepository) Create(ctx context.Context, key *apikey.APIKey) (*apikey.APIKey, error) {
+	var id int64
+	err := r.db.QueryRowContext(ctx,
+		`INSERT INTO api_keys (name, prefix, hash, scopes, rate_limit, created_at) VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id`,
+		key.Name(), key.Prefix(), key.Hash(), apikey.JoinScopes(key.Scopes()), key.RateLimit(), key.CreatedAt(),
+	).Scan(&id)
+	if err != nil {
+		return nil, err
+	}
+
+	return apikey.RestoreAPIKey(id, key.Name(), key.Prefix(), key.Hash(), key.Scopes(), key.RateLimit(), key.CreatedAt(), key.RevokedAt()), nil
+}
+
+// Update updates the revocation time of an api_keys row
+func (r *APIKeyRepository) Update(ctx context.Context, key *apikey.APIKey) error {
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE api_keys SET revoked_at = $1 WHERE id = $2`,
+		nullableTime(key.RevokedAt()), key.ID(),
+	)
+	if err != nil {
+		return err
+	}
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if updated == 0 {
+		return fmt.Errorf("api_key %d not found", key.ID())
+	}
+	return nil
+}
+
+// FindByID finds the api_keys row with the ID
+func (r *APIKeyRepository) FindByID(ctx context.Context, id int64) (*apikey.APIKey, error) {
+	keys, err := r.findBy(ctx, "id = $1", id)
+	if err != nil || len(keys) == 0 {
+		return nil, err
+	}
+	return keys[0], nil
+}
+
+// FindByHash finds the api_keys row with the secret hash
+func (r *APIKeyRepository) FindByHash(ctx context.Context, hash string) (*apikey.APIKey, error) {
+	keys, err := r.findBy(ctx, "hash = $1", hash)
+	if err != nil || len(keys) == 0 {
+		return nil, err
+	}
+	return keys[0], nil
+}
+
+// FindAll finds all api_keys rows
+func (r *APIKeyRepository) FindAll(ctx context.Context) ([]*apikey.APIKey, error) {
+	return r.findBy(ctx, "1 = 1")
+}
+
+// findBy finds all api_keys matching the condition, oldest first
+func (r *APIKeyRepository) findBy(ctx context.Context, condition string, args ...interface{}) ([]*apikey.APIKey, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT id, name, prefix, hash, scopes, rate_limit, created_at, revoked_at FROM api_keys WHERE `+condition+` ORDER BY id`,
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := make([]*apikey.APIKey, 0)
+	for rows.Next() {
+		var (
+			id        int64
+			name      string
+			prefix    string
+			hash      string
+			scopes    string
+			rateLimit int
+			createdAt time.Time
+			revokedAt sql.NullTime
+		)
+		if err := rows.Scan(&id, &name, &prefix, &hash, &scopes, &rateLimit, &createdAt, &revokedAt); err != nil {
+			return nil, err
+		}
+
+		keys = append(keys, apikey.RestoreAPIKey(id, name, prefix, hash, apikey.SplitScopes(scopes), rateLimit, createdAt, revokedAt.Time))
+	}
+	return keys, rows.Err()
+}
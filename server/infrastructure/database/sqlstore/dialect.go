@@ -0,0 +1,43 @@
+package sqlstore
+
+import (
+	"strings"
+)
+
+// Dialect describes the differences between the SQL databases sharing the repositories and migrations
+type Dialect struct {
+	Name string
+	// types replaces the type placeholders used by the migrations
+	types *strings.Replacer
+	// numericOrdering reports whether uint256 amounts are stored as numbers that can be ordered in SQL
+	numericOrdering bool
+}
+
+var (
+	// Postgres is the PostgreSQL dialect
+	Postgres = Dialect{
+		Name: "postgres",
+		types: strings.NewReplacer(
+			"{{ID}}", "BIGSERIAL PRIMARY KEY",
+			"{{TIMESTAMP}}", "TIMESTAMPTZ",
+			"{{UINT256}}", "NUMERIC(78, 0)",
+		),
+		numericOrdering: true,
+	}
+
+	// SQLite is the SQLite dialect. uint256 amounts exceed SQLite's 64-bit integers,
+	// so they are stored as decimal text and ordered in Go.
+	SQLite = Dialect{
+		Name: "sqlite",
+		types: strings.NewReplacer(
+			"{{ID}}", "INTEGER PRIMARY KEY AUTOINCREMENT",
+			"{{TIMESTAMP}}", "TIMESTAMP",
+			"{{UINT256}}", "TEXT",
+		),
+	}
+)
+
+// render replaces the type placeholders of a migration script
+func (d Dialect) render(script string) string {
+	return d.types.Replace(script)
+}
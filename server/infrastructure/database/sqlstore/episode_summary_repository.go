@@ -0,0 +1,205 @@
+package sqlstore
+
+import (
+	"database/sql"
+	"errors"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	eventsureepisode "eventsure-server/domain/episode"
+)
+
+// EpisodeSummaryRepository is the SQL implementation of the episode Summary repository
+type EpisodeSummaryRepository struct {
+	db      *sql.DB
+	dialect Dialect
+}
+
+// NewEpisodeSummaryRepository creates a new EpisodeSummaryRepository
+func NewEpisodeSummaryRepository(db *sql.DB, dialect Dialect) *EpisodeSummaryRepository {
+	return &EpisodeSummaryRepository{
+		db:      db,
+		dialect: dialect,
+	}
+}
+
+// summaryColumns are the episode_summaries columns read into summaries, in scan order
+const summaryColumns = `address, category, flight_name, CAST(premium_amount AS TEXT), CAST(payout_amount AS TEXT),
+	departure_time, estimated_arrival_time, signup_start, signup_end, creation_block,
+	state, CAST(total_premium AS TEXT), CAST(tvl AS TEXT), updated_at`
+
+// sortColumns maps sort fields to the PostgreSQL expression holding SortKey
+var sortColumns = map[eventsureepisode.SortField]string{
+	eventsureepisode.SortByCreationBlock: "creation_block",
+	eventsureepisode.SortByDeparture:     "EXTRACT(EPOCH FROM departure_time)::bigint",
+	eventsureepisode.SortByTVL:           "tvl",
+}
+
+// FindByAddress finds a summary by episode address
+func (r *EpisodeSummaryRepository) FindByAddress(address string) (*eventsureepisode.Summary, error) {
+	rows, err := r.db.Query(`SELECT `+summaryColumns+` FROM episode_summaries WHERE address = $1`, strings.ToLower(address))
+	if err != nil {
+		return nil, err
+	}
+
+	summaries, err := scanSummaries(rows)
+	if err != nil || len(summaries) == 0 {
+		return nil, err
+	}
+	return summaries[0], nil
+}
+
+// FindAll finds all summaries ordered by creation block
+func (r *EpisodeSummaryRepository) FindAll() ([]*eventsureepisode.Summary, error) {
+	return r.FindByQuery(eventsureepisode.SummaryQuery{})
+}
+
+// FindByQuery finds summaries matching the query filters, sorted and paginated.
+// Ties on the sort key are broken by address so the order is stable across calls.
+// Without numeric ordering in SQL the summaries are filtered, sorted and paginated in Go.
+func (r *EpisodeSummaryRepository) FindByQuery(query eventsureepisode.SummaryQuery) ([]*eventsureepisode.Summary, error) {
+	if !r.dialect.numericOrdering {
+		rows, err := r.db.Query(`SELECT ` + summaryColumns + ` FROM episode_summaries`)
+		if err != nil {
+			return nil, err
+		}
+		summaries, err := scanSummaries(rows)
+		if err != nil {
+			return nil, err
+		}
+		return query.Apply(summaries), nil
+	}
+
+	var (
+		conditions []string
+		args       []interface{}
+	)
+	arg := func(v interface{}) string {
+		args = append(args, v)
+		return "$" + strconv.Itoa(len(args))
+	}
+
+	if query.State != "" {
+		conditions = append(conditions, "state = "+arg(string(query.State)))
+	}
+	if query.Category != "" {
+		conditions = append(conditions, "category = "+arg(string(query.Category)))
+	}
+	if query.FlightName != "" {
+		conditions = append(conditions, "LOWER(flight_name) = LOWER("+arg(query.FlightName)+")")
+	}
+	if query.DepartureFrom != nil {
+		conditions = append(conditions, "departure_time >= "+arg(query.DepartureFrom.UTC()))
+	}
+	if query.DepartureTo != nil {
+		conditions = append(conditions, "departure_time <= "+arg(query.DepartureTo.UTC()))
+	}
+	if query.DepartureBefore != nil {
+		conditions = append(conditions, "departure_time < "+arg(query.DepartureBefore.UTC()))
+	}
+
+	sortColumn, ok := sortColumns[query.SortBy]
+	if !ok {
+		sortColumn = sortColumns[eventsureepisode.SortByCreationBlock]
+	}
+	direction, comparison := "ASC", ">"
+	if query.Descending {
+		direction, comparison = "DESC", "<"
+	}
+
+	if query.After != nil {
+		key := arg(query.After.SortKey.String()) + "::numeric"
+		address := arg(strings.ToLower(query.After.Address))
+		conditions = append(conditions, "("+sortColumn+"::numeric, address) "+comparison+" ("+key+", "+address+")")
+	}
+
+	sqlQuery := `SELECT ` + summaryColumns + ` FROM episode_summaries`
+	if len(conditions) > 0 {
+		sqlQuery += ` WHERE ` + strings.Join(conditions, " AND ")
+	}
+	sqlQuery += ` ORDER BY ` + sortColumn + ` ` + direction + `, address ` + direction
+	if query.Limit > 0 {
+		sqlQuery += ` LIMIT ` + arg(query.Limit)
+	}
+
+	rows, err := r.db.Query(sqlQuery, args...)
+	if err != nil {
+		return nil, err
+	}
+	return scanSummaries(rows)
+}
+
+// Save inserts or updates a summary
+func (r *EpisodeSummaryRepository) Save(summary *eventsureepisode.Summary) error {
+	if summary == nil {
+		return errors.New("summary cannot be nil")
+	}
+
+	_, err := r.db.Exec(`INSERT INTO episode_summaries
+	(address, category, flight_name, premium_amount, payout_amount,
+	 departure_time, estimated_arrival_time, signup_start, signup_end, creation_block,
+	 state, total_premium, tvl, updated_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+	ON CONFLICT (address) DO UPDATE SET
+	 creation_block = EXCLUDED.creation_block,
+	 state = EXCLUDED.state,
+	 total_premium = EXCLUDED.total_premium,
+	 tvl = EXCLUDED.tvl,
+	 updated_at = EXCLUDED.updated_at`,
+		strings.ToLower(summary.Address()), string(summary.Category()), summary.FlightName(),
+		summary.PremiumAmount().String(), summary.PayoutAmount().String(),
+		summary.DepartureTime().UTC(), summary.EstimatedArrivalTime().UTC(),
+		summary.SignupStart().UTC(), summary.SignupEnd().UTC(), summary.CreationBlock(),
+		string(summary.State()), summary.TotalPremium().String(), summary.TVL().String(), summary.UpdatedAt().UTC(),
+	)
+	return err
+}
+
+// scanSummaries reads summary rows and closes them
+func scanSummaries(rows *sql.Rows) ([]*eventsureepisode.Summary, error) {
+	defer rows.Close()
+
+	summaries := make([]*eventsureepisode.Summary, 0)
+	for rows.Next() {
+		var (
+			address, category, flightName, state                         string
+			premiumAmount, payoutAmount, totalPremium, tvl               string
+			departure, estimatedArrival, signupStart, signupEnd, updated time.Time
+			creationBlock                                                int64
+		)
+		err := rows.Scan(
+			&address, &category, &flightName, &premiumAmount, &payoutAmount,
+			&departure, &estimatedArrival, &signupStart, &signupEnd, &creationBlock,
+			&state, &totalPremium, &tvl, &updated,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		summary := eventsureepisode.NewSummary(
+			address,
+			flightName,
+			decimal(premiumAmount),
+			decimal(payoutAmount),
+			departure.UTC(),
+			estimatedArrival.UTC(),
+			signupStart.UTC(),
+			signupEnd.UTC(),
+			creationBlock,
+		)
+		summary.RestoreOnChainState(eventsureepisode.State(state), decimal(totalPremium), decimal(tvl), updated)
+		summaries = append(summaries, summary)
+	}
+	return summaries, rows.Err()
+}
+
+// decimal parses a NUMERIC column read as text; invalid values are zero
+func decimal(s string) *big.Int {
+	v, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		return new(big.Int)
+	}
+	return v
+}
@@ -0,0 +1,178 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"eventsure-server/domain/event"
+)
+
+// EventStore is the SQL implementation of the event Store.
+// The episode_events id column is used as the event sequence.
+type EventStore struct {
+	db *sql.DB
+}
+
+// NewEventStore creates a new EventStore
+func NewEventStore(db *sql.DB) *EventStore {
+	return &EventStore{
+		db: db,
+	}
+}
+
+// eventColumns are the episode_events columns read into events, in scan order
+const eventColumns = `id, episode, name, block_number, log_index, transaction_hash, timestamp,
+	member, amount, event_occurred, final_arrival_time, total_payout, surplus`
+
+// Append inserts events, skipping those already stored on (transaction_hash, log_index)
+func (s *EventStore) Append(ctx context.Context, events []*event.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, `INSERT INTO episode_events
+	(episode, name, block_number, log_index, transaction_hash, timestamp,
+	 member, amount, event_occurred, final_arrival_time, total_payout, surplus)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+	ON CONFLICT (transaction_hash, log_index) DO NOTHING`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, e := range events {
+		var (
+			member           sql.NullString
+			amount           sql.NullString
+			eventOccurred    sql.NullBool
+			finalArrivalTime sql.NullInt64
+			totalPayout      sql.NullString
+			surplus          sql.NullString
+		)
+		switch e.Name {
+		case event.NameMemberJoined, event.NamePayoutClaimed, event.NameSurplusClaimed:
+			member = sql.NullString{String: e.Member, Valid: true}
+			amount = nullableBigInt(e.Amount)
+		case event.NameEpisodeResolved:
+			eventOccurred = sql.NullBool{Bool: e.EventOccurred, Valid: true}
+			finalArrivalTime = sql.NullInt64{Int64: int64(e.FinalArrivalTime), Valid: true}
+		case event.NameEpisodeSettled:
+			totalPayout = nullableBigInt(e.TotalPayout)
+			surplus = nullableBigInt(e.Surplus)
+		}
+
+		_, err := stmt.ExecContext(ctx,
+			strings.ToLower(e.Episode), string(e.Name), e.BlockNumber, e.LogIndex,
+			strings.ToLower(e.TransactionHash), e.Timestamp.UTC(),
+			member, amount, eventOccurred, finalArrivalTime, totalPayout, surplus,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// FindByEpisode finds all events of an episode in chain order
+func (s *EventStore) FindByEpisode(ctx context.Context, episode string) ([]*event.Event, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT `+eventColumns+` FROM episode_events WHERE episode = $1 ORDER BY block_number, log_index`,
+		strings.ToLower(episode),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return scanEvents(rows)
+}
+
+// FindAfterSequence finds up to limit events appended after sequence, in append order
+func (s *EventStore) FindAfterSequence(ctx context.Context, sequence int64, limit int) ([]*event.Event, error) {
+	query := `SELECT ` + eventColumns + ` FROM episode_events WHERE id > $1 ORDER BY id`
+	args := []interface{}{sequence}
+	if limit > 0 {
+		query += ` LIMIT $2`
+		args = append(args, limit)
+	}
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return scanEvents(rows)
+}
+
+// scanEvents reads event rows and closes them
+func scanEvents(rows *sql.Rows) ([]*event.Event, error) {
+	defer rows.Close()
+
+	events := make([]*event.Event, 0)
+	for rows.Next() {
+		var (
+			e                event.Event
+			name             string
+			member           sql.NullString
+			amount           sql.NullString
+			eventOccurred    sql.NullBool
+			finalArrivalTime sql.NullInt64
+			totalPayout      sql.NullString
+			surplus          sql.NullString
+		)
+		err := rows.Scan(
+			&e.Sequence, &e.Episode, &name, &e.BlockNumber, &e.LogIndex, &e.TransactionHash, &e.Timestamp,
+			&member, &amount, &eventOccurred, &finalArrivalTime, &totalPayout, &surplus,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		e.Name = event.Name(name)
+		e.Timestamp = e.Timestamp.UTC()
+		e.Member = member.String
+		e.Amount = parseBigInt(amount)
+		e.EventOccurred = eventOccurred.Bool
+		e.FinalArrivalTime = uint64(finalArrivalTime.Int64)
+		e.TotalPayout = parseBigInt(totalPayout)
+		e.Surplus = parseBigInt(surplus)
+		events = append(events, &e)
+	}
+	return events, rows.Err()
+}
+
+// CheckpointRepository is the SQL implementation of the event CheckpointRepository
+type CheckpointRepository struct {
+	db *sql.DB
+}
+
+// NewCheckpointRepository creates a new CheckpointRepository
+func NewCheckpointRepository(db *sql.DB) *CheckpointRepository {
+	return &CheckpointRepository{
+		db: db,
+	}
+}
+
+// FindCheckpoint returns the last indexed block for name, or 0 if there is none
+func (r *CheckpointRepository) FindCheckpoint(ctx context.Context, name string) (int64, error) {
+	var block int64
+	err := r.db.QueryRowContext(ctx, `SELECT block_number FROM indexer_checkpoints WHERE name = $1`, name).Scan(&block)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	return block, err
+}
+
+// SaveCheckpoint saves the last indexed block for name
+func (r *CheckpointRepository) SaveCheckpoint(ctx context.Context, name string, block int64) error {
+	_, err := r.db.ExecContext(ctx, `INSERT INTO indexer_checkpoints (name, block_number, updated_at) VALUES ($1, $2, $3)
+	ON CONFLICT (name) DO UPDATE SET block_number = EXCLUDED.block_number, updated_at = EXCLUDED.updated_at`,
+		name, block, time.Now().UTC())
+	return err
+}
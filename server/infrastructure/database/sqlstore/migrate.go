@@ -0,0 +1,273 @@
+package sqlstore
+
+import (
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// migrationFiles holds the versioned SQL migrations shared by all dialects,
+// named NNNN_description.up.sql / NNNN_description.down.sql.
+// Column types that differ between databases are written as {{ID}}, {{TIMESTAMP}} and {{UINT256}}.
+// A file in migrations/<dialect> replaces the shared file of the same name for that dialect:
+// 0001-0003 were released as PostgreSQL scripts and are kept unchanged, with SQLite versions alongside.
+// Released migrations are never edited; schema changes go in a new version.
+//
+//go:embed migrations/*.sql migrations/sqlite/*.sql
+var migrationFiles embed.FS
+
+// schemaTable records the applied migrations
+const schemaTable = "schema_migrations"
+
+// Migration is a versioned schema change
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Status is a migration and whether it has been applied
+type Status struct {
+	Migration
+	Applied   bool
+	AppliedAt *time.Time
+}
+
+// Migrator applies and rolls back the embedded migrations.
+// Each migration runs in its own transaction together with its schema_migrations row.
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+}
+
+// NewMigrator creates a new Migrator for db with the migrations rendered for dialect
+func NewMigrator(db *sql.DB, dialect Dialect) (*Migrator, error) {
+	fsys, err := fs.Sub(migrationFiles, "migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	migrations, err := loadMigrations(fsys, dialect)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{
+		db:         db,
+		migrations: migrations,
+	}, nil
+}
+
+// loadMigrations reads the migrations of fsys ordered by version, preferring the files
+// in the dialect's directory to the shared ones
+func loadMigrations(fsys fs.FS, dialect Dialect) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+	paths := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			paths[entry.Name()] = entry.Name()
+		}
+	}
+	overrides, err := fs.ReadDir(fsys, dialect.Name)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read %s migrations: %w", dialect.Name, err)
+	}
+	for _, entry := range overrides {
+		if _, ok := paths[entry.Name()]; !ok {
+			return nil, fmt.Errorf("%s migration %q replaces no shared migration", dialect.Name, entry.Name())
+		}
+		paths[entry.Name()] = path.Join(dialect.Name, entry.Name())
+	}
+
+	byVersion := make(map[int]*Migration)
+	for name, file := range paths {
+		var direction string
+		switch {
+		case strings.HasSuffix(name, ".up.sql"):
+			direction = "up"
+		case strings.HasSuffix(name, ".down.sql"):
+			direction = "down"
+		default:
+			continue
+		}
+
+		base := strings.TrimSuffix(name, "."+direction+".sql")
+		prefix, description, ok := strings.Cut(base, "_")
+		if !ok {
+			return nil, fmt.Errorf("invalid migration file name %q", name)
+		}
+		version, err := strconv.Atoi(prefix)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version in %q", name)
+		}
+
+		content, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", file, err)
+		}
+
+		m, exists := byVersion[version]
+		if !exists {
+			m = &Migration{Version: version, Name: description}
+			byVersion[version] = m
+		} else if m.Name != description {
+			return nil, fmt.Errorf("migration %d has conflicting names %q and %q", version, m.Name, description)
+		}
+		if direction == "up" {
+			m.Up = dialect.render(string(content))
+		} else {
+			m.Down = dialect.render(string(content))
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" {
+			return nil, fmt.Errorf("migration %d_%s has no up script", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+// ensureSchemaTable creates the schema_migrations table if it does not exist
+func (m *Migrator) ensureSchemaTable() error {
+	_, err := m.db.Exec(`CREATE TABLE IF NOT EXISTS ` + schemaTable + ` (
+    version BIGINT PRIMARY KEY,
+    name VARCHAR NOT NULL,
+    applied_at TIMESTAMP NOT NULL
+)`)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", schemaTable, err)
+	}
+	return nil
+}
+
+// applied returns the applied migration versions and their application times
+func (m *Migrator) applied() (map[int]time.Time, error) {
+	if err := m.ensureSchemaTable(); err != nil {
+		return nil, err
+	}
+
+	rows, err := m.db.Query(`SELECT version, applied_at FROM ` + schemaTable)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", schemaTable, err)
+	}
+	defer rows.Close()
+
+	applied := make(map[int]time.Time)
+	for rows.Next() {
+		var (
+			version   int
+			appliedAt time.Time
+		)
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", schemaTable, err)
+		}
+		applied[version] = appliedAt
+	}
+	return applied, rows.Err()
+}
+
+// Status returns every known migration and whether it has been applied
+func (m *Migrator) Status() ([]Status, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, len(m.migrations))
+	for i, migration := range m.migrations {
+		statuses[i] = Status{Migration: migration}
+		if appliedAt, ok := applied[migration.Version]; ok {
+			appliedAt := appliedAt
+			statuses[i].Applied = true
+			statuses[i].AppliedAt = &appliedAt
+		}
+	}
+	return statuses, nil
+}
+
+// Up applies all pending migrations in version order and returns the applied ones
+func (m *Migrator) Up() ([]Migration, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+		err := m.run(migration, migration.Up, func(tx *sql.Tx) error {
+			_, err := tx.Exec(`INSERT INTO `+schemaTable+` (version, name, applied_at) VALUES ($1, $2, $3)`,
+				migration.Version, migration.Name, time.Now().UTC())
+			return err
+		})
+		if err != nil {
+			return done, err
+		}
+		done = append(done, migration)
+	}
+	return done, nil
+}
+
+// Down rolls back the latest steps applied migrations and returns the rolled back ones
+func (m *Migrator) Down(steps int) ([]Migration, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for i := len(m.migrations) - 1; i >= 0 && len(done) < steps; i-- {
+		migration := m.migrations[i]
+		if _, ok := applied[migration.Version]; !ok {
+			continue
+		}
+		if migration.Down == "" {
+			return done, fmt.Errorf("migration %d_%s has no down script", migration.Version, migration.Name)
+		}
+		err := m.run(migration, migration.Down, func(tx *sql.Tx) error {
+			_, err := tx.Exec(`DELETE FROM `+schemaTable+` WHERE version = $1`, migration.Version)
+			return err
+		})
+		if err != nil {
+			return done, err
+		}
+		done = append(done, migration)
+	}
+	return done, nil
+}
+
+// run executes script and record in a single transaction
+func (m *Migrator) run(migration Migration, script string, record func(tx *sql.Tx) error) error {
+	tx, err := m.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin migration %d: %w", migration.Version, err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(script); err != nil {
+		return fmt.Errorf("migration %d_%s failed: %w", migration.Version, migration.Name, err)
+	}
+	if err := record(tx); err != nil {
+		return fmt.Errorf("failed to record migration %d: %w", migration.Version, err)
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,96 @@
+package sqlstore
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io/fs"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// releasedMigrations pins the checksums of the migrations already applied by deployed databases.
+// Editing one of them does not reach those databases; add a new version instead.
+var releasedMigrations = map[string]string{
+	"0001_create_user_episodes.up.sql":       "c2b5b8a22ec1d8d1c66a4e733d7e2128c76c41147e66c772371805c7aade3e99",
+	"0001_create_user_episodes.down.sql":     "56e0ea63a1881996cad6f4af8bc8341f9781ed4b0a766e8eb25e3c5d0da30877",
+	"0002_create_episode_events.up.sql":      "f03f57880dc2e521a79342f23a6abea6b4e8b0cdac926a92146dc56b36183856",
+	"0002_create_episode_events.down.sql":    "f0fecf4db57a2488ed165bff6b8e0cc30d0d283442e9fd3281f215475e1a6b40",
+	"0003_create_episode_summaries.up.sql":   "569c0c0173ce1dd8e302a80605c693cc1eb35c9b203fac068ec63c8a2166b7c0",
+	"0003_create_episode_summaries.down.sql": "feb5cb9c27ce372641c2dbb711d2cfb088e3a5af5f3e5931310f04590bbf1b9c",
+}
+
+func TestReleasedMigrationsUnchanged(t *testing.T) {
+	for name, want := range releasedMigrations {
+		content, err := fs.ReadFile(migrationFiles, "migrations/"+name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sum := sha256.Sum256(content)
+		if got := hex.EncodeToString(sum[:]); got != want {
+			t.Errorf("released migration %s was edited (sha256 %s)", name, got)
+		}
+	}
+}
+
+func TestLoadMigrationsPrefersDialectFiles(t *testing.T) {
+	fsys := fstest.MapFS{
+		"0001_init.up.sql":          {Data: []byte("CREATE TABLE t (id BIGSERIAL)")},
+		"0001_init.down.sql":        {Data: []byte("DROP TABLE t")},
+		"0002_next.up.sql":          {Data: []byte("CREATE TABLE u (id {{ID}})")},
+		"sqlite/0001_init.up.sql":   {Data: []byte("CREATE TABLE t (id INTEGER)")},
+		"postgres/0002_next.up.sql": {Data: []byte("CREATE TABLE u (id BIGINT)")},
+	}
+
+	tests := []struct {
+		dialect Dialect
+		want    []string
+	}{
+		{Postgres, []string{"CREATE TABLE t (id BIGSERIAL)", "CREATE TABLE u (id BIGINT)"}},
+		{SQLite, []string{"CREATE TABLE t (id INTEGER)", SQLite.render("CREATE TABLE u (id {{ID}})")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.dialect.Name, func(t *testing.T) {
+			migrations, err := loadMigrations(fsys, tt.dialect)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(migrations) != len(tt.want) {
+				t.Fatalf("loaded %d migrations, want %d", len(migrations), len(tt.want))
+			}
+			for i, m := range migrations {
+				if m.Up != tt.want[i] {
+					t.Errorf("migration %d up = %q, want %q", m.Version, m.Up, tt.want[i])
+				}
+			}
+			if migrations[0].Down != "DROP TABLE t" {
+				t.Errorf("shared down script was not kept, got %q", migrations[0].Down)
+			}
+		})
+	}
+}
+
+func TestLoadMigrationsRejectsOrphanDialectFile(t *testing.T) {
+	fsys := fstest.MapFS{
+		"0001_init.up.sql":        {Data: []byte("CREATE TABLE t (id {{ID}})")},
+		"sqlite/0002_next.up.sql": {Data: []byte("CREATE TABLE u (id INTEGER)")},
+	}
+	_, err := loadMigrations(fsys, SQLite)
+	if err == nil || !strings.Contains(err.Error(), "replaces no shared migration") {
+		t.Fatalf("loadMigrations error = %v, want an orphan override error", err)
+	}
+}
+
+func TestEmbeddedMigrationsLoad(t *testing.T) {
+	for _, dialect := range []Dialect{Postgres, SQLite} {
+		m, err := NewMigrator(nil, dialect)
+		if err != nil {
+			t.Fatalf("%s: %v", dialect.Name, err)
+		}
+		for _, migration := range m.migrations {
+			if strings.Contains(migration.Up, "{{") || strings.Contains(migration.Down, "{{") {
+				t.Errorf("%s: migration %d has unrendered placeholders", dialect.Name, migration.Version)
+			}
+		}
+	}
+}
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS user_episodes (
+    id BIGSERIAL PRIMARY KEY,
+    "user" VARCHAR NOT NULL,
+    episode VARCHAR NOT NULL,
+    progress VARCHAR,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS user_episodes_user_idx ON user_episodes ("user");
+CREATE INDEX IF NOT EXISTS user_episodes_episode_idx ON user_episodes (episode);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS indexer_checkpoints;
+DROP TABLE IF EXISTS episode_events;
@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS episode_events (
+    id BIGSERIAL PRIMARY KEY,
+    episode VARCHAR NOT NULL,
+    name VARCHAR NOT NULL,
+    block_number BIGINT NOT NULL,
+    log_index BIGINT NOT NULL,
+    transaction_hash VARCHAR NOT NULL,
+    timestamp TIMESTAMPTZ NOT NULL,
+    member VARCHAR,
+    amount TEXT,
+    event_occurred BOOLEAN,
+    final_arrival_time BIGINT,
+    total_payout TEXT,
+    surplus TEXT,
+    UNIQUE (transaction_hash, log_index)
+);
+
+CREATE INDEX IF NOT EXISTS episode_events_episode_idx ON episode_events (episode, block_number, log_index);
+
+CREATE TABLE IF NOT EXISTS indexer_checkpoints (
+    name VARCHAR PRIMARY KEY,
+    block_number BIGINT NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
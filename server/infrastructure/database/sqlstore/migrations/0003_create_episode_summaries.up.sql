@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS episode_summaries (
+    address VARCHAR PRIMARY KEY,
+    category VARCHAR NOT NULL,
+    flight_name VARCHAR NOT NULL,
+    premium_amount NUMERIC(78, 0) NOT NULL,
+    payout_amount NUMERIC(78, 0) NOT NULL,
+    departure_time TIMESTAMPTZ NOT NULL,
+    estimated_arrival_time TIMESTAMPTZ NOT NULL,
+    signup_start TIMESTAMPTZ NOT NULL,
+    signup_end TIMESTAMPTZ NOT NULL,
+    creation_block BIGINT NOT NULL,
+    state VARCHAR NOT NULL,
+    total_premium NUMERIC(78, 0) NOT NULL,
+    tvl NUMERIC(78, 0) NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL
+);
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS user_episodes_user_episode_key;
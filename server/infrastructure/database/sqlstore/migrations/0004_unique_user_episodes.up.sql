@@ -0,0 +1,6 @@
+UPDATE user_episodes SET "user" = LOWER("user"), episode = LOWER(episode);
+
+DELETE FROM user_episodes
+WHERE id NOT IN (SELECT MIN(id) FROM user_episodes GROUP BY "user", episode);
+
+CREATE UNIQUE INDEX IF NOT EXISTS user_episodes_user_episode_key ON user_episodes ("user", episode);
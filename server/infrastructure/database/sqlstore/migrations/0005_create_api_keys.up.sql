@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS api_keys (
+    id {{ID}},
+    name VARCHAR NOT NULL,
+    prefix VARCHAR NOT NULL,
+    hash VARCHAR NOT NULL,
+    scopes VARCHAR NOT NULL,
+    rate_limit INTEGER NOT NULL DEFAULT 0,
+    created_at {{TIMESTAMP}} NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    revoked_at {{TIMESTAMP}}
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS api_keys_hash_key ON api_keys (hash);
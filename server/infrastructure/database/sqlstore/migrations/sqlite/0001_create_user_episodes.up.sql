@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS user_episodes (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    "user" VARCHAR NOT NULL,
+    episode VARCHAR NOT NULL,
+    progress VARCHAR,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS user_episodes_user_idx ON user_episodes ("user");
+CREATE INDEX IF NOT EXISTS user_episodes_episode_idx ON user_episodes (episode);
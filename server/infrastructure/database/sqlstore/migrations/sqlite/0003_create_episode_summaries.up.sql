@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS episode_summaries (
+    address VARCHAR PRIMARY KEY,
+    category VARCHAR NOT NULL,
+    flight_name VARCHAR NOT NULL,
+    premium_amount TEXT NOT NULL,
+    payout_amount TEXT NOT NULL,
+    departure_time TIMESTAMP NOT NULL,
+    estimated_arrival_time TIMESTAMP NOT NULL,
+    signup_start TIMESTAMP NOT NULL,
+    signup_end TIMESTAMP NOT NULL,
+    creation_block BIGINT NOT NULL,
+    state VARCHAR NOT NULL,
+    total_premium TEXT NOT NULL,
+    tvl TEXT NOT NULL,
+    updated_at TIMESTAMP NOT NULL
+);
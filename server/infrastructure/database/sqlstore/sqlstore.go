@@ -0,0 +1,37 @@
+package sqlstore
+
+import (
+	"database/sql"
+	"math/big"
+	"time"
+)
+
+// nullableString returns a NULL for empty strings
+func nullableString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+// nullableTime returns a NULL for the zero time
+func nullableTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
+
+// nullableBigInt returns a NULL for nil amounts and the decimal string otherwise
+func nullableBigInt(v *big.Int) sql.NullString {
+	if v == nil {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: v.String(), Valid: true}
+}
+
+// parseBigInt parses a decimal amount; NULL and invalid values are nil
+func parseBigInt(s sql.NullString) *big.Int {
+	if !s.Valid {
+		return nil
+	}
+	v, ok := new(big.Int).SetString(s.String, 10)
+	if !ok {
+		return nil
+	}
+	return v
+}
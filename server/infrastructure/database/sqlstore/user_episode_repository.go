@@ -0,0 +1,128 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"eventsure-server/domain/membership"
+)
+
+// UserEpisodeRepository is the SQL implementation of the membership Repository
+type UserEpisodeRepository struct {
+	db *sql.DB
+}
+
+// NewUserEpisodeRepository creates a new UserEpisodeRepository
+func NewUserEpisodeRepository(db *sql.DB) *UserEpisodeRepository {
+	return &UserEpisodeRepository{
+		db: db,
+	}
+}
+
+// Create inserts a new user_episodes row, or returns membership.ErrDuplicate
+// when the user already has a row for the episode
+func (r *UserEpisodeRepository) Create(ctx context.Context, userEpisode *membership.UserEpisode) (*membership.UserEpisode, error) {
+	var (
+		id        int64
+		createdAt time.Time
+	)
+	err := r.db.QueryRowContext(ctx,
+		`INSERT INTO user_episodes ("user", episode, progress, created_at) VALUES ($1, $2, $3, $4)
+		ON CONFLICT ("user", episode) DO NOTHING
+		RETURNING id, created_at`,
+		userEpisode.User(), userEpisode.Episode(), nullableString(string(userEpisode.Progress())), userEpisode.CreatedAt(),
+	).Scan(&id, &createdAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, membership.ErrDuplicate
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return membership.RestoreUserEpisode(id, userEpisode.User(), userEpisode.Episode(), userEpisode.Progress(), createdAt), nil
+}
+
+// Update updates the progress of a user_episodes row
+func (r *UserEpisodeRepository) Update(ctx context.Context, userEpisode *membership.UserEpisode) error {
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE user_episodes SET progress = $1 WHERE id = $2`,
+		nullableString(string(userEpisode.Progress())), userEpisode.ID(),
+	)
+	if err != nil {
+		return err
+	}
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if updated == 0 {
+		return fmt.Errorf("user_episode %d not found", userEpisode.ID())
+	}
+	return nil
+}
+
+// FindByID finds the user_episodes row with the ID
+func (r *UserEpisodeRepository) FindByID(ctx context.Context, id int64) (*membership.UserEpisode, error) {
+	userEpisodes, err := r.findBy(ctx, "id = $1", id)
+	if err != nil || len(userEpisodes) == 0 {
+		return nil, err
+	}
+	return userEpisodes[0], nil
+}
+
+// FindByUserAndEpisode finds the user_episodes row of user for episode
+func (r *UserEpisodeRepository) FindByUserAndEpisode(ctx context.Context, user, episode string) (*membership.UserEpisode, error) {
+	userEpisodes, err := r.findBy(ctx, `"user" = $1 AND episode = $2`, strings.ToLower(user), strings.ToLower(episode))
+	if err != nil || len(userEpisodes) == 0 {
+		return nil, err
+	}
+	return userEpisodes[0], nil
+}
+
+// FindByUser finds all user_episodes for a specific user
+func (r *UserEpisodeRepository) FindByUser(ctx context.Context, user string) ([]*membership.UserEpisode, error) {
+	return r.findBy(ctx, `"user" = $1`, strings.ToLower(user))
+}
+
+// FindByEpisode finds all user_episodes for a specific episode
+func (r *UserEpisodeRepository) FindByEpisode(ctx context.Context, episode string) ([]*membership.UserEpisode, error) {
+	return r.findBy(ctx, "episode = $1", strings.ToLower(episode))
+}
+
+// FindWithoutProgress finds all user_episodes whose progress has not been set yet
+func (r *UserEpisodeRepository) FindWithoutProgress(ctx context.Context) ([]*membership.UserEpisode, error) {
+	return r.findBy(ctx, "progress IS NULL")
+}
+
+// findBy finds all user_episodes matching the condition, oldest first
+func (r *UserEpisodeRepository) findBy(ctx context.Context, condition string, args ...interface{}) ([]*membership.UserEpisode, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT id, "user", episode, progress, created_at FROM user_episodes WHERE `+condition+` ORDER BY id`,
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	userEpisodes := make([]*membership.UserEpisode, 0)
+	for rows.Next() {
+		var (
+			id        int64
+			user      string
+			episode   string
+			progress  sql.NullString
+			createdAt time.Time
+		)
+		if err := rows.Scan(&id, &user, &episode, &progress, &createdAt); err != nil {
+			return nil, err
+		}
+
+		userEpisodes = append(userEpisodes, membership.RestoreUserEpisode(id, user, episode, membership.Progress(progress.String), createdAt))
+	}
+	return userEpisodes, rows.Err()
+}
@@ -2,9 +2,10 @@ package database
 
 import (
 	"fmt"
-	"os"
 	"strconv"
 
+	"eventsure-server/infrastructure/config"
+
 	supabase "github.com/supabase-community/supabase-go"
 )
 
@@ -15,20 +16,16 @@ type SupabaseRESTClient struct {
 
 // NewSupabaseRESTClient creates a new Supabase REST API client
 // Uses Project URL and API Key for authentication
-func NewSupabaseRESTClient() (*SupabaseRESTClient, error) {
-	// Get Supabase project URL and API key from environment variables
-	supabaseURL := os.Getenv("SUPABASE_PROJECT_URL")
-	supabaseKey := os.Getenv("SUPABASE_API_KEY")
-
-	if supabaseURL == "" {
-		return nil, fmt.Errorf("SUPABASE_PROJECT_URL or SUPABASE_URL must be set")
+func NewSupabaseRESTClient(cfg config.Supabase) (*SupabaseRESTClient, error) {
+	if cfg.ProjectURL == "" {
+		return nil, fmt.Errorf("SUPABASE_PROJECT_URL must be set")
 	}
-	if supabaseKey == "" {
-		return nil, fmt.Errorf("SUPABASE_API_KEY, SUPABASE_ANON_KEY, or SUPABASE_PUBLISHABLE_KEY must be set")
+	if cfg.APIKey == "" {
+		return nil, fmt.Errorf("SUPABASE_API_KEY must be set")
 	}
 
 	// Create Supabase client
-	client, err := supabase.NewClient(supabaseURL, supabaseKey, nil)
+	client, err := supabase.NewClient(cfg.ProjectURL, cfg.APIKey, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create Supabase client: %w", err)
 	}
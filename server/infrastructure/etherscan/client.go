@@ -1,16 +1,23 @@
 package etherscan
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
 	"math/rand"
 	"net/http"
 	"net/url"
-	"os"
 	"strconv"
 	"strings"
 	"time"
+
+	"eventsure-server/infrastructure/config"
+	"eventsure-server/infrastructure/metrics"
+	"eventsure-server/infrastructure/tracing"
+
+	"go.opentelemetry.io/otel/attribute"
 )
 
 const (
@@ -18,6 +25,9 @@ const (
 	EtherscanAPIBaseURL = "https://api.etherscan.io/v2/api"
 	// DefaultChainID is Ethereum mainnet
 	DefaultChainID = "1"
+
+	// noRecordsFoundMessage is the message Etherscan returns for queries without results
+	noRecordsFoundMessage = "No records found"
 )
 
 // Episode event signature hashes (keccak256 of event signatures)
@@ -33,15 +43,27 @@ const (
 	EventHashLocked = "0xf11bd1aff5803887bfd7a098dbc630480ac167ae541d416d1cad2ba71e3a3a07"
 	// Resolved event hash
 	EventHashResolved = "0x5ca8c0d4010082eaf8ea9b4a485d921877231e7522f79b654b9a4d5fee0efcb9"
+	// Settled event hash
+	EventHashSettled = "0xe8e892f2e7a9b4641dbefce4449775ad1b9cd86669e3d1b325b3faefec9a5356"
+	// Closed event hash
+	EventHashClosed = "0x2fe75601fe020a7d0056d66f45d52ce62a56d9f24e5d5d5165adc524c458d551"
+	// PayoutClaimed event hash
+	EventHashPayoutClaimed = "0xec68461f5d4cc45c89e914cb8826a966c73dd35e5f97815ece0a01ffa4a025a6"
+	// SurplusClaimed event hash
+	EventHashSurplusClaimed = "0x4e1aeeaffeaa761537f0466076928c277ee7b2f0460c07ca2e597f06e7cd6b60"
 )
 
 // EpisodeEventMap maps event signature hashes to their event names
 var EpisodeEventMap = map[string]string{
-	EventHashCreated:  "Created",
-	EventHashOpen:     "Open",
-	EventHashJoin:     "Join",
-	EventHashLocked:   "Locked",
-	EventHashResolved: "Resolved",
+	EventHashCreated:        "Created",
+	EventHashOpen:           "Open",
+	EventHashJoin:           "Join",
+	EventHashLocked:         "Locked",
+	EventHashResolved:       "Resolved",
+	EventHashSettled:        "Settled",
+	EventHashClosed:         "Closed",
+	EventHashPayoutClaimed:  "PayoutClaimed",
+	EventHashSurplusClaimed: "SurplusClaimed",
 }
 
 // EtherscanClient represents an Etherscan API client
@@ -51,26 +73,17 @@ type EtherscanClient struct {
 	baseURL    string
 	client     *http.Client
 	maxRetries int
+	ctx        context.Context
 }
 
 // NewEtherscanClient creates a new Etherscan API client
-func NewEtherscanClient() (*EtherscanClient, error) {
-	apiKey1 := os.Getenv("ETHERSCAN_API_KEY_1")
-	apiKey2 := os.Getenv("ETHERSCAN_API_KEY_2")
-
-	var apiKeys []string
-	if apiKey1 != "" {
-		apiKeys = append(apiKeys, apiKey1)
-	}
-	if apiKey2 != "" {
-		apiKeys = append(apiKeys, apiKey2)
-	}
-
+func NewEtherscanClient(cfg config.Etherscan) (*EtherscanClient, error) {
+	apiKeys := cfg.APIKeys()
 	if len(apiKeys) == 0 {
 		return nil, fmt.Errorf("at least one of ETHERSCAN_API_KEY_1 or ETHERSCAN_API_KEY_2 must be set")
 	}
 
-	chainID := os.Getenv("ETHERSCAN_CHAIN_ID")
+	chainID := cfg.ChainID
 	if chainID == "" {
 		chainID = DefaultChainID
 	}
@@ -83,12 +96,23 @@ func NewEtherscanClient() (*EtherscanClient, error) {
 		client: &http.Client{
 			Timeout: 30 * time.Second,
 		},
+		ctx: context.Background(),
 	}, nil
 }
 
-// getRandomAPIKey returns a random API key from the available keys
-func (c *EtherscanClient) getRandomAPIKey() string {
-	return c.apiKeys[rand.Intn(len(c.apiKeys))]
+// WithContext returns a copy of the client whose requests use ctx: they are cancelled
+// with it and logged with its request ID
+func (c *EtherscanClient) WithContext(ctx context.Context) *EtherscanClient {
+	copied := *c
+	copied.ctx = ctx
+	return &copied
+}
+
+// getRandomAPIKey returns a random API key from the available keys, and its label
+// (key1, key2) for metrics, which must not expose the key itself
+func (c *EtherscanClient) getRandomAPIKey() (key string, label string) {
+	i := rand.Intn(len(c.apiKeys))
+	return c.apiKeys[i], "key" + strconv.Itoa(i+1)
 }
 
 // InternalTransaction represents an internal transaction
@@ -128,83 +152,57 @@ type GetInternalTransactionsParams struct {
 
 // GetInternalTransactions retrieves the internal transaction history of a specified address
 func (c *EtherscanClient) GetInternalTransactions(params GetInternalTransactionsParams) (*InternalTransactionsResponse, error) {
-	var lastErr error
-
-	for attempt := 0; attempt <= c.maxRetries; attempt++ {
-		queryParams := url.Values{}
-		queryParams.Set("apikey", c.getRandomAPIKey())
-		queryParams.Set("chainid", c.chainID)
-		queryParams.Set("module", "account")
-		queryParams.Set("action", "txlistinternal")
-		queryParams.Set("address", params.Address)
-
-		if params.StartBlock != nil {
-			queryParams.Set("startblock", strconv.FormatInt(*params.StartBlock, 10))
-		} else {
-			queryParams.Set("startblock", "0")
-		}
-
-		if params.EndBlock != nil {
-			queryParams.Set("endblock", strconv.FormatInt(*params.EndBlock, 10))
-		} else {
-			queryParams.Set("endblock", "9999999999")
-		}
-
-		if params.Page != nil {
-			queryParams.Set("page", strconv.Itoa(*params.Page))
-		} else {
-			queryParams.Set("page", "1")
-		}
-
-		if params.Offset != nil {
-			queryParams.Set("offset", strconv.Itoa(*params.Offset))
-		} else {
-			queryParams.Set("offset", "1000")
-		}
-
-		if params.Sort != "" {
-			queryParams.Set("sort", params.Sort)
-		} else {
-			queryParams.Set("sort", "desc")
-		}
+	queryParams := url.Values{}
+	queryParams.Set("module", "account")
+	queryParams.Set("action", "txlistinternal")
+	queryParams.Set("address", params.Address)
+
+	if params.StartBlock != nil {
+		queryParams.Set("startblock", strconv.FormatInt(*params.StartBlock, 10))
+	} else {
+		queryParams.Set("startblock", "0")
+	}
 
-		reqURL := fmt.Sprintf("%s?%s", c.baseURL, queryParams.Encode())
+	if params.EndBlock != nil {
+		queryParams.Set("endblock", strconv.FormatInt(*params.EndBlock, 10))
+	} else {
+		queryParams.Set("endblock", "9999999999")
+	}
 
-		resp, err := c.client.Get(reqURL)
-		if err != nil {
-			lastErr = fmt.Errorf("failed to make request: %w", err)
-			continue
-		}
+	if params.Page != nil {
+		queryParams.Set("page", strconv.Itoa(*params.Page))
+	} else {
+		queryParams.Set("page", "1")
+	}
 
-		if resp.StatusCode != http.StatusOK {
-			body, _ := io.ReadAll(resp.Body)
-			resp.Body.Close()
-			lastErr = fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(body))
-			continue
-		}
+	if params.Offset != nil {
+		queryParams.Set("offset", strconv.Itoa(*params.Offset))
+	} else {
+		queryParams.Set("offset", "1000")
+	}
 
-		body, err := io.ReadAll(resp.Body)
-		resp.Body.Close()
-		if err != nil {
-			lastErr = fmt.Errorf("failed to read response body: %w", err)
-			continue
-		}
+	if params.Sort != "" {
+		queryParams.Set("sort", params.Sort)
+	} else {
+		queryParams.Set("sort", "desc")
+	}
 
-		var result InternalTransactionsResponse
+	var result InternalTransactionsResponse
+	err := c.execute(queryParams, func(body []byte) error {
+		result = InternalTransactionsResponse{}
 		if err := json.Unmarshal(body, &result); err != nil {
-			lastErr = fmt.Errorf("failed to unmarshal response: %w", err)
-			continue
+			return fmt.Errorf("failed to unmarshal response: %w", err)
 		}
-
 		if result.Status != "1" {
-			lastErr = fmt.Errorf("etherscan API error: %s", result.Message)
-			continue
+			return fmt.Errorf("etherscan API error: %s", result.Message)
 		}
-
-		return &result, nil
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return nil, fmt.Errorf("failed after %d retries: %w", c.maxRetries+1, lastErr)
+	return &result, nil
 }
 
 // EventLog represents an event log
@@ -240,77 +238,143 @@ type GetEventLogsParams struct {
 
 // GetEventLogs retrieves event logs from a specific address
 func (c *EtherscanClient) GetEventLogs(params GetEventLogsParams) (*EventLogsResponse, error) {
-	var lastErr error
+	queryParams := url.Values{}
+	queryParams.Set("module", "logs")
+	queryParams.Set("action", "getLogs")
+	queryParams.Set("address", params.Address)
+
+	if params.FromBlock != nil {
+		queryParams.Set("fromBlock", strconv.FormatInt(*params.FromBlock, 10))
+	} else {
+		queryParams.Set("fromBlock", "0")
+	}
 
-	for attempt := 0; attempt <= c.maxRetries; attempt++ {
-		queryParams := url.Values{}
-		queryParams.Set("apikey", c.getRandomAPIKey())
-		queryParams.Set("chainid", c.chainID)
-		queryParams.Set("module", "logs")
-		queryParams.Set("action", "getLogs")
-		queryParams.Set("address", params.Address)
-
-		if params.FromBlock != nil {
-			queryParams.Set("fromBlock", strconv.FormatInt(*params.FromBlock, 10))
-		} else {
-			queryParams.Set("fromBlock", "0")
-		}
+	if params.ToBlock != nil {
+		queryParams.Set("toBlock", strconv.FormatInt(*params.ToBlock, 10))
+	} else {
+		queryParams.Set("toBlock", "latest")
+	}
 
-		if params.ToBlock != nil {
-			queryParams.Set("toBlock", strconv.FormatInt(*params.ToBlock, 10))
-		} else {
-			queryParams.Set("toBlock", "latest")
-		}
+	if params.Page != nil {
+		queryParams.Set("page", strconv.Itoa(*params.Page))
+	} else {
+		queryParams.Set("page", "1")
+	}
 
-		if params.Page != nil {
-			queryParams.Set("page", strconv.Itoa(*params.Page))
-		} else {
-			queryParams.Set("page", "1")
-		}
+	if params.Offset != nil {
+		queryParams.Set("offset", strconv.Itoa(*params.Offset))
+	} else {
+		queryParams.Set("offset", "1000")
+	}
 
-		if params.Offset != nil {
-			queryParams.Set("offset", strconv.Itoa(*params.Offset))
-		} else {
-			queryParams.Set("offset", "1000")
+	var result EventLogsResponse
+	err := c.execute(queryParams, func(body []byte) error {
+		result = EventLogsResponse{}
+		if err := json.Unmarshal(body, &result); err != nil {
+			return fmt.Errorf("failed to unmarshal response: %w", err)
 		}
+		// An empty block range is reported as status "0" rather than an empty result
+		if result.Status == "0" && result.Message == noRecordsFoundMessage {
+			result.Result = []EventLog{}
+			return nil
+		}
+		if result.Status != "1" {
+			return fmt.Errorf("etherscan API error: %s", result.Message)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
 
-		reqURL := fmt.Sprintf("%s?%s", c.baseURL, queryParams.Encode())
+	return &result, nil
+}
 
-		resp, err := c.client.Get(reqURL)
-		if err != nil {
-			lastErr = fmt.Errorf("failed to make request: %w", err)
-			continue
-		}
+// execute sends a GET request with the given query parameters, retrying up to maxRetries times.
+// Each attempt uses a random API key and is recorded in the Etherscan metrics and as a span.
+// decode is called with the response body and returning an error from it marks the attempt as failed.
+func (c *EtherscanClient) execute(queryParams url.Values, decode func(body []byte) error) error {
+	var lastErr error
+	action := queryParams.Get("action")
 
-		if resp.StatusCode != http.StatusOK {
-			body, _ := io.ReadAll(resp.Body)
-			resp.Body.Close()
-			lastErr = fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(body))
-			continue
-		}
+	for attempt := 0; attempt <= c.maxRetries; attempt++ {
+		apiKey, keyLabel := c.getRandomAPIKey()
+		queryParams.Set("apikey", apiKey)
+		queryParams.Set("chainid", c.chainID)
 
-		body, err := io.ReadAll(resp.Body)
-		resp.Body.Close()
+		ctx, span := tracing.StartClient(c.ctx, "etherscan "+action,
+			attribute.String("etherscan.action", action),
+			attribute.Int("etherscan.attempt", attempt+1),
+			attribute.String("etherscan.api_key", keyLabel),
+		)
+		start := time.Now()
+		reason, err := c.attempt(ctx, queryParams, decode)
+		duration := time.Since(start)
+		metrics.ObserveEtherscanAttempt(action, keyLabel, duration, reason)
+		if reason != "" {
+			span.SetAttributes(attribute.String("etherscan.error_reason", reason))
+		}
+		tracing.End(span, err)
 		if err != nil {
-			lastErr = fmt.Errorf("failed to read response body: %w", err)
+			slog.WarnContext(ctx, "etherscan request failed",
+				"action", action,
+				"attempt", attempt+1,
+				"api_key", keyLabel,
+				"duration_ms", float64(duration.Microseconds())/1000,
+				"reason", reason,
+				"error", err,
+			)
+			if c.ctx.Err() != nil {
+				return fmt.Errorf("request cancelled: %w", err)
+			}
+			lastErr = err
 			continue
 		}
 
-		var result EventLogsResponse
-		if err := json.Unmarshal(body, &result); err != nil {
-			lastErr = fmt.Errorf("failed to unmarshal response: %w", err)
-			continue
-		}
+		slog.DebugContext(ctx, "etherscan request",
+			"action", action,
+			"attempt", attempt+1,
+			"api_key", keyLabel,
+			"duration_ms", float64(duration.Microseconds())/1000,
+		)
 
-		if result.Status != "1" {
-			lastErr = fmt.Errorf("etherscan API error: %s", result.Message)
-			continue
-		}
+		return nil
+	}
 
-		return &result, nil
+	return fmt.Errorf("failed after %d retries: %w", c.maxRetries+1, lastErr)
+}
+
+// attempt sends one request and decodes its body. On failure it returns the metrics
+// reason (transport, status, read or decode) with the error.
+func (c *EtherscanClient) attempt(ctx context.Context, queryParams url.Values, decode func(body []byte) error) (string, error) {
+	reqURL := fmt.Sprintf("%s?%s", c.baseURL, queryParams.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return "transport", fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return "transport", fmt.Errorf("failed to make request: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		return "status", fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(body))
 	}
 
-	return nil, fmt.Errorf("failed after %d retries: %w", c.maxRetries+1, lastErr)
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return "read", fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if err := decode(body); err != nil {
+		return "decode", err
+	}
+	return "", nil
 }
 
 // IdentifyEpisodeEvent identifies an episode event from its first topic (Topics[0])
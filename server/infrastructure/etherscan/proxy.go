@@ -0,0 +1,116 @@
+package etherscan
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// proxyResponse represents a JSON-RPC response relayed by the Etherscan proxy module.
+// When Etherscan itself rejects the request (rate limit, invalid key) it answers with
+// the regular status/message/result envelope instead.
+type proxyResponse struct {
+	Status  string          `json:"status"`
+	Message string          `json:"message"`
+	Result  json.RawMessage `json:"result"`
+	Error   *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// proxy executes a JSON-RPC method through the Etherscan proxy module and returns the hex result
+func (c *EtherscanClient) proxy(queryParams url.Values) (string, error) {
+	queryParams.Set("module", "proxy")
+
+	var hexResult string
+	err := c.execute(queryParams, func(body []byte) error {
+		var result proxyResponse
+		if err := json.Unmarshal(body, &result); err != nil {
+			return fmt.Errorf("failed to unmarshal response: %w", err)
+		}
+		if result.Error != nil {
+			return fmt.Errorf("json-rpc error %d: %s", result.Error.Code, result.Error.Message)
+		}
+		if err := json.Unmarshal(result.Result, &hexResult); err != nil {
+			return fmt.Errorf("failed to unmarshal result: %w", err)
+		}
+		if result.Status == "0" || !strings.HasPrefix(hexResult, "0x") {
+			return fmt.Errorf("etherscan API error: %s", hexResult)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return hexResult, nil
+}
+
+// EthCall executes a read-only contract call (eth_call) against the latest block.
+// data is the hex encoded calldata; the hex encoded return data is returned.
+func (c *EtherscanClient) EthCall(to, data string) (string, error) {
+	queryParams := url.Values{}
+	queryParams.Set("action", "eth_call")
+	queryParams.Set("to", to)
+	queryParams.Set("data", data)
+	queryParams.Set("tag", "latest")
+
+	return c.proxy(queryParams)
+}
+
+// GetBlockNumber returns the number of the most recent block
+func (c *EtherscanClient) GetBlockNumber() (int64, error) {
+	queryParams := url.Values{}
+	queryParams.Set("action", "eth_blockNumber")
+
+	hexResult, err := c.proxy(queryParams)
+	if err != nil {
+		return 0, err
+	}
+
+	blockNumber, err := strconv.ParseInt(strings.TrimPrefix(hexResult, "0x"), 16, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid block number %q: %w", hexResult, err)
+	}
+	return blockNumber, nil
+}
+
+// balanceResponse represents the response for an account balance
+type balanceResponse struct {
+	Status  string `json:"status"`
+	Message string `json:"message"`
+	Result  string `json:"result"`
+}
+
+// GetBalance returns the ether balance of an address in wei
+func (c *EtherscanClient) GetBalance(address string) (*big.Int, error) {
+	queryParams := url.Values{}
+	queryParams.Set("module", "account")
+	queryParams.Set("action", "balance")
+	queryParams.Set("address", address)
+	queryParams.Set("tag", "latest")
+
+	balance := new(big.Int)
+	err := c.execute(queryParams, func(body []byte) error {
+		var result balanceResponse
+		if err := json.Unmarshal(body, &result); err != nil {
+			return fmt.Errorf("failed to unmarshal response: %w", err)
+		}
+		if result.Status != "1" {
+			return fmt.Errorf("etherscan API error: %s", result.Message)
+		}
+		if _, ok := balance.SetString(result.Result, 10); !ok {
+			return fmt.Errorf("invalid balance %q", result.Result)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return balance, nil
+}
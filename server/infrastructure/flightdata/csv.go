@@ -0,0 +1,128 @@
+package flightdata
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"eventsure-server/domain/pricing"
+)
+
+// Required CSV columns. A "flight" column is optional.
+const (
+	columnRoute            = "route"
+	columnCarrier          = "carrier"
+	columnFlight           = "flight"
+	columnScheduledArrival = "scheduled_arrival"
+	columnActualArrival    = "actual_arrival"
+)
+
+// ImportResult is the outcome of a CSV import
+type ImportResult struct {
+	Arrivals []pricing.Arrival
+	// Skipped counts rows without an actual arrival (cancelled or diverted flights),
+	// which FlightOracle cannot resolve
+	Skipped int
+}
+
+// LoadArrivalsCSV reads historical arrivals from a CSV file
+func LoadArrivalsCSV(path string) (*ImportResult, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return ReadArrivalsCSV(f)
+}
+
+// ReadArrivalsCSV reads historical arrivals from CSV with a header row:
+//
+//	route,carrier,flight,scheduled_arrival,actual_arrival
+//	ICN-NRT,KE,KE703,2025-01-15T12:30:00Z,2025-01-15T15:10:00Z
+//
+// Times may be RFC 3339, "YYYY-MM-DD HH:MM:SS" (UTC) or Unix seconds.
+func ReadArrivalsCSV(r io.Reader) (*ImportResult, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("csv is empty")
+		}
+		return nil, fmt.Errorf("failed to read csv header: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range []string{columnRoute, columnCarrier, columnScheduledArrival, columnActualArrival} {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("csv is missing the %s column", name)
+		}
+	}
+
+	field := func(record []string, name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	result := &ImportResult{}
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		if field(record, columnActualArrival) == "" {
+			result.Skipped++
+			continue
+		}
+
+		scheduled, err := parseTime(field(record, columnScheduledArrival))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid %s: %w", line, columnScheduledArrival, err)
+		}
+		actual, err := parseTime(field(record, columnActualArrival))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid %s: %w", line, columnActualArrival, err)
+		}
+
+		result.Arrivals = append(result.Arrivals, pricing.Arrival{
+			Route:            field(record, columnRoute),
+			Carrier:          field(record, columnCarrier),
+			FlightNumber:     field(record, columnFlight),
+			ScheduledArrival: scheduled,
+			ActualArrival:    actual,
+		})
+	}
+
+	return result, nil
+}
+
+// parseTime parses an RFC 3339, "YYYY-MM-DD HH:MM:SS" or Unix seconds timestamp
+func parseTime(s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse("2006-01-02 15:04:05", s); err == nil {
+		return t, nil
+	}
+	if seconds, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.Unix(seconds, 0).UTC(), nil
+	}
+	return time.Time{}, fmt.Errorf("unrecognized time %q", s)
+}
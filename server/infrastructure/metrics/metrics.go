@@ -0,0 +1,162 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// namespace prefixes the names of all metrics of the server
+const namespace = "eventsure"
+
+// registry holds the server's collectors, plus the Go runtime and process collectors
+var registry = prometheus.NewRegistry()
+
+var (
+	httpRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests by method, route template and status code.",
+	}, []string{"method", "route", "status"})
+
+	httpDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency by method, route template and status code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	etherscanDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "etherscan_request_duration_seconds",
+		Help:      "Latency of each Etherscan request attempt, including retries, by action and API key.",
+		Buckets:   []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+	}, []string{"action", "api_key"})
+
+	etherscanErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "etherscan_request_errors_total",
+		Help:      "Failed Etherscan request attempts by action, API key and reason (transport, status, read, decode).",
+	}, []string{"action", "api_key", "reason"})
+
+	supabaseDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "supabase_query_duration_seconds",
+		Help:      "Latency of Supabase queries by table, operation and result (ok, error).",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"table", "operation", "result"})
+
+	indexerLag = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "indexer_lag_blocks",
+		Help:      "Blocks between the latest block and the furthest-behind episode checkpoint at the start of the last indexer sync.",
+	})
+
+	cacheRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "cache_requests_total",
+		Help:      "Cache lookups by cache and result (hit, miss).",
+	}, []string{"cache", "result"})
+
+	rateLimited = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "rate_limited_requests_total",
+		Help:      "Requests rejected by the rate limiter by tier (key, anonymous, browser, auth_failure).",
+	}, []string{"tier"})
+)
+
+func init() {
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		httpRequests,
+		httpDuration,
+		etherscanDuration,
+		etherscanErrors,
+		supabaseDuration,
+		indexerLag,
+		cacheRequests,
+		rateLimited,
+	)
+}
+
+// Handler serves the metrics in the Prometheus text format
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
+
+// otherMethod is the method label of requests with a method outside httpMethods
+const otherMethod = "OTHER"
+
+// httpMethods are the request methods recorded under their own label
+var httpMethods = map[string]bool{
+	http.MethodGet:     true,
+	http.MethodHead:    true,
+	http.MethodPost:    true,
+	http.MethodPut:     true,
+	http.MethodPatch:   true,
+	http.MethodDelete:  true,
+	http.MethodOptions: true,
+}
+
+// ObserveHTTPRequest records a served HTTP request. route is the mux path template,
+// not the request path, so that addresses in paths do not create a series each.
+// Methods outside the standard ones are recorded as OTHER, since clients can send any method.
+func ObserveHTTPRequest(method, route string, status int, duration time.Duration) {
+	if !httpMethods[method] {
+		method = otherMethod
+	}
+	code := strconv.Itoa(status)
+	httpRequests.WithLabelValues(method, route, code).Inc()
+	httpDuration.WithLabelValues(method, route, code).Observe(duration.Seconds())
+}
+
+// ObserveEtherscanAttempt records one Etherscan request attempt. apiKey is the label of
+// the key used (e.g. key1), never the key itself. reason is empty for a successful attempt.
+func ObserveEtherscanAttempt(action, apiKey string, duration time.Duration, reason string) {
+	etherscanDuration.WithLabelValues(action, apiKey).Observe(duration.Seconds())
+	if reason != "" {
+		etherscanErrors.WithLabelValues(action, apiKey, reason).Inc()
+	}
+}
+
+// TimeSupabaseQuery starts timing a Supabase query; call the returned function with the query's error
+//
+// Example:
+//
+//	done := metrics.TimeSupabaseQuery("user_episodes", "select")
+//	_, err := query.ExecuteTo(&rows)
+//	done(err)
+func TimeSupabaseQuery(table, operation string) func(err error) {
+	start := time.Now()
+	return func(err error) {
+		result := "ok"
+		if err != nil {
+			result = "error"
+		}
+		supabaseDuration.WithLabelValues(table, operation, result).Observe(time.Since(start).Seconds())
+	}
+}
+
+// SetIndexerLag records how many blocks the indexer is behind the chain
+func SetIndexerLag(blocks int64) {
+	indexerLag.Set(float64(blocks))
+}
+
+// ObserveCacheLookup records a hit or miss of the named cache
+func ObserveCacheLookup(cache string, hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	cacheRequests.WithLabelValues(cache, result).Inc()
+}
+
+// ObserveRateLimited records a request rejected by the rate limiter of tier
+func ObserveRateLimited(tier string) {
+	rateLimited.WithLabelValues(tier).Inc()
+}
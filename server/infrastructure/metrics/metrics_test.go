@@ -0,0 +1,58 @@
+package metrics
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+// requestCount returns the value of the HTTP request counter of method, route and status 200
+func requestCount(t *testing.T, method, route string) float64 {
+	t.Helper()
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, family := range families {
+		if family.GetName() != namespace+"_http_requests_total" {
+			continue
+		}
+		for _, m := range family.GetMetric() {
+			labels := make(map[string]string)
+			for _, label := range m.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			if labels["method"] == method && labels["route"] == route && labels["status"] == "200" {
+				return m.GetCounter().GetValue()
+			}
+		}
+	}
+	return 0
+}
+
+func TestObserveHTTPRequestNormalizesMethod(t *testing.T) {
+	const route = "/test/methods"
+
+	tests := []struct {
+		method string
+		want   string
+	}{
+		{http.MethodGet, http.MethodGet},
+		{http.MethodPatch, http.MethodPatch},
+		{"PROPFIND", otherMethod},
+		{"get", otherMethod},
+		{"X-RANDOM-1234", otherMethod},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method, func(t *testing.T) {
+			before := requestCount(t, tt.want, route)
+			ObserveHTTPRequest(tt.method, route, http.StatusOK, time.Millisecond)
+			if after := requestCount(t, tt.want, route); after != before+1 {
+				t.Errorf("requests with method %q counted under %q: %v → %v", tt.method, tt.want, before, after)
+			}
+			if tt.method != tt.want && requestCount(t, tt.method, route) != 0 {
+				t.Errorf("method %q got its own series", tt.method)
+			}
+		})
+	}
+}
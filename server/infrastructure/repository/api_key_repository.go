@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"eventsure-server/domain/apikey"
+)
+
+// APIKeyRepository is the in-memory implementation of the apikey Repository
+type APIKeyRepository struct {
+	keys   []*apikey.APIKey
+	nextID int64
+	mu     sync.RWMutex
+}
+
+// NewAPIKeyRepository creates a new APIKeyRepository
+func NewAPIKeyRepository() *APIKeyRepository {
+	return &APIKeyRepository{
+		nextID: 1,
+	}
+}
+
+// Create stores a new API key and assigns its ID
+func (r *APIKeyRepository) Create(_ context.Context, key *apikey.APIKey) (*apikey.APIKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	created := apikey.RestoreAPIKey(r.nextID, key.Name(), key.Prefix(), key.Hash(), key.Scopes(), key.RateLimit(), key.CreatedAt(), key.RevokedAt())
+	r.nextID++
+	r.keys = append(r.keys, created)
+
+	return created, nil
+}
+
+// Update replaces the stored API key with the same ID
+func (r *APIKeyRepository) Update(_ context.Context, key *apikey.APIKey) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, k := range r.keys {
+		if k.ID() == key.ID() {
+			r.keys[i] = key
+			return nil
+		}
+	}
+	return fmt.Errorf("api_key %d not found", key.ID())
+}
+
+// FindByID finds the API key with the ID
+func (r *APIKeyRepository) FindByID(_ context.Context, id int64) (*apikey.APIKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, k := range r.keys {
+		if k.ID() == id {
+			return k, nil
+		}
+	}
+	return nil, nil
+}
+
+// FindByHash finds the API key with the secret hash
+func (r *APIKeyRepository) FindByHash(_ context.Context, hash string) (*apikey.APIKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, k := range r.keys {
+		if k.Hash() == hash {
+			return k, nil
+		}
+	}
+	return nil, nil
+}
+
+// FindAll returns all API keys, oldest first
+func (r *APIKeyRepository) FindAll(_ context.Context) ([]*apikey.APIKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	keys := make([]*apikey.APIKey, len(r.keys))
+	copy(keys, r.keys)
+	return keys, nil
+}
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"errors"
+	"strings"
+	"sync"
+
+	eventsureepisode "eventsure-server/domain/episode"
+)
+
+// EpisodeSummaryRepository is the in-memory implementation of the episode Summary repository
+type EpisodeSummaryRepository struct {
+	summaries map[string]*eventsureepisode.Summary
+	mu        sync.RWMutex
+}
+
+// NewEpisodeSummaryRepository creates a new EpisodeSummaryRepository
+func NewEpisodeSummaryRepository() *EpisodeSummaryRepository {
+	return &EpisodeSummaryRepository{
+		summaries: make(map[string]*eventsureepisode.Summary),
+	}
+}
+
+// FindByAddress finds a summary by episode address
+func (r *EpisodeSummaryRepository) FindByAddress(address string) (*eventsureepisode.Summary, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	summary, exists := r.summaries[strings.ToLower(address)]
+	if !exists {
+		return nil, nil
+	}
+	return summary, nil
+}
+
+// FindAll finds all summaries ordered by creation block
+func (r *EpisodeSummaryRepository) FindAll() ([]*eventsureepisode.Summary, error) {
+	return r.FindByQuery(eventsureepisode.SummaryQuery{})
+}
+
+// FindByQuery finds summaries matching the query filters, sorted and paginated
+func (r *EpisodeSummaryRepository) FindByQuery(query eventsureepisode.SummaryQuery) ([]*eventsureepisode.Summary, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	summaries := make([]*eventsureepisode.Summary, 0, len(r.summaries))
+	for _, s := range r.summaries {
+		summaries = append(summaries, s)
+	}
+	return query.Apply(summaries), nil
+}
+
+// Save saves a summary
+func (r *EpisodeSummaryRepository) Save(summary *eventsureepisode.Summary) error {
+	if summary == nil {
+		return errors.New("summary cannot be nil")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.summaries[strings.ToLower(summary.Address())] = summary
+	return nil
+}
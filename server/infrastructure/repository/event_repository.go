@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"eventsure-server/domain/event"
+)
+
+// EventStore is the in-memory implementation of the event Store
+type EventStore struct {
+	events []*event.Event
+	keys   map[string]bool
+	mu     sync.RWMutex
+}
+
+// NewEventStore creates a new EventStore
+func NewEventStore() *EventStore {
+	return &EventStore{
+		keys: make(map[string]bool),
+	}
+}
+
+// eventKey returns the unique key of an event log
+func eventKey(e *event.Event) string {
+	return strings.ToLower(e.TransactionHash) + ":" + strconv.FormatInt(e.LogIndex, 10)
+}
+
+// Append appends events that are not stored yet and assigns their sequence numbers
+func (s *EventStore) Append(_ context.Context, events []*event.Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, e := range events {
+		key := eventKey(e)
+		if s.keys[key] {
+			continue
+		}
+		s.keys[key] = true
+
+		stored := *e
+		stored.Sequence = int64(len(s.events) + 1)
+		s.events = append(s.events, &stored)
+	}
+	return nil
+}
+
+// FindByEpisode finds all events of an episode in chain order
+func (s *EventStore) FindByEpisode(_ context.Context, episode string) ([]*event.Event, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var events []*event.Event
+	for _, e := range s.events {
+		if strings.EqualFold(e.Episode, episode) {
+			events = append(events, e)
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Before(events[j])
+	})
+	return events, nil
+}
+
+// FindAfterSequence finds up to limit events appended after sequence, in append order
+func (s *EventStore) FindAfterSequence(_ context.Context, sequence int64, limit int) ([]*event.Event, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if sequence < 0 {
+		sequence = 0
+	}
+	if sequence >= int64(len(s.events)) {
+		return nil, nil
+	}
+
+	end := len(s.events)
+	if limit > 0 && int(sequence)+limit < end {
+		end = int(sequence) + limit
+	}
+	return append([]*event.Event(nil), s.events[sequence:end]...), nil
+}
+
+// CheckpointRepository is the in-memory implementation of the indexer CheckpointRepository
+type CheckpointRepository struct {
+	checkpoints map[string]int64
+	mu          sync.RWMutex
+}
+
+// NewCheckpointRepository creates a new CheckpointRepository
+func NewCheckpointRepository() *CheckpointRepository {
+	return &CheckpointRepository{
+		checkpoints: make(map[string]int64),
+	}
+}
+
+// FindCheckpoint returns the last indexed block for name, or 0 if there is none
+func (r *CheckpointRepository) FindCheckpoint(_ context.Context, name string) (int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.checkpoints[name], nil
+}
+
+// SaveCheckpoint saves the last indexed block for name
+func (r *CheckpointRepository) SaveCheckpoint(_ context.Context, name string, block int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.checkpoints[name] = block
+	return nil
+}
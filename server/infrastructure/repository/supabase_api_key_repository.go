@@ -0,0 +1,164 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"eventsure-server/domain/apikey"
+	"eventsure-server/infrastructure/database"
+
+	"github.com/supabase-community/postgrest-go"
+)
+
+// SupabaseAPIKeyRepository handles api_keys table operations using Supabase
+// api_keys table structure:
+// - id (int8, Primary Key, auto-generated)
+// - name (varchar)
+// - prefix (varchar, first characters of the secret)
+// - hash (varchar, unique, hex SHA-256 of the secret)
+// - scopes (varchar, comma-separated)
+// - rate_limit (int4, requests per minute, 0 for the default)
+// - created_at (timestamptz, auto-generated)
+// - revoked_at (timestamptz, nullable)
+type SupabaseAPIKeyRepository struct {
+	supabaseClient *database.SupabaseRESTClient
+}
+
+// NewSupabaseAPIKeyRepository creates a new SupabaseAPIKeyRepository
+func NewSupabaseAPIKeyRepository(client *database.SupabaseRESTClient) *SupabaseAPIKeyRepository {
+	return &SupabaseAPIKeyRepository{
+		supabaseClient: client,
+	}
+}
+
+// apiKeyRow is the api_keys row representation
+type apiKeyRow struct {
+	ID        int64      `json:"id"`
+	Name      string     `json:"name"`
+	Prefix    string     `json:"prefix"`
+	Hash      string     `json:"hash"`
+	Scopes    string     `json:"scopes"`
+	RateLimit int        `json:"rate_limit"`
+	CreatedAt time.Time  `json:"created_at"`
+	RevokedAt *time.Time `json:"revoked_at"`
+}
+
+// newAPIKeyRow is the api_keys insert representation; id and created_at are generated by the database
+type newAPIKeyRow struct {
+	Name      string `json:"name"`
+	Prefix    string `json:"prefix"`
+	Hash      string `json:"hash"`
+	Scopes    string `json:"scopes"`
+	RateLimit int    `json:"rate_limit"`
+}
+
+// apiKeyRevocationRow is the api_keys revocation update representation
+type apiKeyRevocationRow struct {
+	RevokedAt *time.Time `json:"revoked_at"`
+}
+
+// Create inserts a new api_key record
+func (r *SupabaseAPIKeyRepository) Create(ctx context.Context, key *apikey.APIKey) (*apikey.APIKey, error) {
+	var rows []apiKeyRow
+	done := startQuery(ctx, "api_keys", "insert")
+	_, err := r.supabaseClient.Client.From("api_keys").
+		Insert(newAPIKeyRow{
+			Name:      key.Name(),
+			Prefix:    key.Prefix(),
+			Hash:      key.Hash(),
+			Scopes:    apikey.JoinScopes(key.Scopes()),
+			RateLimit: key.RateLimit(),
+		}, false, "", "representation", "").
+		ExecuteTo(&rows)
+	done(len(rows), err)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rows) == 0 {
+		return nil, errors.New("failed to create api_key")
+	}
+
+	return rows[0].toAPIKey(), nil
+}
+
+// Update updates the revocation time of an api_key record
+func (r *SupabaseAPIKeyRepository) Update(ctx context.Context, key *apikey.APIKey) error {
+	var revokedAt *time.Time
+	if key.Revoked() {
+		t := key.RevokedAt()
+		revokedAt = &t
+	}
+
+	var rows []apiKeyRow
+	done := startQuery(ctx, "api_keys", "update")
+	_, err := r.supabaseClient.Client.From("api_keys").
+		Update(apiKeyRevocationRow{RevokedAt: revokedAt}, "representation", "").
+		Eq("id", strconv.FormatInt(key.ID(), 10)).
+		ExecuteTo(&rows)
+	done(len(rows), err)
+	if err != nil {
+		return err
+	}
+
+	if len(rows) == 0 {
+		return fmt.Errorf("api_key %d not found", key.ID())
+	}
+	return nil
+}
+
+// FindByID finds the api_key record with the ID
+func (r *SupabaseAPIKeyRepository) FindByID(ctx context.Context, id int64) (*apikey.APIKey, error) {
+	keys, err := r.findBy(ctx, map[string]string{"id": strconv.FormatInt(id, 10)})
+	if err != nil || len(keys) == 0 {
+		return nil, err
+	}
+	return keys[0], nil
+}
+
+// FindByHash finds the api_key record with the secret hash
+func (r *SupabaseAPIKeyRepository) FindByHash(ctx context.Context, hash string) (*apikey.APIKey, error) {
+	keys, err := r.findBy(ctx, map[string]string{"hash": hash})
+	if err != nil || len(keys) == 0 {
+		return nil, err
+	}
+	return keys[0], nil
+}
+
+// FindAll finds all api_key records
+func (r *SupabaseAPIKeyRepository) FindAll(ctx context.Context) ([]*apikey.APIKey, error) {
+	return r.findBy(ctx, map[string]string{})
+}
+
+// findBy finds all api_keys whose columns equal the values, oldest first
+func (r *SupabaseAPIKeyRepository) findBy(ctx context.Context, values map[string]string) ([]*apikey.APIKey, error) {
+	var rows []apiKeyRow
+	done := startQuery(ctx, "api_keys", "select")
+	_, err := r.supabaseClient.Client.From("api_keys").
+		Select("*", "exact", false).
+		Match(values).
+		Order("id", &postgrest.OrderOpts{Ascending: true}).
+		ExecuteTo(&rows)
+	done(len(rows), err)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]*apikey.APIKey, len(rows))
+	for i, row := range rows {
+		keys[i] = row.toAPIKey()
+	}
+	return keys, nil
+}
+
+// toAPIKey converts the row to the domain aggregate
+func (row apiKeyRow) toAPIKey() *apikey.APIKey {
+	var revokedAt time.Time
+	if row.RevokedAt != nil {
+		revokedAt = *row.RevokedAt
+	}
+	return apikey.RestoreAPIKey(row.ID, row.Name, row.Prefix, row.Hash, apikey.SplitScopes(row.Scopes), row.RateLimit, row.CreatedAt, revokedAt)
+}
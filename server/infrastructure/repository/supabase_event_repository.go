@@ -0,0 +1,236 @@
+package repository
+
+import (
+	"context"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"eventsure-server/domain/event"
+	"eventsure-server/infrastructure/database"
+
+	"github.com/supabase-community/postgrest-go"
+)
+
+// SupabaseEventStore persists decoded episode events in Supabase
+// episode_events table structure:
+// - id (int8, Primary Key, auto-generated) - used as the event sequence
+// - episode (varchar)
+// - name (varchar)
+// - block_number (int8)
+// - log_index (int8)
+// - transaction_hash (varchar), unique together with log_index
+// - timestamp (timestamptz)
+// - member (varchar, nullable)
+// - amount (numeric as text, nullable)
+// - event_occurred (bool, nullable)
+// - final_arrival_time (int8, nullable)
+// - total_payout (numeric as text, nullable)
+// - surplus (numeric as text, nullable)
+type SupabaseEventStore struct {
+	supabaseClient *database.SupabaseRESTClient
+}
+
+// NewSupabaseEventStore creates a new SupabaseEventStore
+func NewSupabaseEventStore(client *database.SupabaseRESTClient) *SupabaseEventStore {
+	return &SupabaseEventStore{
+		supabaseClient: client,
+	}
+}
+
+// eventRow is the episode_events row representation
+type eventRow struct {
+	ID               int64     `json:"id,omitempty"`
+	Episode          string    `json:"episode"`
+	Name             string    `json:"name"`
+	BlockNumber      int64     `json:"block_number"`
+	LogIndex         int64     `json:"log_index"`
+	TransactionHash  string    `json:"transaction_hash"`
+	Timestamp        time.Time `json:"timestamp"`
+	Member           *string   `json:"member"`
+	Amount           *string   `json:"amount"`
+	EventOccurred    *bool     `json:"event_occurred"`
+	FinalArrivalTime *int64    `json:"final_arrival_time"`
+	TotalPayout      *string   `json:"total_payout"`
+	Surplus          *string   `json:"surplus"`
+}
+
+// Append upserts events on (transaction_hash, log_index)
+func (s *SupabaseEventStore) Append(ctx context.Context, events []*event.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	rows := make([]eventRow, len(events))
+	for i, e := range events {
+		rows[i] = toEventRow(e)
+	}
+
+	done := startQuery(ctx, "episode_events", "upsert")
+	_, _, err := s.supabaseClient.Client.From("episode_events").
+		Upsert(rows, "transaction_hash,log_index", "minimal", "").
+		Execute()
+	done(len(rows), err)
+	return err
+}
+
+// FindByEpisode finds all events of an episode in chain order
+func (s *SupabaseEventStore) FindByEpisode(ctx context.Context, episode string) ([]*event.Event, error) {
+	var rows []eventRow
+	done := startQuery(ctx, "episode_events", "select")
+	_, err := s.supabaseClient.Client.From("episode_events").
+		Select("*", "", false).
+		Eq("episode", strings.ToLower(episode)).
+		Order("block_number", &postgrest.OrderOpts{Ascending: true}).
+		Order("log_index", &postgrest.OrderOpts{Ascending: true}).
+		ExecuteTo(&rows)
+	done(len(rows), err)
+	if err != nil {
+		return nil, err
+	}
+	return fromEventRows(rows), nil
+}
+
+// FindAfterSequence finds up to limit events appended after sequence, in append order
+func (s *SupabaseEventStore) FindAfterSequence(ctx context.Context, sequence int64, limit int) ([]*event.Event, error) {
+	var rows []eventRow
+	query := s.supabaseClient.Client.From("episode_events").
+		Select("*", "", false).
+		Gt("id", strconv.FormatInt(sequence, 10)).
+		Order("id", &postgrest.OrderOpts{Ascending: true})
+	if limit > 0 {
+		query = query.Limit(limit, "")
+	}
+	done := startQuery(ctx, "episode_events", "select")
+	_, err := query.ExecuteTo(&rows)
+	done(len(rows), err)
+	if err != nil {
+		return nil, err
+	}
+	return fromEventRows(rows), nil
+}
+
+// SupabaseCheckpointRepository persists indexer checkpoints in Supabase
+// indexer_checkpoints table structure:
+// - name (varchar, Primary Key)
+// - block_number (int8)
+// - updated_at (timestamptz)
+type SupabaseCheckpointRepository struct {
+	supabaseClient *database.SupabaseRESTClient
+}
+
+// NewSupabaseCheckpointRepository creates a new SupabaseCheckpointRepository
+func NewSupabaseCheckpointRepository(client *database.SupabaseRESTClient) *SupabaseCheckpointRepository {
+	return &SupabaseCheckpointRepository{
+		supabaseClient: client,
+	}
+}
+
+// checkpointRow is the indexer_checkpoints row representation
+type checkpointRow struct {
+	Name        string    `json:"name"`
+	BlockNumber int64     `json:"block_number"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// FindCheckpoint returns the last indexed block for name, or 0 if there is none
+func (r *SupabaseCheckpointRepository) FindCheckpoint(ctx context.Context, name string) (int64, error) {
+	var rows []checkpointRow
+	done := startQuery(ctx, "indexer_checkpoints", "select")
+	_, err := r.supabaseClient.Client.From("indexer_checkpoints").
+		Select("*", "", false).
+		Eq("name", name).
+		ExecuteTo(&rows)
+	done(len(rows), err)
+	if err != nil {
+		return 0, err
+	}
+	if len(rows) == 0 {
+		return 0, nil
+	}
+	return rows[0].BlockNumber, nil
+}
+
+// SaveCheckpoint saves the last indexed block for name
+func (r *SupabaseCheckpointRepository) SaveCheckpoint(ctx context.Context, name string, block int64) error {
+	done := startQuery(ctx, "indexer_checkpoints", "upsert")
+	_, _, err := r.supabaseClient.Client.From("indexer_checkpoints").
+		Upsert(checkpointRow{Name: name, BlockNumber: block, UpdatedAt: time.Now().UTC()}, "name", "minimal", "").
+		Execute()
+	done(1, err)
+	return err
+}
+
+// toEventRow converts a domain event to its row representation
+func toEventRow(e *event.Event) eventRow {
+	row := eventRow{
+		Episode:         strings.ToLower(e.Episode),
+		Name:            string(e.Name),
+		BlockNumber:     e.BlockNumber,
+		LogIndex:        e.LogIndex,
+		TransactionHash: strings.ToLower(e.TransactionHash),
+		Timestamp:       e.Timestamp.UTC(),
+	}
+
+	switch e.Name {
+	case event.NameMemberJoined, event.NamePayoutClaimed, event.NameSurplusClaimed:
+		member := e.Member
+		amount := e.Amount.String()
+		row.Member = &member
+		row.Amount = &amount
+	case event.NameEpisodeResolved:
+		eventOccurred := e.EventOccurred
+		finalArrivalTime := int64(e.FinalArrivalTime)
+		row.EventOccurred = &eventOccurred
+		row.FinalArrivalTime = &finalArrivalTime
+	case event.NameEpisodeSettled:
+		totalPayout := e.TotalPayout.String()
+		surplus := e.Surplus.String()
+		row.TotalPayout = &totalPayout
+		row.Surplus = &surplus
+	}
+	return row
+}
+
+// fromEventRows converts rows to domain events
+func fromEventRows(rows []eventRow) []*event.Event {
+	events := make([]*event.Event, len(rows))
+	for i, row := range rows {
+		e := &event.Event{
+			Sequence:        row.ID,
+			Episode:         row.Episode,
+			Name:            event.Name(row.Name),
+			BlockNumber:     row.BlockNumber,
+			LogIndex:        row.LogIndex,
+			TransactionHash: row.TransactionHash,
+			Timestamp:       row.Timestamp,
+			Amount:          parseBigInt(row.Amount),
+			TotalPayout:     parseBigInt(row.TotalPayout),
+			Surplus:         parseBigInt(row.Surplus),
+		}
+		if row.Member != nil {
+			e.Member = *row.Member
+		}
+		if row.EventOccurred != nil {
+			e.EventOccurred = *row.EventOccurred
+		}
+		if row.FinalArrivalTime != nil {
+			e.FinalArrivalTime = uint64(*row.FinalArrivalTime)
+		}
+		events[i] = e
+	}
+	return events
+}
+
+// parseBigInt parses a nullable decimal string; nil and malformed values yield nil
+func parseBigInt(s *string) *big.Int {
+	if s == nil {
+		return nil
+	}
+	value, ok := new(big.Int).SetString(*s, 10)
+	if !ok {
+		return nil
+	}
+	return value
+}
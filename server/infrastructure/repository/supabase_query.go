@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"context"
+
+	"eventsure-server/infrastructure/metrics"
+	"eventsure-server/infrastructure/tracing"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// startQuery starts the span and latency metric of a Supabase query. Call the returned
+// function with the number of rows read or written and the query's error.
+func startQuery(ctx context.Context, table, operation string) func(rows int, err error) {
+	done := metrics.TimeSupabaseQuery(table, operation)
+	_, span := tracing.StartClient(ctx, "supabase "+operation+" "+table,
+		attribute.String("db.system", "supabase"),
+		attribute.String("db.collection.name", table),
+		attribute.String("db.operation.name", operation),
+	)
+	return func(rows int, err error) {
+		done(err)
+		span.SetAttributes(attribute.Int("result.count", rows))
+		tracing.End(span, err)
+	}
+}
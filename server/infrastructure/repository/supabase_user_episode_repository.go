@@ -0,0 +1,203 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"eventsure-server/domain/membership"
+	"eventsure-server/infrastructure/database"
+
+	"github.com/supabase-community/postgrest-go"
+)
+
+// SupabaseUserEpisodeRepository handles user_episodes table operations using Supabase
+// user_episodes table structure:
+// - id (int8, Primary Key, auto-generated)
+// - user (varchar, lowercase)
+// - episode (varchar, lowercase)
+// - progress (varchar, nullable)
+// - created_at (timestamptz, auto-generated)
+// - unique (user, episode)
+type SupabaseUserEpisodeRepository struct {
+	supabaseClient *database.SupabaseRESTClient
+}
+
+// NewSupabaseUserEpisodeRepository creates a new SupabaseUserEpisodeRepository
+func NewSupabaseUserEpisodeRepository(client *database.SupabaseRESTClient) *SupabaseUserEpisodeRepository {
+	return &SupabaseUserEpisodeRepository{
+		supabaseClient: client,
+	}
+}
+
+// userEpisodeRow is the user_episodes row representation
+type userEpisodeRow struct {
+	ID        int64     `json:"id"`
+	User      string    `json:"user"`
+	Episode   string    `json:"episode"`
+	Progress  *string   `json:"progress"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// userEpisodeProgressRow is the user_episodes progress update representation
+type userEpisodeProgressRow struct {
+	Progress *string `json:"progress"`
+}
+
+// newUserEpisodeRow is the user_episodes insert representation;
+// id and created_at are generated by the database
+type newUserEpisodeRow struct {
+	User     string  `json:"user"`
+	Episode  string  `json:"episode"`
+	Progress *string `json:"progress,omitempty"`
+}
+
+// Create inserts a new user_episode record, or returns membership.ErrDuplicate
+// when the unique (user, episode) constraint rejects it
+func (r *SupabaseUserEpisodeRepository) Create(ctx context.Context, userEpisode *membership.UserEpisode) (*membership.UserEpisode, error) {
+	var rows []userEpisodeRow
+	done := startQuery(ctx, "user_episodes", "insert")
+	_, err := r.supabaseClient.Client.From("user_episodes").
+		Insert(newUserEpisodeRow{
+			User:     userEpisode.User(),
+			Episode:  userEpisode.Episode(),
+			Progress: progressColumn(userEpisode.Progress()),
+		}, false, "", "representation", "").
+		ExecuteTo(&rows)
+	done(len(rows), err)
+	if err != nil {
+		// 23505 is the PostgreSQL unique_violation error code
+		if strings.Contains(err.Error(), "23505") {
+			return nil, membership.ErrDuplicate
+		}
+		return nil, err
+	}
+
+	if len(rows) == 0 {
+		return nil, errors.New("failed to create user_episode")
+	}
+
+	return rows[0].toUserEpisode(), nil
+}
+
+// Update updates the progress of a user_episode record
+func (r *SupabaseUserEpisodeRepository) Update(ctx context.Context, userEpisode *membership.UserEpisode) error {
+	var rows []userEpisodeRow
+	done := startQuery(ctx, "user_episodes", "update")
+	_, err := r.supabaseClient.Client.From("user_episodes").
+		Update(userEpisodeProgressRow{
+			Progress: progressColumn(userEpisode.Progress()),
+		}, "representation", "").
+		Eq("id", strconv.FormatInt(userEpisode.ID(), 10)).
+		ExecuteTo(&rows)
+	done(len(rows), err)
+	if err != nil {
+		return err
+	}
+
+	if len(rows) == 0 {
+		return fmt.Errorf("user_episode %d not found", userEpisode.ID())
+	}
+	return nil
+}
+
+// FindByID finds the user_episode record with the ID
+func (r *SupabaseUserEpisodeRepository) FindByID(ctx context.Context, id int64) (*membership.UserEpisode, error) {
+	userEpisodes, err := r.findBy(ctx, map[string]string{"id": strconv.FormatInt(id, 10)})
+	if err != nil || len(userEpisodes) == 0 {
+		return nil, err
+	}
+	return userEpisodes[0], nil
+}
+
+// FindByUserAndEpisode finds the user_episode record of user for episode
+func (r *SupabaseUserEpisodeRepository) FindByUserAndEpisode(ctx context.Context, user, episode string) (*membership.UserEpisode, error) {
+	userEpisodes, err := r.findBy(ctx, map[string]string{
+		"user":    strings.ToLower(user),
+		"episode": strings.ToLower(episode),
+	})
+	if err != nil || len(userEpisodes) == 0 {
+		return nil, err
+	}
+	return userEpisodes[0], nil
+}
+
+// FindByUser finds all user_episodes for a specific user
+func (r *SupabaseUserEpisodeRepository) FindByUser(ctx context.Context, user string) ([]*membership.UserEpisode, error) {
+	return r.findBy(ctx, map[string]string{"user": strings.ToLower(user)})
+}
+
+// FindByEpisode finds all user_episodes for a specific episode
+func (r *SupabaseUserEpisodeRepository) FindByEpisode(ctx context.Context, episode string) ([]*membership.UserEpisode, error) {
+	return r.findBy(ctx, map[string]string{"episode": strings.ToLower(episode)})
+}
+
+// FindWithoutProgress finds all user_episodes whose progress has not been set yet
+func (r *SupabaseUserEpisodeRepository) FindWithoutProgress(ctx context.Context) ([]*membership.UserEpisode, error) {
+	return r.find(ctx, func(query *postgrest.FilterBuilder) *postgrest.FilterBuilder {
+		return query.Is("progress", "null")
+	})
+}
+
+// CountMixedCase counts the user_episodes whose user or episode address has upper case letters.
+// Such rows predate 0004_unique_user_episodes, which lowercases the addresses; lookups match
+// addresses exactly, so they would not be found.
+func (r *SupabaseUserEpisodeRepository) CountMixedCase(ctx context.Context) (int64, error) {
+	var rows []userEpisodeRow
+	done := startQuery(ctx, "user_episodes", "select")
+	count, err := r.supabaseClient.Client.From("user_episodes").
+		Select("id", "exact", false).
+		Or(`user.match.[A-F],episode.match.[A-F]`, "").
+		Limit(1, "").
+		ExecuteTo(&rows)
+	done(len(rows), err)
+	return count, err
+}
+
+// findBy finds all user_episodes whose columns equal the values, oldest first
+func (r *SupabaseUserEpisodeRepository) findBy(ctx context.Context, values map[string]string) ([]*membership.UserEpisode, error) {
+	return r.find(ctx, func(query *postgrest.FilterBuilder) *postgrest.FilterBuilder {
+		return query.Match(values)
+	})
+}
+
+// find finds all user_episodes passing filter, oldest first
+func (r *SupabaseUserEpisodeRepository) find(ctx context.Context, filter func(*postgrest.FilterBuilder) *postgrest.FilterBuilder) ([]*membership.UserEpisode, error) {
+	var rows []userEpisodeRow
+	done := startQuery(ctx, "user_episodes", "select")
+	query := r.supabaseClient.Client.From("user_episodes").Select("*", "exact", false)
+	_, err := filter(query).
+		Order("id", &postgrest.OrderOpts{Ascending: true}).
+		ExecuteTo(&rows)
+	done(len(rows), err)
+	if err != nil {
+		return nil, err
+	}
+
+	userEpisodes := make([]*membership.UserEpisode, len(rows))
+	for i, row := range rows {
+		userEpisodes[i] = row.toUserEpisode()
+	}
+	return userEpisodes, nil
+}
+
+// toUserEpisode converts the row to the domain aggregate
+func (row userEpisodeRow) toUserEpisode() *membership.UserEpisode {
+	var progress membership.Progress
+	if row.Progress != nil {
+		progress = membership.Progress(*row.Progress)
+	}
+	return membership.RestoreUserEpisode(row.ID, row.User, row.Episode, progress, row.CreatedAt)
+}
+
+// progressColumn converts an unset progress to null
+func progressColumn(progress membership.Progress) *string {
+	if progress == "" {
+		return nil
+	}
+	s := string(progress)
+	return &s
+}
@@ -1,75 +1,124 @@
 package repository
 
 import (
-	"eventsure-server/infrastructure/database"
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"eventsure-server/domain/membership"
 )
 
-// UserEpisodeRepository handles user_episodes table operations using Supabase
+// UserEpisodeRepository is the in-memory implementation of the membership Repository
 type UserEpisodeRepository struct {
-	supabaseClient *database.SupabaseRESTClient
+	userEpisodes []*membership.UserEpisode
+	nextID       int64
+	mu           sync.RWMutex
 }
 
 // NewUserEpisodeRepository creates a new UserEpisodeRepository
-func NewUserEpisodeRepository() (*UserEpisodeRepository, error) {
-	client, err := database.NewSupabaseRESTClient()
-	if err != nil {
-		return nil, err
-	}
-
+func NewUserEpisodeRepository() *UserEpisodeRepository {
 	return &UserEpisodeRepository{
-		supabaseClient: client,
-	}, nil
+		nextID: 1,
+	}
 }
 
-// Create inserts a new user_episode record
-// user_episodes table structure:
-// - id (int8, Primary Key, auto-generated)
-// - user (varchar)
-// - episode (varchar)
-// - progress (varchar, nullable)
-// - created_at (timestamptz, auto-generated)
-func (r *UserEpisodeRepository) Create(user, episode string) (map[string]interface{}, error) {
-	data := map[string]interface{}{
-		"user":    user,
-		"episode": episode,
-		// progress는 nullable이므로 생략 가능
-		// id와 created_at은 자동 생성됨
+// Create stores a new user episode and assigns its ID, or returns membership.ErrDuplicate
+// when the user already has one for the episode
+func (r *UserEpisodeRepository) Create(_ context.Context, userEpisode *membership.UserEpisode) (*membership.UserEpisode, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, u := range r.userEpisodes {
+		if u.User() == userEpisode.User() && u.Episode() == userEpisode.Episode() {
+			return nil, membership.ErrDuplicate
+		}
 	}
 
-	result, err := r.supabaseClient.Insert("user_episodes", data)
-	if err != nil {
-		return nil, err
+	created := membership.RestoreUserEpisode(
+		r.nextID,
+		userEpisode.User(),
+		userEpisode.Episode(),
+		userEpisode.Progress(),
+		userEpisode.CreatedAt(),
+	)
+	r.nextID++
+	r.userEpisodes = append(r.userEpisodes, created)
+
+	return created, nil
+}
+
+// Update replaces the stored user episode with the same ID
+func (r *UserEpisodeRepository) Update(_ context.Context, userEpisode *membership.UserEpisode) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, u := range r.userEpisodes {
+		if u.ID() == userEpisode.ID() {
+			r.userEpisodes[i] = userEpisode
+			return nil
+		}
 	}
+	return fmt.Errorf("user episode %d not found", userEpisode.ID())
+}
 
+// FindByID finds the user episode with the ID
+func (r *UserEpisodeRepository) FindByID(_ context.Context, id int64) (*membership.UserEpisode, error) {
+	result := r.findBy(func(u *membership.UserEpisode) bool {
+		return u.ID() == id
+	})
 	if len(result) == 0 {
 		return nil, nil
 	}
-
 	return result[0], nil
 }
 
-// FindByUser finds all user_episodes for a specific user
-func (r *UserEpisodeRepository) FindByUser(user string) ([]map[string]interface{}, error) {
-	var result []map[string]interface{}
-	_, err := r.supabaseClient.Client.From("user_episodes").
-		Select("*", "exact", false).
-		Eq("user", user).
-		ExecuteTo(&result)
-	if err != nil {
-		return nil, err
+// FindByUserAndEpisode finds the user episode of user for episode
+func (r *UserEpisodeRepository) FindByUserAndEpisode(_ context.Context, user, episode string) (*membership.UserEpisode, error) {
+	user, episode = strings.ToLower(user), strings.ToLower(episode)
+	result := r.findBy(func(u *membership.UserEpisode) bool {
+		return u.User() == user && u.Episode() == episode
+	})
+	if len(result) == 0 {
+		return nil, nil
 	}
-	return result, nil
+	return result[0], nil
+}
+
+// FindByUser finds all user episodes for a specific user
+func (r *UserEpisodeRepository) FindByUser(_ context.Context, user string) ([]*membership.UserEpisode, error) {
+	user = strings.ToLower(user)
+	return r.findBy(func(u *membership.UserEpisode) bool {
+		return u.User() == user
+	}), nil
 }
 
-// FindByEpisode finds all user_episodes for a specific episode
-func (r *UserEpisodeRepository) FindByEpisode(episode string) ([]map[string]interface{}, error) {
-	var result []map[string]interface{}
-	_, err := r.supabaseClient.Client.From("user_episodes").
-		Select("*", "exact", false).
-		Eq("episode", episode).
-		ExecuteTo(&result)
-	if err != nil {
-		return nil, err
+// FindByEpisode finds all user episodes for a specific episode
+func (r *UserEpisodeRepository) FindByEpisode(_ context.Context, episode string) ([]*membership.UserEpisode, error) {
+	episode = strings.ToLower(episode)
+	return r.findBy(func(u *membership.UserEpisode) bool {
+		return u.Episode() == episode
+	}), nil
+}
+
+// FindWithoutProgress finds all user episodes whose progress has not been set yet
+func (r *UserEpisodeRepository) FindWithoutProgress(_ context.Context) ([]*membership.UserEpisode, error) {
+	return r.findBy(func(u *membership.UserEpisode) bool {
+		return u.Progress() == ""
+	}), nil
+}
+
+// findBy returns the user episodes matching the predicate in creation order
+func (r *UserEpisodeRepository) findBy(match func(*membership.UserEpisode) bool) []*membership.UserEpisode {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]*membership.UserEpisode, 0)
+	for _, u := range r.userEpisodes {
+		if match(u) {
+			copied := *u
+			result = append(result, &copied)
+		}
 	}
-	return result, nil
+	return result
 }
@@ -0,0 +1,196 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	"eventsure-server/infrastructure/config"
+)
+
+// Worker is a background job that runs next to the HTTP server
+type Worker struct {
+	Name string
+	// Run runs the worker until ctx is cancelled
+	Run func(ctx context.Context)
+	// Flush, if set, is called after Run returned to persist the state the worker holds,
+	// such as checkpoints or queued work
+	Flush func(ctx context.Context) error
+}
+
+// closer releases a resource after the server and the workers stopped
+type closer struct {
+	name  string
+	close func(ctx context.Context) error
+}
+
+// Runner runs the HTTP server and the background workers, and shuts them down
+// when SIGINT or SIGTERM is received:
+//
+//  1. the server stops accepting connections and in-flight requests are drained
+//  2. the workers are cancelled and waited for
+//  3. the workers are flushed
+//  4. the resources registered with OnShutdown are released, last registered first
+//
+// All steps share the shutdown timeout. A second signal exits immediately.
+type Runner struct {
+	server          *http.Server
+	shutdownTimeout time.Duration
+	workers         []Worker
+	closers         []closer
+}
+
+// New creates a Runner that serves handler on the configured port with the configured timeouts
+func New(handler http.Handler, cfg config.Server) *Runner {
+	return &Runner{
+		server: &http.Server{
+			Addr:              ":" + strconv.Itoa(cfg.Port),
+			Handler:           handler,
+			ReadHeaderTimeout: cfg.ReadTimeout,
+			ReadTimeout:       cfg.ReadTimeout,
+			WriteTimeout:      cfg.WriteTimeout,
+			IdleTimeout:       cfg.IdleTimeout,
+			ErrorLog:          slog.NewLogLogger(slog.Default().Handler(), slog.LevelWarn),
+		},
+		shutdownTimeout: cfg.ShutdownTimeout,
+	}
+}
+
+// Go registers a worker started by Run
+func (r *Runner) Go(worker Worker) {
+	r.workers = append(r.workers, worker)
+}
+
+// OnShutdown registers a resource released at the end of the shutdown, such as a database
+// connection or the trace exporter
+func (r *Runner) OnShutdown(name string, close func(ctx context.Context) error) {
+	r.closers = append(r.closers, closer{name: name, close: close})
+}
+
+// Run starts the workers and the HTTP server, and blocks until SIGINT or SIGTERM is received,
+// ctx is cancelled or the server fails. It then shuts down and returns the server error
+// and the errors of the shutdown steps.
+func (r *Runner) Run(ctx context.Context) error {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+
+	// Workers outlive ctx until the requests that may enqueue work for them are drained
+	workerCtx, cancelWorkers := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancelWorkers()
+
+	var wg sync.WaitGroup
+	for _, worker := range r.workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			worker.Run(workerCtx)
+			slog.Info("worker stopped", "worker", worker.Name)
+		}()
+		slog.Info("worker started", "worker", worker.Name)
+	}
+
+	serverErr := make(chan error, 1)
+	go func() {
+		serverErr <- r.server.ListenAndServe()
+	}()
+	slog.Info("server starting", "addr", r.server.Addr,
+		"readTimeout", r.server.ReadTimeout.String(), "writeTimeout", r.server.WriteTimeout.String(), "idleTimeout", r.server.IdleTimeout.String())
+
+	var errs []error
+	serving := true
+	select {
+	case sig := <-signals:
+		slog.Info("shutdown started, send the signal again to exit immediately", "signal", sig.String(), "timeout", r.shutdownTimeout.String())
+	case <-ctx.Done():
+		slog.Info("shutdown started", "reason", ctx.Err().Error(), "timeout", r.shutdownTimeout.String())
+	case err := <-serverErr:
+		serving = false
+		errs = append(errs, errors.New("http server failed: "+err.Error()))
+		slog.Error("http server failed, shutting down", "error", err)
+	}
+	// Restore the default handling, so a second signal terminates the process
+	signal.Stop(signals)
+
+	started := time.Now()
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), r.shutdownTimeout)
+	defer cancel()
+
+	if serving {
+		if err := r.drain(shutdownCtx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	cancelWorkers()
+	if err := r.wait(shutdownCtx, &wg); err != nil {
+		errs = append(errs, err)
+	}
+
+	for _, worker := range r.workers {
+		if worker.Flush == nil {
+			continue
+		}
+		if err := worker.Flush(shutdownCtx); err != nil {
+			slog.Error("failed to flush worker", "worker", worker.Name, "error", err)
+			errs = append(errs, errors.New("failed to flush "+worker.Name+": "+err.Error()))
+			continue
+		}
+		slog.Info("worker flushed", "worker", worker.Name)
+	}
+
+	for i := len(r.closers) - 1; i >= 0; i-- {
+		c := r.closers[i]
+		if err := c.close(shutdownCtx); err != nil {
+			slog.Error("failed to close", "resource", c.name, "error", err)
+			errs = append(errs, errors.New("failed to close "+c.name+": "+err.Error()))
+			continue
+		}
+		slog.Info("closed", "resource", c.name)
+	}
+
+	slog.Info("shutdown complete", "duration", time.Since(started).String())
+	return errors.Join(errs...)
+}
+
+// drain stops accepting connections and waits for in-flight requests, closing the
+// connections that are still active when ctx is done
+func (r *Runner) drain(ctx context.Context) error {
+	started := time.Now()
+	slog.Info("draining in-flight requests")
+	if err := r.server.Shutdown(ctx); err != nil {
+		r.server.Close()
+		slog.Error("failed to drain in-flight requests, closed the remaining connections", "error", err)
+		return errors.New("failed to drain in-flight requests: " + err.Error())
+	}
+	slog.Info("in-flight requests drained", "duration", time.Since(started).String())
+	return nil
+}
+
+// wait waits for the cancelled workers to return until ctx is done
+func (r *Runner) wait(ctx context.Context, wg *sync.WaitGroup) error {
+	if len(r.workers) == 0 {
+		return nil
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	slog.Info("stopping workers", "count", len(r.workers))
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		slog.Error("workers did not stop before the shutdown timeout")
+		return errors.New("workers did not stop: " + ctx.Err().Error())
+	}
+}
@@ -0,0 +1,449 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"eventsure-server/domain/apikey"
+	"eventsure-server/domain/chain"
+	eventsureepisode "eventsure-server/domain/episode"
+	"eventsure-server/domain/event"
+	"eventsure-server/domain/membership"
+	"eventsure-server/infrastructure/config"
+)
+
+// contractBackend opens empty repositories of one storage backend
+type contractBackend struct {
+	name string
+	open func(t *testing.T) *Repositories
+}
+
+// contractBackends are the backends every repository contract test runs against.
+// PostgreSQL is added by postgres_contract_test.go when built with -tags postgres.
+var contractBackends = []contractBackend{
+	{name: BackendMemory, open: func(*testing.T) *Repositories { return openMemory() }},
+	{name: BackendSQLite, open: func(t *testing.T) *Repositories {
+		return openContractStorage(t, sqlitePrefix+filepath.Join(t.TempDir(), "contract.db"))
+	}},
+}
+
+// openContractStorage opens storage and closes it when the test ends
+func openContractStorage(t *testing.T, storage string) *Repositories {
+	t.Helper()
+	repos, err := Open(config.Storage{URL: storage}, config.Supabase{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { repos.Close() })
+	return repos
+}
+
+// runContract runs test against fresh repositories of every backend
+func runContract(t *testing.T, test func(t *testing.T, repos *Repositories)) {
+	for _, backend := range contractBackends {
+		t.Run(backend.name, func(t *testing.T) {
+			test(t, backend.open(t))
+		})
+	}
+}
+
+func mustAddress(t *testing.T, s string) chain.Address {
+	t.Helper()
+	address, err := chain.ParseAddress(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return address
+}
+
+// sameTime reports whether two stored times are equal at the precision every backend keeps
+func sameTime(a, b time.Time) bool {
+	return a.Truncate(time.Millisecond).Equal(b.Truncate(time.Millisecond))
+}
+
+const (
+	alice    = "0xAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA"
+	bob      = "0xbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"
+	episodeA = "0x1111111111111111111111111111111111111111"
+	episodeB = "0x2222222222222222222222222222222222222222"
+)
+
+func TestUserEpisodeRepositoryContract(t *testing.T) {
+	runContract(t, func(t *testing.T, repos *Repositories) {
+		ctx := context.Background()
+		repo := repos.UserEpisodes
+
+		first, err := repo.Create(ctx, membership.NewUserEpisode(mustAddress(t, alice), mustAddress(t, episodeA)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if first.ID() == 0 {
+			t.Fatal("Create did not assign an ID")
+		}
+		if first.User() != mustAddress(t, alice).String() {
+			t.Errorf("user = %s, want the lowercase address", first.User())
+		}
+
+		_, err = repo.Create(ctx, membership.NewUserEpisode(mustAddress(t, alice), mustAddress(t, episodeA)))
+		if !errors.Is(err, membership.ErrDuplicate) {
+			t.Errorf("duplicate Create error = %v, want ErrDuplicate", err)
+		}
+
+		second, err := repo.Create(ctx, membership.NewUserEpisode(mustAddress(t, alice), mustAddress(t, episodeB)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := repo.Create(ctx, membership.NewUserEpisode(mustAddress(t, bob), mustAddress(t, episodeA))); err != nil {
+			t.Fatal(err)
+		}
+		if second.ID() == first.ID() {
+			t.Errorf("IDs are not unique: %d", second.ID())
+		}
+
+		found, err := repo.FindByID(ctx, first.ID())
+		if err != nil || found == nil {
+			t.Fatalf("FindByID = %v, %v", found, err)
+		}
+		if found.Episode() != first.Episode() || !sameTime(found.CreatedAt(), first.CreatedAt()) {
+			t.Errorf("FindByID = %s at %s, want %s at %s", found.Episode(), found.CreatedAt(), first.Episode(), first.CreatedAt())
+		}
+		if missing, err := repo.FindByID(ctx, 1_000_000); err != nil || missing != nil {
+			t.Errorf("FindByID of a missing ID = %v, %v; want nil, nil", missing, err)
+		}
+
+		// Lookups match addresses case-insensitively
+		found, err = repo.FindByUserAndEpisode(ctx, alice, "0x1111111111111111111111111111111111111111")
+		if err != nil || found == nil || found.ID() != first.ID() {
+			t.Errorf("FindByUserAndEpisode = %v, %v; want ID %d", found, err, first.ID())
+		}
+		if missing, err := repo.FindByUserAndEpisode(ctx, bob, episodeB); err != nil || missing != nil {
+			t.Errorf("FindByUserAndEpisode of a missing pair = %v, %v; want nil, nil", missing, err)
+		}
+
+		byUser, err := repo.FindByUser(ctx, alice)
+		if err != nil || len(byUser) != 2 {
+			t.Errorf("FindByUser = %d user episodes, %v; want 2", len(byUser), err)
+		}
+		byEpisode, err := repo.FindByEpisode(ctx, episodeA)
+		if err != nil || len(byEpisode) != 2 {
+			t.Errorf("FindByEpisode = %d user episodes, %v; want 2", len(byEpisode), err)
+		}
+
+		first.SetProgress(membership.ProgressLocked)
+		if err := repo.Update(ctx, first); err != nil {
+			t.Fatal(err)
+		}
+		found, err = repo.FindByID(ctx, first.ID())
+		if err != nil || found.Progress() != membership.ProgressLocked {
+			t.Errorf("progress after Update = %v, %v; want %s", found.Progress(), err, membership.ProgressLocked)
+		}
+		withoutProgress, err := repo.FindWithoutProgress(ctx)
+		if err != nil || len(withoutProgress) != 2 {
+			t.Errorf("FindWithoutProgress = %d user episodes, %v; want 2", len(withoutProgress), err)
+		}
+		for _, u := range withoutProgress {
+			if u.ID() == first.ID() {
+				t.Errorf("FindWithoutProgress returned user episode %d with progress %s", u.ID(), u.Progress())
+			}
+		}
+
+		missing := membership.RestoreUserEpisode(1_000_000, first.User(), first.Episode(), membership.ProgressJoined, time.Now())
+		if err := repo.Update(ctx, missing); err == nil {
+			t.Error("Update of a missing user episode succeeded")
+		}
+	})
+}
+
+func TestEventStoreContract(t *testing.T) {
+	runContract(t, func(t *testing.T, repos *Repositories) {
+		ctx := context.Background()
+		store := repos.Events
+		at := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+		joined := &event.Event{
+			Episode: episodeA, Name: event.NameMemberJoined, BlockNumber: 10, LogIndex: 2,
+			TransactionHash: "0xaa", Timestamp: at, Member: bob, Amount: big.NewInt(1000),
+		}
+		created := &event.Event{
+			Episode: episodeA, Name: event.NameEpisodeCreated, BlockNumber: 10, LogIndex: 1,
+			TransactionHash: "0xaa", Timestamp: at,
+		}
+		settled := &event.Event{
+			Episode: episodeA, Name: event.NameEpisodeSettled, BlockNumber: 20, LogIndex: 0,
+			TransactionHash: "0xbb", Timestamp: at.Add(time.Hour),
+			TotalPayout: new(big.Int).Lsh(big.NewInt(1), 200), Surplus: big.NewInt(0),
+		}
+		resolved := &event.Event{
+			Episode: episodeA, Name: event.NameEpisodeResolved, BlockNumber: 15, LogIndex: 0,
+			TransactionHash: "0xcc", Timestamp: at, EventOccurred: true, FinalArrivalTime: 1_740_830_400,
+		}
+		other := &event.Event{
+			Episode: episodeB, Name: event.NameEpisodeCreated, BlockNumber: 5, LogIndex: 0,
+			TransactionHash: "0xdd", Timestamp: at,
+		}
+
+		// Appended out of chain order, with a duplicate log
+		if err := store.Append(ctx, []*event.Event{joined, settled, created}); err != nil {
+			t.Fatal(err)
+		}
+		if err := store.Append(ctx, []*event.Event{settled, resolved, other}); err != nil {
+			t.Fatal(err)
+		}
+
+		all, err := store.FindAfterSequence(ctx, 0, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		wantNames := []event.Name{event.NameMemberJoined, event.NameEpisodeSettled, event.NameEpisodeCreated, event.NameEpisodeResolved, event.NameEpisodeCreated}
+		if len(all) != len(wantNames) {
+			t.Fatalf("stored %d events, want %d (duplicates must be skipped)", len(all), len(wantNames))
+		}
+		for i, e := range all {
+			if e.Name != wantNames[i] {
+				t.Errorf("event %d = %s, want %s in append order", i, e.Name, wantNames[i])
+			}
+			if i > 0 && e.Sequence <= all[i-1].Sequence {
+				t.Errorf("sequence %d does not increase after %d", e.Sequence, all[i-1].Sequence)
+			}
+		}
+
+		page, err := store.FindAfterSequence(ctx, all[1].Sequence, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(page) != 2 || page[0].Sequence != all[2].Sequence || page[1].Sequence != all[3].Sequence {
+			t.Errorf("FindAfterSequence(%d, 2) returned the wrong page", all[1].Sequence)
+		}
+		if rest, err := store.FindAfterSequence(ctx, all[len(all)-1].Sequence, 10); err != nil || len(rest) != 0 {
+			t.Errorf("FindAfterSequence past the end = %d events, %v; want none", len(rest), err)
+		}
+
+		byEpisode, err := store.FindByEpisode(ctx, episodeA)
+		if err != nil {
+			t.Fatal(err)
+		}
+		wantChain := []event.Name{event.NameEpisodeCreated, event.NameMemberJoined, event.NameEpisodeResolved, event.NameEpisodeSettled}
+		if len(byEpisode) != len(wantChain) {
+			t.Fatalf("FindByEpisode = %d events, want %d", len(byEpisode), len(wantChain))
+		}
+		for i, e := range byEpisode {
+			if e.Name != wantChain[i] {
+				t.Errorf("event %d = %s, want %s in chain order", i, e.Name, wantChain[i])
+			}
+		}
+
+		// Event fields survive the round trip
+		gotJoined, gotResolved, gotSettled := byEpisode[1], byEpisode[2], byEpisode[3]
+		if gotJoined.Member != bob || gotJoined.Amount.Cmp(joined.Amount) != 0 || !gotJoined.Timestamp.Equal(at) {
+			t.Errorf("MemberJoined = %+v", gotJoined)
+		}
+		if !gotResolved.EventOccurred || gotResolved.FinalArrivalTime != resolved.FinalArrivalTime {
+			t.Errorf("EpisodeResolved = %+v", gotResolved)
+		}
+		if gotSettled.TotalPayout.Cmp(settled.TotalPayout) != 0 || gotSettled.Surplus.Sign() != 0 {
+			t.Errorf("EpisodeSettled amounts = %s, %s; want %s, 0", gotSettled.TotalPayout, gotSettled.Surplus, settled.TotalPayout)
+		}
+	})
+}
+
+func TestCheckpointRepositoryContract(t *testing.T) {
+	runContract(t, func(t *testing.T, repos *Repositories) {
+		ctx := context.Background()
+		repo := repos.Checkpoints
+
+		if block, err := repo.FindCheckpoint(ctx, "indexer"); err != nil || block != 0 {
+			t.Errorf("missing checkpoint = %d, %v; want 0", block, err)
+		}
+		for _, block := range []int64{100, 250} {
+			if err := repo.SaveCheckpoint(ctx, "indexer", block); err != nil {
+				t.Fatal(err)
+			}
+			if got, err := repo.FindCheckpoint(ctx, "indexer"); err != nil || got != block {
+				t.Errorf("checkpoint = %d, %v; want %d", got, err, block)
+			}
+		}
+		if block, err := repo.FindCheckpoint(ctx, "progress"); err != nil || block != 0 {
+			t.Errorf("checkpoints are not kept per name: %d, %v", block, err)
+		}
+	})
+}
+
+func TestEpisodeSummaryRepositoryContract(t *testing.T) {
+	runContract(t, func(t *testing.T, repos *Repositories) {
+		repo := repos.Summaries
+		departure := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+		ether := new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil)
+
+		summaries := []struct {
+			address   string
+			flight    string
+			departure time.Time
+			block     int64
+			state     eventsureepisode.State
+			tvl       *big.Int
+		}{
+			{"0x0000000000000000000000000000000000000001", "KE123", departure, 30, eventsureepisode.StateOpen, big.NewInt(5)},
+			{"0x0000000000000000000000000000000000000002", "OZ202", departure.Add(24 * time.Hour), 10, eventsureepisode.StateOpen, new(big.Int).Mul(ether, big.NewInt(3))},
+			{"0x0000000000000000000000000000000000000003", "KE123", departure.Add(48 * time.Hour), 20, eventsureepisode.StateSettled, new(big.Int).Mul(ether, big.NewInt(20))},
+			{"0x0000000000000000000000000000000000000004", "LJ7", departure.Add(24 * time.Hour), 40, eventsureepisode.StateLocked, big.NewInt(5)},
+		}
+		for _, s := range summaries {
+			summary := eventsureepisode.NewSummary(s.address, s.flight, big.NewInt(1000), big.NewInt(5000),
+				s.departure, s.departure.Add(2*time.Hour), s.departure.Add(-72*time.Hour), s.departure.Add(-time.Hour), s.block)
+			summary.UpdateOnChainState(s.state, big.NewInt(2000), s.tvl)
+			if err := repo.Save(summary); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		// Saving again updates the on-chain state in place
+		updated, err := repo.FindByAddress(summaries[0].address)
+		if err != nil || updated == nil {
+			t.Fatalf("FindByAddress = %v, %v", updated, err)
+		}
+		updated.UpdateOnChainState(eventsureepisode.StateLocked, big.NewInt(3000), big.NewInt(5))
+		if err := repo.Save(updated); err != nil {
+			t.Fatal(err)
+		}
+		found, err := repo.FindByAddress(summaries[0].address)
+		if err != nil || found.State() != eventsureepisode.StateLocked || found.MemberCount() != 3 {
+			t.Errorf("summary after update = %v, %v", found, err)
+		}
+		if found.FlightName() != "KE123" || found.PayoutAmount().Cmp(big.NewInt(5000)) != 0 || !found.DepartureTime().Equal(departure) {
+			t.Errorf("terms changed after update: %s %s %s", found.FlightName(), found.PayoutAmount(), found.DepartureTime())
+		}
+		if missing, err := repo.FindByAddress("0x0000000000000000000000000000000000000009"); err != nil || missing != nil {
+			t.Errorf("FindByAddress of a missing summary = %v, %v; want nil, nil", missing, err)
+		}
+
+		addresses := func(summaries []*eventsureepisode.Summary) []string {
+			result := make([]string, len(summaries))
+			for i, s := range summaries {
+				result[i] = s.Address()[len(s.Address())-1:]
+			}
+			return result
+		}
+		day := departure.Add(24 * time.Hour)
+		nextDay := day.Add(24 * time.Hour)
+
+		tests := []struct {
+			name  string
+			query eventsureepisode.SummaryQuery
+			want  []string
+		}{
+			{"all by creation block", eventsureepisode.SummaryQuery{}, []string{"2", "3", "1", "4"}},
+			{"state", eventsureepisode.SummaryQuery{State: eventsureepisode.StateLocked}, []string{"1", "4"}},
+			{"flight name ignores case", eventsureepisode.SummaryQuery{FlightName: "ke123"}, []string{"3", "1"}},
+			{"departure range", eventsureepisode.SummaryQuery{DepartureFrom: &day, DepartureTo: &day}, []string{"2", "4"}},
+			{"departure before is exclusive", eventsureepisode.SummaryQuery{DepartureBefore: &day}, []string{"1"}},
+			{"departure descending", eventsureepisode.SummaryQuery{SortBy: eventsureepisode.SortByDeparture, Descending: true}, []string{"3", "4", "2", "1"}},
+			{"tvl beyond int64 ties broken by address", eventsureepisode.SummaryQuery{SortBy: eventsureepisode.SortByTVL}, []string{"1", "4", "2", "3"}},
+			{"limit", eventsureepisode.SummaryQuery{SortBy: eventsureepisode.SortByTVL, Descending: true, Limit: 2}, []string{"3", "2"}},
+			{
+				"cursor within a tie",
+				eventsureepisode.SummaryQuery{SortBy: eventsureepisode.SortByTVL, After: &eventsureepisode.SummaryCursor{
+					SortKey: big.NewInt(5), Address: "0x0000000000000000000000000000000000000001",
+				}},
+				[]string{"4", "2", "3"},
+			},
+			{
+				"cursor descending",
+				eventsureepisode.SummaryQuery{SortBy: eventsureepisode.SortByDeparture, Descending: true, DepartureBefore: &nextDay, After: &eventsureepisode.SummaryCursor{
+					SortKey: big.NewInt(day.Unix()), Address: "0x0000000000000000000000000000000000000004",
+				}},
+				[]string{"2", "1"},
+			},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				got, err := repo.FindByQuery(tt.query)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if gotAddresses := addresses(got); !equalStrings(gotAddresses, tt.want) {
+					t.Errorf("FindByQuery = %v, want %v", gotAddresses, tt.want)
+				}
+			})
+		}
+	})
+}
+
+func TestAPIKeyRepositoryContract(t *testing.T) {
+	runContract(t, func(t *testing.T, repos *Repositories) {
+		ctx := context.Background()
+		repo := repos.APIKeys
+
+		key, secret, err := apikey.NewAPIKey("partner", []apikey.Scope{apikey.ScopeRead, apikey.ScopeWrite}, 120)
+		if err != nil {
+			t.Fatal(err)
+		}
+		created, err := repo.Create(ctx, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if created.ID() == 0 {
+			t.Fatal("Create did not assign an ID")
+		}
+		other, _, err := apikey.NewAPIKey("admin", []apikey.Scope{apikey.ScopeAdmin}, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := repo.Create(ctx, other); err != nil {
+			t.Fatal(err)
+		}
+
+		found, err := repo.FindByHash(ctx, apikey.HashSecret(secret))
+		if err != nil || found == nil || found.ID() != created.ID() {
+			t.Fatalf("FindByHash = %v, %v; want ID %d", found, err, created.ID())
+		}
+		if found.Name() != "partner" || found.Prefix() != key.Prefix() || found.RateLimit() != 120 ||
+			apikey.JoinScopes(found.Scopes()) != "read,write" || found.Revoked() {
+			t.Errorf("FindByHash = %+v", found)
+		}
+		if missing, err := repo.FindByHash(ctx, apikey.HashSecret("esk_unknown")); err != nil || missing != nil {
+			t.Errorf("FindByHash of an unknown secret = %v, %v; want nil, nil", missing, err)
+		}
+		if missing, err := repo.FindByID(ctx, 1_000_000); err != nil || missing != nil {
+			t.Errorf("FindByID of a missing ID = %v, %v; want nil, nil", missing, err)
+		}
+
+		revokedAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+		found.Revoke(revokedAt)
+		if err := repo.Update(ctx, found); err != nil {
+			t.Fatal(err)
+		}
+		revoked, err := repo.FindByID(ctx, created.ID())
+		if err != nil || !revoked.Revoked() || !revoked.RevokedAt().Equal(revokedAt) {
+			t.Errorf("key after revocation = %v, %v", revoked, err)
+		}
+
+		all, err := repo.FindAll(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(all) != 2 || all[0].Name() != "partner" || all[1].Name() != "admin" {
+			t.Errorf("FindAll returned %d keys, want partner then admin including the revoked key", len(all))
+		}
+
+		missing := apikey.RestoreAPIKey(1_000_000, "gone", "esk_00000000", "hash", []apikey.Scope{apikey.ScopeRead}, 0, time.Now(), time.Time{})
+		if err := repo.Update(ctx, missing); err == nil {
+			t.Error("Update of a missing key succeeded")
+		}
+	})
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
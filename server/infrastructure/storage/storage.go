@@ -0,0 +1,199 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"eventsure-server/domain/apikey"
+	eventsureepisode "eventsure-server/domain/episode"
+	"eventsure-server/domain/event"
+	"eventsure-server/domain/membership"
+	"eventsure-server/infrastructure/config"
+	"eventsure-server/infrastructure/database"
+	"eventsure-server/infrastructure/database/postgres"
+	"eventsure-server/infrastructure/database/sqlite"
+	"eventsure-server/infrastructure/database/sqlstore"
+	"eventsure-server/infrastructure/repository"
+)
+
+const (
+	BackendMemory   = "memory"
+	BackendSupabase = "supabase"
+	BackendPostgres = "postgres"
+	BackendSQLite   = "sqlite"
+)
+
+// supabaseCheckTimeout bounds the user_episodes address check when opening Supabase
+const supabaseCheckTimeout = 10 * time.Second
+
+// sqlitePrefix is the STORAGE prefix selecting the SQLite backend, followed by the database file path
+const sqlitePrefix = "sqlite://"
+
+// Repositories are the repositories of the selected storage backend
+type Repositories struct {
+	Backend      string
+	Events       event.Store
+	Checkpoints  event.CheckpointRepository
+	UserEpisodes membership.Repository
+	Summaries    eventsureepisode.SummaryRepository
+	APIKeys      apikey.Repository
+
+	db *sql.DB
+}
+
+// Close releases the database connection of the backend, if any
+func (r *Repositories) Close() error {
+	if r.db == nil {
+		return nil
+	}
+	return r.db.Close()
+}
+
+// pingCheckpoint is the checkpoint read to check that a backend without a database connection answers
+const pingCheckpoint = "health"
+
+// Ping checks that the backend is reachable
+func (r *Repositories) Ping(ctx context.Context) error {
+	if r.db != nil {
+		return r.db.PingContext(ctx)
+	}
+	_, err := r.Checkpoints.FindCheckpoint(ctx, pingCheckpoint)
+	return err
+}
+
+// IsPostgresURL reports whether storage selects the PostgreSQL backend
+func IsPostgresURL(storage string) bool {
+	return strings.HasPrefix(storage, "postgres://") || strings.HasPrefix(storage, "postgresql://")
+}
+
+// IsSQLiteURL reports whether storage selects the SQLite backend
+func IsSQLiteURL(storage string) bool {
+	return strings.HasPrefix(storage, sqlitePrefix)
+}
+
+// OpenDatabase opens the SQL database selected by storage (postgres://... or sqlite://path)
+// together with its dialect, without applying migrations
+func OpenDatabase(storage string) (*sql.DB, sqlstore.Dialect, error) {
+	switch {
+	case IsPostgresURL(storage):
+		db, err := postgres.Open(storage)
+		return db, sqlstore.Postgres, err
+	case IsSQLiteURL(storage):
+		path := strings.TrimPrefix(storage, sqlitePrefix)
+		if path == "" {
+			return nil, sqlstore.Dialect{}, fmt.Errorf("STORAGE %q is missing the database file path", storage)
+		}
+		db, err := sqlite.Open(path)
+		return db, sqlstore.SQLite, err
+	}
+	return nil, sqlstore.Dialect{}, fmt.Errorf("STORAGE %q is not a postgres:// or sqlite:// URL", storage)
+}
+
+// Open opens the storage backend selected by the STORAGE setting:
+//   - "postgres://..." / "postgresql://...": PostgreSQL, pending migrations are applied
+//   - "sqlite://path": SQLite database file, pending migrations are applied
+//   - "supabase": Supabase REST (episode summaries stay in memory); it is refused while
+//     user_episodes has upper case addresses, see 0004_unique_user_episodes
+//   - "memory": in-memory, nothing is persisted
+//   - "": Supabase when configured, in-memory otherwise
+func Open(cfg config.Storage, supabaseCfg config.Supabase) (*Repositories, error) {
+	storage := cfg.URL
+	switch {
+	case IsPostgresURL(storage), IsSQLiteURL(storage):
+		return openSQL(storage)
+	case storage == BackendSupabase:
+		return openSupabase(supabaseCfg)
+	case storage == BackendMemory:
+		return openMemory(), nil
+	case storage == "":
+		client, err := database.NewSupabaseRESTClient(supabaseCfg)
+		if err != nil {
+			slog.Warn("Supabase is not configured, using in-memory repositories", "error", err)
+			return openMemory(), nil
+		}
+		return supabaseRepositories(client)
+	}
+	return nil, fmt.Errorf("unsupported STORAGE %q (expected postgres://..., sqlite://..., supabase or memory)", storage)
+}
+
+func openSQL(storage string) (*Repositories, error) {
+	db, dialect, err := OpenDatabase(storage)
+	if err != nil {
+		return nil, err
+	}
+
+	migrator, err := sqlstore.NewMigrator(db, dialect)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	applied, err := migrator.Up()
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to apply migrations: %w", err)
+	}
+	for _, m := range applied {
+		slog.Info("applied migration", "version", m.Version, "name", m.Name)
+	}
+
+	return &Repositories{
+		Backend:      dialect.Name,
+		Events:       sqlstore.NewEventStore(db),
+		Checkpoints:  sqlstore.NewCheckpointRepository(db),
+		UserEpisodes: sqlstore.NewUserEpisodeRepository(db),
+		Summaries:    sqlstore.NewEpisodeSummaryRepository(db, dialect),
+		APIKeys:      sqlstore.NewAPIKeyRepository(db),
+		db:           db,
+	}, nil
+}
+
+func openSupabase(cfg config.Supabase) (*Repositories, error) {
+	client, err := database.NewSupabaseRESTClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return supabaseRepositories(client)
+}
+
+// supabaseRepositories creates the Supabase repositories, refusing a user_episodes table with
+// upper case addresses: they would not match lookups, and the unique (user, episode) index would
+// not reject duplicates differing in case. Migration 0004 has to be run first.
+// When Supabase cannot be reached the check is skipped, and readiness reports it as down.
+func supabaseRepositories(client *database.SupabaseRESTClient) (*Repositories, error) {
+	userEpisodes := repository.NewSupabaseUserEpisodeRepository(client)
+
+	ctx, cancel := context.WithTimeout(context.Background(), supabaseCheckTimeout)
+	defer cancel()
+	mixedCase, err := userEpisodes.CountMixedCase(ctx)
+	switch {
+	case err != nil:
+		slog.Warn("failed to check user_episodes addresses", "error", err)
+	case mixedCase > 0:
+		return nil, fmt.Errorf("user_episodes has %d rows with upper case addresses, "+
+			"run 0004_unique_user_episodes.up.sql in the Supabase SQL Editor", mixedCase)
+	}
+
+	return &Repositories{
+		Backend:      BackendSupabase,
+		Events:       repository.NewSupabaseEventStore(client),
+		Checkpoints:  repository.NewSupabaseCheckpointRepository(client),
+		UserEpisodes: userEpisodes,
+		Summaries:    repository.NewEpisodeSummaryRepository(),
+		APIKeys:      repository.NewSupabaseAPIKeyRepository(client),
+	}, nil
+}
+
+func openMemory() *Repositories {
+	return &Repositories{
+		Backend:      BackendMemory,
+		Events:       repository.NewEventStore(),
+		Checkpoints:  repository.NewCheckpointRepository(),
+		UserEpisodes: repository.NewUserEpisodeRepository(),
+		Summaries:    repository.NewEpisodeSummaryRepository(),
+		APIKeys:      repository.NewAPIKeyRepository(),
+	}
+}
@@ -0,0 +1,77 @@
+package storage
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"eventsure-server/infrastructure/config"
+)
+
+// fakePostgREST answers the user_episodes address check with mixedCase matching rows
+func fakePostgREST(t *testing.T, mixedCase int) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/v1/user_episodes" || r.URL.Query().Get("or") != "(user.match.[A-F],episode.match.[A-F])" {
+			t.Errorf("unexpected request %s", r.URL)
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if mixedCase == 0 {
+			w.Header().Set("Content-Range", "*/0")
+			fmt.Fprint(w, "[]")
+			return
+		}
+		w.Header().Set("Content-Range", fmt.Sprintf("0-0/%d", mixedCase))
+		fmt.Fprint(w, `[{"id":1}]`)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestOpenSupabaseChecksAddressCase(t *testing.T) {
+	tests := []struct {
+		name      string
+		storage   string
+		mixedCase int
+		wantErr   string
+	}{
+		{"lowercase", BackendSupabase, 0, ""},
+		{"mixed case", BackendSupabase, 3, "user_episodes has 3 rows with upper case addresses"},
+		{"mixed case with the default backend", "", 3, "user_episodes has 3 rows with upper case addresses"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := fakePostgREST(t, tt.mixedCase)
+			repos, err := Open(config.Storage{URL: tt.storage}, config.Supabase{ProjectURL: server.URL, APIKey: "test"})
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+				if repos.Backend != BackendSupabase {
+					t.Errorf("Backend = %q, want %q", repos.Backend, BackendSupabase)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Open error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestOpenSupabaseStartsWhenUnreachable(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	repos, err := Open(config.Storage{URL: BackendSupabase}, config.Supabase{ProjectURL: server.URL, APIKey: "test"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if repos.Backend != BackendSupabase {
+		t.Errorf("Backend = %q, want %q", repos.Backend, BackendSupabase)
+	}
+}
@@ -0,0 +1,121 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"eventsure-server/infrastructure/config"
+	"eventsure-server/infrastructure/logging"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// serviceName is the default service.name resource attribute, overridden by OTEL_SERVICE_NAME
+	serviceName = "eventsure-server"
+	// instrumentationName names the tracer of the server's own spans
+	instrumentationName = "eventsure-server"
+)
+
+// Exporters selected with OTEL_TRACES_EXPORTER
+const (
+	ExporterOTLP   = "otlp"
+	ExporterStdout = "stdout"
+	ExporterNone   = "none"
+)
+
+// Setup installs the global tracer provider and W3C trace context propagator.
+// The exporter is otlp, stdout or none; it defaults to otlp when an OTLP endpoint is configured
+// and to none otherwise. The OTLP/HTTP exporter reads its headers and timeout from the standard
+// OTEL_EXPORTER_OTLP_* variables, and the sampler from OTEL_TRACES_SAMPLER. The returned function
+// flushes and stops the exporter.
+func Setup(ctx context.Context, cfg config.Tracing) (exporter string, shutdown func(context.Context) error, err error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	exporter = strings.ToLower(cfg.Exporter)
+	if exporter == "" {
+		exporter = ExporterNone
+		if cfg.Endpoint != "" {
+			exporter = ExporterOTLP
+		}
+	}
+
+	noop := func(context.Context) error { return nil }
+
+	var spanExporter sdktrace.SpanExporter
+	switch exporter {
+	case ExporterNone:
+		return exporter, noop, nil
+	case ExporterOTLP:
+		var opts []otlptracehttp.Option
+		if cfg.Endpoint != "" {
+			opts = append(opts, otlptracehttp.WithEndpointURL(strings.TrimSuffix(cfg.Endpoint, "/")+"/v1/traces"))
+		}
+		spanExporter, err = otlptracehttp.New(ctx, opts...)
+	case ExporterStdout:
+		spanExporter, err = stdouttrace.New()
+	default:
+		return ExporterNone, noop, fmt.Errorf("unsupported OTEL_TRACES_EXPORTER %q (expected otlp, stdout or none)", exporter)
+	}
+	if err != nil {
+		return ExporterNone, noop, errors.New("failed to create " + exporter + " trace exporter: " + err.Error())
+	}
+
+	res, err := resource.New(ctx,
+		resource.WithAttributes(attribute.String("service.name", serviceName)),
+		resource.WithFromEnv(),
+		resource.WithTelemetrySDK(),
+	)
+	if err != nil {
+		return ExporterNone, noop, errors.New("failed to create trace resource: " + err.Error())
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(spanExporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(provider)
+	return exporter, provider.Shutdown, nil
+}
+
+// Start starts an internal span named name as a child of the span in ctx
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// StartClient starts a span for an outbound call as a child of the span in ctx
+func StartClient(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...),
+	)
+}
+
+// StartServer starts the span of an incoming request, continuing the trace in its headers
+func StartServer(ctx context.Context, carrier propagation.TextMapCarrier, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	ctx = otel.GetTextMapPropagator().Extract(ctx, carrier)
+	return otel.Tracer(instrumentationName).Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(attrs...),
+	)
+}
+
+// End records err on span, with credentials redacted, and ends it
+func End(span trace.Span, err error) {
+	if err != nil {
+		message := logging.Redact(err.Error())
+		span.RecordError(errors.New(message))
+		span.SetStatus(codes.Error, message)
+	}
+	span.End()
+}
@@ -0,0 +1,51 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"eventsure-server/application/apperr"
+	"eventsure-server/domain/chain"
+)
+
+// parseAddressParam parses a required route or query address parameter.
+// A malformed address is a validation error whose details name the field, value and reason.
+func parseAddressParam(field, value string) (chain.Address, error) {
+	if value == "" {
+		return chain.Address{}, apperr.Validation(field + " parameter is required")
+	}
+
+	address, err := chain.ParseAddress(value)
+	if err != nil {
+		var addressErr *chain.AddressError
+		if !errors.As(err, &addressErr) {
+			return chain.Address{}, err
+		}
+		return chain.Address{}, apperr.New(apperr.ErrValidation, addressErr.Error(), addressErr).WithDetails(map[string]interface{}{
+			"field":  field,
+			"value":  addressErr.Value,
+			"reason": addressErr.Reason,
+		})
+	}
+	return address, nil
+}
+
+// decodeBody decodes the JSON request body into v. Malformed addresses keep
+// their chain.AddressError, bodies over the middleware.BodyLimit limit are too large,
+// and any other decoding failure is a validation error.
+func decodeBody(r *http.Request, v interface{}) error {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		var addressErr *chain.AddressError
+		if errors.As(err, &addressErr) {
+			return addressErr
+		}
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return apperr.TooLarge(fmt.Sprintf("request body must be at most %d bytes", maxBytesErr.Limit))
+		}
+		return apperr.Validation("invalid request body")
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	apikeyusecase "eventsure-server/application/apikey"
+	"eventsure-server/application/apperr"
+	"eventsure-server/interface/http/httperr"
+
+	"github.com/gorilla/mux"
+)
+
+// APIKeyController handles HTTP requests for issuing and revoking partner API keys
+type APIKeyController struct {
+	apiKeyUseCase *apikeyusecase.UseCase
+}
+
+// NewAPIKeyController creates a new APIKeyController
+func NewAPIKeyController(apiKeyUseCase *apikeyusecase.UseCase) *APIKeyController {
+	return &APIKeyController{
+		apiKeyUseCase: apiKeyUseCase,
+	}
+}
+
+// ListAPIKeys handles GET /api/admin/api-keys
+func (c *APIKeyController) ListAPIKeys(w http.ResponseWriter, r *http.Request) {
+	response, err := c.apiKeyUseCase.List(r.Context())
+	if err != nil {
+		httperr.Write(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// IssueAPIKey handles POST /api/admin/api-keys
+// Returns 201 with the secret of the new key, which is not shown again
+func (c *APIKeyController) IssueAPIKey(w http.ResponseWriter, r *http.Request) {
+	var req apikeyusecase.IssueAPIKeyRequest
+	if err := decodeBody(r, &req); err != nil {
+		httperr.Write(w, r, err)
+		return
+	}
+
+	response, err := c.apiKeyUseCase.Issue(r.Context(), req)
+	if err != nil {
+		httperr.Write(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(response)
+}
+
+// RevokeAPIKey handles DELETE /api/admin/api-keys/{id}
+// Revokes the key and returns it; revoked keys stay listed
+func (c *APIKeyController) RevokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		httperr.Write(w, r, apperr.Validation("invalid api key id"))
+		return
+	}
+
+	response, err := c.apiKeyUseCase.Revoke(r.Context(), id)
+	if err != nil {
+		httperr.Write(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
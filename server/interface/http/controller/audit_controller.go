@@ -0,0 +1,61 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+
+	auditusecase "eventsure-server/application/audit"
+	"eventsure-server/interface/http/httperr"
+
+	"github.com/gorilla/mux"
+)
+
+// AuditController handles HTTP requests for episode invariant audits
+type AuditController struct {
+	auditUseCase *auditusecase.UseCase
+}
+
+// NewAuditController creates a new AuditController
+func NewAuditController(auditUseCase *auditusecase.UseCase) *AuditController {
+	return &AuditController{
+		auditUseCase: auditUseCase,
+	}
+}
+
+// GetEpisodeAudit handles GET /api/audit/{episode}
+// Returns the latest audit of the episode made by the background audit, without reading the chain
+func (c *AuditController) GetEpisodeAudit(w http.ResponseWriter, r *http.Request) {
+	episode, err := parseAddressParam("episode", mux.Vars(r)["episode"])
+	if err != nil {
+		httperr.Write(w, r, err)
+		return
+	}
+
+	response, err := c.auditUseCase.LatestAudit(r.Context(), episode)
+	if err != nil {
+		httperr.Write(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// RunEpisodeAudit handles POST /api/admin/audit/{episode}
+// Indexes the episode, replays its events against its on-chain state and returns invariant violations
+func (c *AuditController) RunEpisodeAudit(w http.ResponseWriter, r *http.Request) {
+	episode, err := parseAddressParam("episode", mux.Vars(r)["episode"])
+	if err != nil {
+		httperr.Write(w, r, err)
+		return
+	}
+
+	response, err := c.auditUseCase.AuditEpisode(r.Context(), episode)
+	if err != nil {
+		httperr.Write(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
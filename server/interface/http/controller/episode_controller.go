@@ -1,10 +1,20 @@
 package controller
 
 import (
+	"encoding/csv"
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"net/url"
+	"strconv"
+	"time"
 
+	"eventsure-server/application/apperr"
 	episodeusecase "eventsure-server/application/episode"
+	"eventsure-server/domain/chain"
+	eventsureepisode "eventsure-server/domain/episode"
+	"eventsure-server/domain/membership"
+	"eventsure-server/interface/http/httperr"
 
 	"github.com/gorilla/mux"
 )
@@ -22,43 +32,78 @@ func NewEpisodeController(episodeUseCase *episodeusecase.UseCase) *EpisodeContro
 }
 
 // CreateUserEpisode handles POST /api/user-episodes
+// Returns 201 for a new record and 200 with the existing record when the user already joined the episode
 func (c *EpisodeController) CreateUserEpisode(w http.ResponseWriter, r *http.Request) {
 	var req episodeusecase.CreateUserEpisodeRequest
+	if err := decodeBody(r, &req); err != nil {
+		httperr.Write(w, r, err)
+		return
+	}
 
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+	response, created, err := c.episodeUseCase.CreateUserEpisode(r.Context(), req)
+	if err != nil {
+		httperr.Write(w, r, err)
 		return
 	}
 
-	response, err := c.episodeUseCase.CreateUserEpisode(req)
+	w.Header().Set("Content-Type", "application/json")
+	if created {
+		w.WriteHeader(http.StatusCreated)
+	}
+	json.NewEncoder(w).Encode(response)
+}
+
+// UpdateUserEpisode handles PATCH /api/admin/user-episodes/{id}
+// Corrects the progress of a user episode
+func (c *EpisodeController) UpdateUserEpisode(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
 	if err != nil {
-		if err.Error() == "user is required" || err.Error() == "episode is required" {
-			http.Error(w, err.Error(), http.StatusBadRequest)
-			return
-		}
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		httperr.Write(w, r, apperr.Validation("invalid user episode id"))
+		return
+	}
+
+	var req episodeusecase.UpdateUserEpisodeRequest
+	if err := decodeBody(r, &req); err != nil {
+		httperr.Write(w, r, err)
+		return
+	}
+
+	response, err := c.episodeUseCase.UpdateUserEpisodeProgress(r.Context(), id, req)
+	if err != nil {
+		httperr.Write(w, r, err)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
 	json.NewEncoder(w).Encode(response)
 }
 
-// GetUserEpisodes handles GET /api/user-episodes?user=xxx or GET /api/user-episodes?episode=xxx
+// GetUserEpisodes handles GET /api/user-episodes?user=xxx or GET /api/user-episodes?episode=xxx,
+// optionally filtered by &progress=xxx
 func (c *EpisodeController) GetUserEpisodes(w http.ResponseWriter, r *http.Request) {
 	user := r.URL.Query().Get("user")
 	episode := r.URL.Query().Get("episode")
 
+	var progress membership.Progress
+	if s := r.URL.Query().Get("progress"); s != "" {
+		p, ok := membership.ParseProgress(s)
+		if !ok {
+			httperr.Write(w, r, fmt.Errorf("%w: %s", episodeusecase.ErrInvalidProgress, s))
+			return
+		}
+		progress = p
+	}
+
 	// user 쿼리 파라미터가 있으면 user의 episodes 조회
 	if user != "" {
-		response, err := c.episodeUseCase.GetUserEpisodes(user)
+		userAddress, err := parseAddressParam("user", user)
 		if err != nil {
-			if err.Error() == "user is required" {
-				http.Error(w, err.Error(), http.StatusBadRequest)
-				return
-			}
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			httperr.Write(w, r, err)
+			return
+		}
+		response, err := c.episodeUseCase.GetUserEpisodes(r.Context(), userAddress, progress)
+		if err != nil {
+			httperr.Write(w, r, err)
 			return
 		}
 
@@ -69,13 +114,14 @@ func (c *EpisodeController) GetUserEpisodes(w http.ResponseWriter, r *http.Reque
 
 	// episode 쿼리 파라미터가 있으면 episode의 users 조회
 	if episode != "" {
-		response, err := c.episodeUseCase.GetEpisodeUsers(episode)
+		episodeAddress, err := parseAddressParam("episode", episode)
+		if err != nil {
+			httperr.Write(w, r, err)
+			return
+		}
+		response, err := c.episodeUseCase.GetEpisodeUsers(r.Context(), episodeAddress, progress)
 		if err != nil {
-			if err.Error() == "episode is required" {
-				http.Error(w, err.Error(), http.StatusBadRequest)
-				return
-			}
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			httperr.Write(w, r, err)
 			return
 		}
 
@@ -85,15 +131,80 @@ func (c *EpisodeController) GetUserEpisodes(w http.ResponseWriter, r *http.Reque
 	}
 
 	// 둘 다 없으면 에러
-	http.Error(w, "user or episode query parameter is required", http.StatusBadRequest)
+	httperr.Write(w, r, apperr.Validation("user or episode query parameter is required"))
+}
+
+// GetUserEpisodeDetails handles GET /api/v2/user-episodes?user=xxx&episode=xxx&progress=xxx
+// Returns the matching user episodes with their episode summaries; at least one of user and episode is required
+func (c *EpisodeController) GetUserEpisodeDetails(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	var progress membership.Progress
+	if s := query.Get("progress"); s != "" {
+		p, ok := membership.ParseProgress(s)
+		if !ok {
+			httperr.Write(w, r, fmt.Errorf("%w: %s", episodeusecase.ErrInvalidProgress, s))
+			return
+		}
+		progress = p
+	}
+
+	var user, episode chain.Address
+	for _, p := range []struct {
+		name   string
+		target *chain.Address
+	}{
+		{"user", &user},
+		{"episode", &episode},
+	} {
+		s := query.Get(p.name)
+		if s == "" {
+			continue
+		}
+		address, err := parseAddressParam(p.name, s)
+		if err != nil {
+			httperr.Write(w, r, err)
+			return
+		}
+		*p.target = address
+	}
+
+	response, err := c.episodeUseCase.GetUserEpisodeDetails(r.Context(), user, episode, progress)
+	if err != nil {
+		httperr.Write(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
 }
 
 // GetEpisodes handles GET /api/episodes
-// Returns all episode contract addresses from Etherscan
+// Returns episode summaries filtered by state, category, flight and departure range,
+// sorted by creation block, departure or TVL, with cursor pagination
 func (c *EpisodeController) GetEpisodes(w http.ResponseWriter, r *http.Request) {
-	response, err := c.episodeUseCase.GetAllEpisodes()
+	req, err := parseListEpisodesRequest(r.URL.Query())
+	if err != nil {
+		httperr.Write(w, r, err)
+		return
+	}
+
+	response, err := c.episodeUseCase.ListEpisodes(r.Context(), *req)
+	if err != nil {
+		httperr.Write(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// GetEpisodeAddresses handles GET /api/v1/episodes
+// Returns the address of every episode, newest first, in the v1 response shape
+func (c *EpisodeController) GetEpisodeAddresses(w http.ResponseWriter, r *http.Request) {
+	response, err := c.episodeUseCase.ListEpisodeAddresses(r.Context())
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		httperr.Write(w, r, err)
 		return
 	}
 
@@ -101,20 +212,175 @@ func (c *EpisodeController) GetEpisodes(w http.ResponseWriter, r *http.Request)
 	json.NewEncoder(w).Encode(response)
 }
 
+// parseListEpisodesRequest parses the GET /api/episodes query parameters:
+// state, category, flight, departureFrom, departureTo, sort, order, cursor, limit
+func parseListEpisodesRequest(query url.Values) (*episodeusecase.ListEpisodesRequest, error) {
+	req := &episodeusecase.ListEpisodesRequest{
+		FlightName: query.Get("flight"),
+		Cursor:     query.Get("cursor"),
+		Descending: true,
+	}
+
+	if s := query.Get("state"); s != "" {
+		state, ok := eventsureepisode.ParseState(s)
+		if !ok {
+			return nil, apperr.Validation(fmt.Sprintf("invalid state: %s", s))
+		}
+		req.State = state
+	}
+
+	if s := query.Get("category"); s != "" {
+		category, ok := eventsureepisode.ParseCategory(s)
+		if !ok {
+			return nil, apperr.Validation(fmt.Sprintf("invalid category: %s (expected flightDelay, weather or tripCancel)", s))
+		}
+		req.Category = category
+	}
+
+	if s := query.Get("sort"); s != "" {
+		sortBy, ok := eventsureepisode.ParseSortField(s)
+		if !ok {
+			return nil, apperr.Validation(fmt.Sprintf("invalid sort: %s (expected created, departure or tvl)", s))
+		}
+		req.SortBy = sortBy
+	}
+
+	switch order := query.Get("order"); order {
+	case "", "desc":
+	case "asc":
+		req.Descending = false
+	default:
+		return nil, apperr.Validation(fmt.Sprintf("invalid order: %s (expected asc or desc)", order))
+	}
+
+	if s := query.Get("departureFrom"); s != "" {
+		t, _, err := parseDate(s)
+		if err != nil {
+			return nil, apperr.Validation(fmt.Sprintf("invalid departureFrom: %s (expected RFC 3339 or YYYY-MM-DD)", s))
+		}
+		req.DepartureFrom = &t
+	}
+	if s := query.Get("departureTo"); s != "" {
+		t, dateOnly, err := parseDate(s)
+		if err != nil {
+			return nil, apperr.Validation(fmt.Sprintf("invalid departureTo: %s (expected RFC 3339 or YYYY-MM-DD)", s))
+		}
+		if dateOnly {
+			// A date includes the whole day, up to the next midnight
+			next := t.AddDate(0, 0, 1)
+			req.DepartureBefore = &next
+		} else {
+			req.DepartureTo = &t
+		}
+	}
+
+	if s := query.Get("limit"); s != "" {
+		limit, err := strconv.Atoi(s)
+		if err != nil || limit <= 0 {
+			return nil, apperr.Validation(fmt.Sprintf("invalid limit: %s", s))
+		}
+		req.Limit = limit
+	}
+
+	return req, nil
+}
+
+// parseDate parses an RFC 3339 timestamp or a YYYY-MM-DD date (UTC midnight); dateOnly reports the latter
+func parseDate(s string) (t time.Time, dateOnly bool, err error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, false, nil
+	}
+	t, err = time.Parse("2006-01-02", s)
+	return t, err == nil, err
+}
+
 // GetEpisodeEvents handles GET /api/episodes/{episode}/events
 // Returns all events for a specific episode contract address
 func (c *EpisodeController) GetEpisodeEvents(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	episode := vars["episode"]
+	episode, err := parseAddressParam("episode", mux.Vars(r)["episode"])
+	if err != nil {
+		httperr.Write(w, r, err)
+		return
+	}
+
+	response, err := c.episodeUseCase.GetEpisodeEvents(r.Context(), episode)
+	if err != nil {
+		httperr.Write(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// GetIndexedEpisodeEvents handles GET /api/v2/episodes/{episode}/events
+// Returns the decoded events of an episode from the indexed event store
+func (c *EpisodeController) GetIndexedEpisodeEvents(w http.ResponseWriter, r *http.Request) {
+	episode, err := parseAddressParam("episode", mux.Vars(r)["episode"])
+	if err != nil {
+		httperr.Write(w, r, err)
+		return
+	}
+
+	response, err := c.episodeUseCase.GetIndexedEpisodeEvents(r.Context(), episode)
+	if err != nil {
+		httperr.Write(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// GetEpisodeLedger handles GET /api/episodes/{episode}/ledger?format=json|csv
+// Returns the premiums, payouts and surplus withdrawals of an episode with the running balance,
+// reconciled with the on-chain balance; CSV is sent as a download ending with the reconciliation line
+func (c *EpisodeController) GetEpisodeLedger(w http.ResponseWriter, r *http.Request) {
+	episode, err := parseAddressParam("episode", mux.Vars(r)["episode"])
+	if err != nil {
+		httperr.Write(w, r, err)
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	switch format {
+	case "", "json", "csv":
+	default:
+		httperr.Write(w, r, apperr.Validation(fmt.Sprintf("invalid format: %s (expected json or csv)", format)))
+		return
+	}
+
+	response, err := c.episodeUseCase.GetEpisodeLedger(r.Context(), episode)
+	if err != nil {
+		httperr.Write(w, r, err)
+		return
+	}
 
-	if episode == "" {
-		http.Error(w, "episode parameter is required", http.StatusBadRequest)
+	if format != "csv" {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="ledger-`+response.Episode+`.csv"`)
+	cw := csv.NewWriter(w)
+	cw.Write(episodeusecase.LedgerCSVHeader)
+	cw.WriteAll(response.CSVRecords())
+}
+
+// GetUserPortfolio handles GET /api/users/{address}/portfolio
+// Returns the episodes a wallet joined, what it paid and what it can currently claim
+func (c *EpisodeController) GetUserPortfolio(w http.ResponseWriter, r *http.Request) {
+	address, err := parseAddressParam("address", mux.Vars(r)["address"])
+	if err != nil {
+		httperr.Write(w, r, err)
 		return
 	}
 
-	response, err := c.episodeUseCase.GetEpisodeEvents(episode)
+	response, err := c.episodeUseCase.GetUserPortfolio(r.Context(), address)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		httperr.Write(w, r, err)
 		return
 	}
 
@@ -0,0 +1,42 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+
+	healthusecase "eventsure-server/application/health"
+)
+
+// HealthController handles HTTP requests for readiness and dependency health
+type HealthController struct {
+	healthUseCase *healthusecase.UseCase
+}
+
+// NewHealthController creates a new HealthController
+func NewHealthController(healthUseCase *healthusecase.UseCase) *HealthController {
+	return &HealthController{
+		healthUseCase: healthUseCase,
+	}
+}
+
+// GetReady handles GET /ready
+// Returns 503 when a critical component (configuration, storage, Etherscan) is down
+func (c *HealthController) GetReady(w http.ResponseWriter, r *http.Request) {
+	writeHealth(w, c.healthUseCase.Ready(r.Context()))
+}
+
+// GetHealthDetails handles GET /health/details
+// Returns the status and details of every component, and 503 when a critical one is down
+func (c *HealthController) GetHealthDetails(w http.ResponseWriter, r *http.Request) {
+	writeHealth(w, c.healthUseCase.Details(r.Context()))
+}
+
+// writeHealth writes response with 200 when the server is ready and 503 otherwise
+func writeHealth(w http.ResponseWriter, response *healthusecase.HealthResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	if !response.Ready() {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,40 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+
+	pricingusecase "eventsure-server/application/pricing"
+	"eventsure-server/interface/http/httperr"
+)
+
+// PricingController handles HTTP requests for premium pricing
+type PricingController struct {
+	pricingUseCase *pricingusecase.UseCase
+}
+
+// NewPricingController creates a new PricingController
+func NewPricingController(pricingUseCase *pricingusecase.UseCase) *PricingController {
+	return &PricingController{
+		pricingUseCase: pricingUseCase,
+	}
+}
+
+// Quote handles POST /api/pricing/quote
+// Returns a recommended premium/payout pair from the historical delay rate
+func (c *PricingController) Quote(w http.ResponseWriter, r *http.Request) {
+	var req pricingusecase.QuoteRequest
+	if err := decodeBody(r, &req); err != nil {
+		httperr.Write(w, r, err)
+		return
+	}
+
+	response, err := c.pricingUseCase.Quote(req)
+	if err != nil {
+		httperr.Write(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
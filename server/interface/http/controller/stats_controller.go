@@ -0,0 +1,46 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"eventsure-server/application/apperr"
+	statsusecase "eventsure-server/application/stats"
+	domainstats "eventsure-server/domain/stats"
+	"eventsure-server/interface/http/httperr"
+)
+
+// StatsController handles HTTP requests for protocol statistics
+type StatsController struct {
+	statsUseCase *statsusecase.UseCase
+}
+
+// NewStatsController creates a new StatsController
+func NewStatsController(statsUseCase *statsusecase.UseCase) *StatsController {
+	return &StatsController{
+		statsUseCase: statsUseCase,
+	}
+}
+
+// GetStats handles GET /api/stats?interval=daily|weekly
+// Returns aggregated metrics across all episodes built from the indexed event store
+func (c *StatsController) GetStats(w http.ResponseWriter, r *http.Request) {
+	interval := domainstats.IntervalDaily
+	if s := r.URL.Query().Get("interval"); s != "" {
+		parsed, ok := domainstats.ParseInterval(s)
+		if !ok {
+			httperr.Write(w, r, apperr.Validation("invalid interval: "+s+" (expected daily or weekly)"))
+			return
+		}
+		interval = parsed
+	}
+
+	response, err := c.statsUseCase.GetStats(r.Context(), interval)
+	if err != nil {
+		httperr.Write(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,139 @@
+package httperr
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"eventsure-server/application/apperr"
+	"eventsure-server/domain/chain"
+	"eventsure-server/infrastructure/logging"
+)
+
+// RequestIDHeader carries the request ID set by middleware.RequestID
+const RequestIDHeader = "X-Request-ID"
+
+// Error codes returned in the code field of the envelope
+const (
+	CodeValidation          = "validation_error"
+	CodeNotFound            = "not_found"
+	CodeMethodNotAllowed    = "method_not_allowed"
+	CodeConflict            = "conflict"
+	CodeUnauthorized        = "unauthorized"
+	CodeForbidden           = "forbidden"
+	CodeRateLimited         = "rate_limited"
+	CodePayloadTooLarge     = "payload_too_large"
+	CodeUpstreamUnavailable = "upstream_unavailable"
+	CodeInternal            = "internal_error"
+)
+
+// Envelope is the JSON body of every error response
+type Envelope struct {
+	Code      string                 `json:"code"`
+	Message   string                 `json:"message"`
+	Details   map[string]interface{} `json:"details,omitempty"`
+	RequestID string                 `json:"requestId,omitempty"`
+}
+
+// kinds maps the apperr kinds to their code and HTTP status
+var kinds = []struct {
+	kind   error
+	code   string
+	status int
+}{
+	{apperr.ErrValidation, CodeValidation, http.StatusBadRequest},
+	{apperr.ErrNotFound, CodeNotFound, http.StatusNotFound},
+	{apperr.ErrConflict, CodeConflict, http.StatusConflict},
+	{apperr.ErrUnauthorized, CodeUnauthorized, http.StatusUnauthorized},
+	{apperr.ErrForbidden, CodeForbidden, http.StatusForbidden},
+	{apperr.ErrTooLarge, CodePayloadTooLarge, http.StatusRequestEntityTooLarge},
+	{apperr.ErrRateLimited, CodeRateLimited, http.StatusTooManyRequests},
+	{apperr.ErrUnavailable, CodeUpstreamUnavailable, http.StatusServiceUnavailable},
+}
+
+// Write maps err to a status code and writes the error envelope.
+// Only the messages of application errors reach the client; causes and
+// unexpected errors are logged with secrets redacted and reported as internal errors.
+func Write(w http.ResponseWriter, r *http.Request, err error) {
+	requestID := w.Header().Get(RequestIDHeader)
+	envelope, status := toEnvelope(err)
+	envelope.RequestID = requestID
+
+	if status >= http.StatusInternalServerError {
+		slog.ErrorContext(r.Context(), "request failed",
+			"method", r.Method,
+			"path", r.URL.Path,
+			"code", envelope.Code,
+			"error", logging.Redact(err.Error()),
+		)
+	}
+
+	WriteEnvelope(w, status, envelope)
+}
+
+// WriteEnvelope writes envelope with status, filling in the request ID if it is missing
+func WriteEnvelope(w http.ResponseWriter, status int, envelope Envelope) {
+	if envelope.RequestID == "" {
+		envelope.RequestID = w.Header().Get(RequestIDHeader)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(envelope)
+}
+
+// toEnvelope builds the envelope and status for err
+func toEnvelope(err error) (Envelope, int) {
+	var appErr *apperr.Error
+	if errors.As(err, &appErr) {
+		for _, k := range kinds {
+			if errors.Is(appErr.Kind, k.kind) {
+				// Wrapping with fmt.Errorf("%w: ...") adds client-facing detail,
+				// while a cause may come from an upstream and is never shown
+				message := appErr.Message
+				if appErr.Cause == nil {
+					message = err.Error()
+				}
+				return Envelope{
+					Code:    k.code,
+					Message: logging.Redact(message),
+					Details: appErr.Details,
+				}, k.status
+			}
+		}
+	}
+
+	var addressErr *chain.AddressError
+	if errors.As(err, &addressErr) {
+		return Envelope{
+			Code:    CodeValidation,
+			Message: addressErr.Error(),
+			Details: map[string]interface{}{
+				"value":  addressErr.Value,
+				"reason": addressErr.Reason,
+			},
+		}, http.StatusBadRequest
+	}
+
+	return Envelope{
+		Code:    CodeInternal,
+		Message: "internal server error",
+	}, http.StatusInternalServerError
+}
+
+// NotFound handles requests that match no route
+func NotFound(w http.ResponseWriter, r *http.Request) {
+	WriteEnvelope(w, http.StatusNotFound, Envelope{
+		Code:    CodeNotFound,
+		Message: "no route for " + r.URL.Path,
+	})
+}
+
+// MethodNotAllowed handles requests to a known route with an unsupported method
+func MethodNotAllowed(w http.ResponseWriter, r *http.Request) {
+	WriteEnvelope(w, http.StatusMethodNotAllowed, Envelope{
+		Code:    CodeMethodNotAllowed,
+		Message: "method " + r.Method + " is not allowed for " + r.URL.Path,
+	})
+}
@@ -0,0 +1,148 @@
+package httperr
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"eventsure-server/application/apperr"
+	"eventsure-server/domain/chain"
+	"eventsure-server/infrastructure/logging"
+)
+
+// write runs Write for err and returns the response and the decoded envelope
+func write(t *testing.T, err error) (*httptest.ResponseRecorder, Envelope) {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, "/api/episodes", nil)
+	rec := httptest.NewRecorder()
+	rec.Header().Set(RequestIDHeader, "req-1")
+	Write(rec, req, err)
+
+	var envelope Envelope
+	if err := json.Unmarshal(rec.Body.Bytes(), &envelope); err != nil {
+		t.Fatalf("response is not an envelope: %v (%s)", err, rec.Body)
+	}
+	return rec, envelope
+}
+
+func TestWriteKinds(t *testing.T) {
+	tests := []struct {
+		err    error
+		status int
+		code   string
+	}{
+		{apperr.Validation("bad request"), http.StatusBadRequest, CodeValidation},
+		{apperr.NotFound("episode not found"), http.StatusNotFound, CodeNotFound},
+		{apperr.Conflict("already exists"), http.StatusConflict, CodeConflict},
+		{apperr.Unauthorized("missing API key"), http.StatusUnauthorized, CodeUnauthorized},
+		{apperr.Forbidden("admin scope required"), http.StatusForbidden, CodeForbidden},
+		{apperr.TooLarge("body too large"), http.StatusRequestEntityTooLarge, CodePayloadTooLarge},
+		{apperr.RateLimited("too many requests"), http.StatusTooManyRequests, CodeRateLimited},
+		{apperr.Unavailable("etherscan failed", nil), http.StatusServiceUnavailable, CodeUpstreamUnavailable},
+		{&chain.AddressError{Value: "0x12", Reason: "too short"}, http.StatusBadRequest, CodeValidation},
+		{errors.New("boom"), http.StatusInternalServerError, CodeInternal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			rec, envelope := write(t, tt.err)
+			if rec.Code != tt.status || envelope.Code != tt.code {
+				t.Errorf("Write(%v) = %d %s, want %d %s", tt.err, rec.Code, envelope.Code, tt.status, tt.code)
+			}
+			if envelope.RequestID != "req-1" {
+				t.Errorf("requestId = %q, want req-1", envelope.RequestID)
+			}
+			if got := rec.Header().Get("Content-Type"); got != "application/json" {
+				t.Errorf("Content-Type = %q, want application/json", got)
+			}
+		})
+	}
+}
+
+func TestWriteWrappedKind(t *testing.T) {
+	err := fmt.Errorf("%w: premium must be positive", apperr.Validation("invalid quote"))
+	rec, envelope := write(t, err)
+	if rec.Code != http.StatusBadRequest || envelope.Message != err.Error() {
+		t.Errorf("Write(%v) = %d %q, want 400 with the wrapped message", err, rec.Code, envelope.Message)
+	}
+}
+
+func TestWriteInternalHidesCause(t *testing.T) {
+	cause := errors.New(`pq: relation "episode_summaries" does not exist`)
+	rec, envelope := write(t, fmt.Errorf("failed to list episodes: %w", cause))
+
+	if rec.Code != http.StatusInternalServerError || envelope.Message != "internal server error" {
+		t.Errorf("Write = %d %q, want 500 with a generic message", rec.Code, envelope.Message)
+	}
+	if body := rec.Body.String(); strings.Contains(body, "episode_summaries") || strings.Contains(body, "failed to list") {
+		t.Errorf("response leaks the cause: %s", body)
+	}
+}
+
+func TestWriteNeverLeaksSecrets(t *testing.T) {
+	const (
+		etherscanKey = "ETHERSCANKEY1234567890"
+		apiKey       = "evs_live_0123456789abcdef"
+		supabaseKey  = "eyJhbGciOiJIUzI1NiJ9.secret"
+	)
+	logging.AddSecrets(apiKey)
+
+	var logs bytes.Buffer
+	defaultLogger := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(&logs, nil)))
+	defer slog.SetDefault(defaultLogger)
+
+	etherscanURL := "https://api.etherscan.io/api?module=proxy&action=eth_call&apikey=" + etherscanKey
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{"upstream cause", apperr.Unavailable("etherscan request failed", errors.New("Get "+etherscanURL+": timeout"))},
+		{"internal cause", fmt.Errorf("eth_call: Get %s: %w", etherscanURL, errors.New("EOF"))},
+		{"wrapped message", fmt.Errorf("%w: rejected key %s", apperr.Unauthorized("invalid API key"), apiKey)},
+		{"authorization header", fmt.Errorf("%w: apikey: %s", apperr.Forbidden("supabase refused the request"), supabaseKey)},
+		{"query parameter in message", apperr.Validation("bad callback " + etherscanURL)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logs.Reset()
+			rec, _ := write(t, tt.err)
+			for _, secret := range []string{etherscanKey, apiKey, supabaseKey} {
+				if strings.Contains(rec.Body.String(), secret) {
+					t.Errorf("response body contains %s: %s", secret, rec.Body)
+				}
+				if strings.Contains(logs.String(), secret) {
+					t.Errorf("log contains %s: %s", secret, logs.String())
+				}
+			}
+		})
+	}
+}
+
+func TestAddressErrorDetails(t *testing.T) {
+	_, err := chain.ParseAddress("0x12")
+	rec, envelope := write(t, fmt.Errorf("user: %w", err))
+	if rec.Code != http.StatusBadRequest || envelope.Details["value"] != "0x12" || envelope.Details["reason"] == nil {
+		t.Errorf("Write = %d %+v, want 400 with value and reason details", rec.Code, envelope)
+	}
+}
+
+func TestNotFoundAndMethodNotAllowed(t *testing.T) {
+	req := httptest.NewRequest(http.MethodDelete, "/api/nothing", nil)
+	rec := httptest.NewRecorder()
+	NotFound(rec, req)
+	if rec.Code != http.StatusNotFound || !strings.Contains(rec.Body.String(), CodeNotFound) {
+		t.Errorf("NotFound = %d %s", rec.Code, rec.Body)
+	}
+
+	rec = httptest.NewRecorder()
+	MethodNotAllowed(rec, req)
+	if rec.Code != http.StatusMethodNotAllowed || !strings.Contains(rec.Body.String(), "DELETE") {
+		t.Errorf("MethodNotAllowed = %d %s", rec.Code, rec.Body)
+	}
+}
@@ -0,0 +1,105 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	apikeyusecase "eventsure-server/application/apikey"
+	"eventsure-server/application/apperr"
+	domainapikey "eventsure-server/domain/apikey"
+	"eventsure-server/interface/http/httperr"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// APIKeyHeader is the request header carrying an API key, as an alternative to Authorization: Bearer
+const APIKeyHeader = "X-API-Key"
+
+// principalContextKey is the context key of the authenticated principal
+type principalContextKey struct{}
+
+// PrincipalFrom returns the principal the request was authenticated as, or nil for an anonymous request
+func PrincipalFrom(ctx context.Context) *apikeyusecase.Principal {
+	principal, _ := ctx.Value(principalContextKey{}).(*apikeyusecase.Principal)
+	return principal
+}
+
+// Authenticate authenticates requests carrying an API key in the Authorization: Bearer or
+// X-API-Key header and adds the principal to the request context. Requests without a key
+// pass through anonymously. An unknown or revoked key is rejected with 401, and a key
+// without the scope of the method, read for GET and HEAD and write otherwise, with 403.
+func Authenticate(apiKeys *apikeyusecase.UseCase) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			secret, ok := apiKeyFromRequest(r)
+			if !ok {
+				writeAuthError(w, r, apperr.Unauthorized("Authorization must use the Bearer scheme"))
+				return
+			}
+			if secret == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			principal, err := apiKeys.Authenticate(r.Context(), secret)
+			if err != nil {
+				writeAuthError(w, r, err)
+				return
+			}
+			trace.SpanFromContext(r.Context()).SetAttributes(attribute.Int64("api_key.id", principal.KeyID))
+
+			scope := domainapikey.ScopeWrite
+			if r.Method == http.MethodGet || r.Method == http.MethodHead || r.Method == http.MethodOptions {
+				scope = domainapikey.ScopeRead
+			}
+			if !principal.HasScope(scope) {
+				httperr.Write(w, r, apperr.Forbidden("API key does not have the "+string(scope)+" scope"))
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), principalContextKey{}, principal)))
+		})
+	}
+}
+
+// RequireScope rejects requests without an API key with 401, and those whose key lacks scope with 403
+func RequireScope(scope domainapikey.Scope) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal := PrincipalFrom(r.Context())
+			if principal == nil {
+				writeAuthError(w, r, apperr.Unauthorized("an API key is required"))
+				return
+			}
+			if !principal.HasScope(scope) {
+				httperr.Write(w, r, apperr.Forbidden("API key does not have the "+string(scope)+" scope"))
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// apiKeyFromRequest returns the API key of the request, empty when it has none.
+// ok is false when the Authorization header uses a scheme other than Bearer.
+func apiKeyFromRequest(r *http.Request) (secret string, ok bool) {
+	if authorization := r.Header.Get("Authorization"); authorization != "" {
+		scheme, token, found := strings.Cut(authorization, " ")
+		if !found || !strings.EqualFold(scheme, "Bearer") || strings.TrimSpace(token) == "" {
+			return "", false
+		}
+		return strings.TrimSpace(token), true
+	}
+	return strings.TrimSpace(r.Header.Get(APIKeyHeader)), true
+}
+
+// writeAuthError writes the error response of a failed authentication; a 401 asks for a Bearer API key
+func writeAuthError(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, apperr.ErrUnauthorized) {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="eventsure"`)
+	}
+	httperr.Write(w, r, err)
+}
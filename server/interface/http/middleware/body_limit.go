@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+
+	"eventsure-server/application/apperr"
+	"eventsure-server/interface/http/httperr"
+)
+
+// BodyLimit rejects request bodies larger than limit bytes with 413 Payload Too Large.
+// A larger Content-Length is rejected before the handler runs; bodies without one are cut
+// off at the limit, and reading past it fails with *http.MaxBytesError.
+func BodyLimit(limit int64) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.ContentLength > limit {
+				httperr.Write(w, r, apperr.TooLarge(fmt.Sprintf("request body must be at most %d bytes", limit)))
+				return
+			}
+			r.Body = http.MaxBytesReader(w, r.Body, limit)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
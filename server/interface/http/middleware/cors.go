@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"eventsure-server/infrastructure/config"
+	"eventsure-server/interface/http/httperr"
+
+	"github.com/rs/cors"
+)
+
+// exposedHeaders are the response headers browser clients may read
+var exposedHeaders = []string{
+	httperr.RequestIDHeader,
+	"Deprecation",
+	"Sunset",
+	"Link",
+	"RateLimit-Limit",
+	"RateLimit-Remaining",
+	"RateLimit-Reset",
+	"RateLimit-Policy",
+	"Retry-After",
+	"Idempotent-Replayed",
+}
+
+// NewCORS creates the CORS handler for the configured origins, methods and headers.
+// Preflight requests are answered before routing, so it wraps the whole router.
+func NewCORS(cfg config.CORS) *cors.Cors {
+	return cors.New(cors.Options{
+		AllowedOrigins:   cfg.AllowedOrigins,
+		AllowedMethods:   cfg.AllowedMethods,
+		AllowedHeaders:   cfg.AllowedHeaders,
+		ExposedHeaders:   exposedHeaders,
+		AllowCredentials: cfg.AllowCredentials,
+		MaxAge:           int(cfg.MaxAge.Seconds()),
+	})
+}
@@ -0,0 +1,121 @@
+package middleware
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// defaultSunsetPeriod is how long after deprecation a version is removed when its sunset is not configured
+const defaultSunsetPeriod = 180 * 24 * time.Hour
+
+// RouteUsage is the use of one route of a deprecated version
+type RouteUsage struct {
+	Route         string    `json:"route"`
+	Requests      int64     `json:"requests"`
+	LastRequestAt time.Time `json:"lastRequestAt"`
+}
+
+// VersionUsage describes a deprecated API version and how much it is still used since the server started
+type VersionUsage struct {
+	Version       string       `json:"version"`
+	DeprecatedAt  string       `json:"deprecatedAt"`
+	Sunset        string       `json:"sunset"`
+	Successor     string       `json:"successor"`
+	Requests      int64        `json:"requests"`
+	LastRequestAt *time.Time   `json:"lastRequestAt,omitempty"`
+	Routes        []RouteUsage `json:"routes"`
+}
+
+// Deprecation marks the responses of a deprecated API version with the
+// Deprecation (RFC 9745), Sunset (RFC 8594) and successor-version Link headers,
+// and counts its requests per route so the last clients can be found before the sunset.
+type Deprecation struct {
+	version      string
+	deprecatedAt time.Time
+	sunset       time.Time
+	successor    string
+	usage        map[string]*RouteUsage
+	mu           sync.Mutex
+}
+
+// NewDeprecation creates a new Deprecation for version, deprecated at deprecatedAt in favour of
+// the API at successor and removed at sunset. A zero sunset defaults to 180 days after deprecatedAt.
+func NewDeprecation(version string, deprecatedAt, sunset time.Time, successor string) *Deprecation {
+	if sunset.IsZero() {
+		sunset = deprecatedAt.Add(defaultSunsetPeriod)
+	}
+
+	return &Deprecation{
+		version:      version,
+		deprecatedAt: deprecatedAt,
+		sunset:       sunset,
+		successor:    successor,
+		usage:        make(map[string]*RouteUsage),
+	}
+}
+
+// Middleware sets the deprecation headers and counts the request under its route template
+func (d *Deprecation) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Deprecation", "@"+strconv.FormatInt(d.deprecatedAt.Unix(), 10))
+		w.Header().Set("Sunset", d.sunset.UTC().Format(http.TimeFormat))
+		w.Header().Add("Link", "<"+d.successor+">; rel=\"successor-version\"")
+
+		route := r.URL.Path
+		if current := mux.CurrentRoute(r); current != nil {
+			if template, err := current.GetPathTemplate(); err == nil {
+				route = template
+			}
+		}
+		d.count(r.Method + " " + route)
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (d *Deprecation) count(route string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	u, ok := d.usage[route]
+	if !ok {
+		u = &RouteUsage{Route: route}
+		d.usage[route] = u
+	}
+	u.Requests++
+	u.LastRequestAt = time.Now().UTC()
+}
+
+// Usage returns the version's deprecation dates and its use per route, most used first
+func (d *Deprecation) Usage() VersionUsage {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	usage := VersionUsage{
+		Version:      d.version,
+		DeprecatedAt: d.deprecatedAt.UTC().Format(time.RFC3339),
+		Sunset:       d.sunset.UTC().Format(time.RFC3339),
+		Successor:    d.successor,
+		Routes:       make([]RouteUsage, 0, len(d.usage)),
+	}
+	for _, u := range d.usage {
+		usage.Routes = append(usage.Routes, *u)
+		usage.Requests += u.Requests
+		if usage.LastRequestAt == nil || u.LastRequestAt.After(*usage.LastRequestAt) {
+			last := u.LastRequestAt
+			usage.LastRequestAt = &last
+		}
+	}
+	sort.Slice(usage.Routes, func(i, j int) bool {
+		if usage.Routes[i].Requests != usage.Routes[j].Requests {
+			return usage.Routes[i].Requests > usage.Routes[j].Requests
+		}
+		return usage.Routes[i].Route < usage.Routes[j].Route
+	})
+	return usage
+}
@@ -0,0 +1,246 @@
+package middleware
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"eventsure-server/application/apperr"
+	"eventsure-server/interface/http/httperr"
+)
+
+const (
+	// IdempotencyKeyHeader is the request header identifying retries of the same POST
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// maxIdempotencyKeyLength bounds the keys kept in memory
+	maxIdempotencyKeyLength = 255
+	// defaultIdempotencyWindow is how long responses are replayed when IDEMPOTENCY_WINDOW is not set
+	defaultIdempotencyWindow = 24 * time.Hour
+	// defaultIdempotencyMaxEntries is how many responses are kept when IDEMPOTENCY_MAX_ENTRIES is not set
+	defaultIdempotencyMaxEntries = 10000
+	// idempotencySweepInterval is how often Run drops the expired responses
+	idempotencySweepInterval = time.Minute
+)
+
+// storedResponse is the response recorded for an idempotency key
+type storedResponse struct {
+	scope       string
+	fingerprint [sha256.Size]byte
+	status      int
+	contentType string
+	body        []byte
+	expiresAt   time.Time
+}
+
+// Idempotency replays the stored response when a POST is retried with the same
+// Idempotency-Key header within the replay window (IDEMPOTENCY_WINDOW, default 24h).
+// Reusing a key for a different request body is rejected with 400, and a retry while
+// the first request is still running with 409. Server errors are not stored, so they can be retried.
+// Keys sent with an API key are scoped to that key, so partners cannot replay each other's responses.
+//
+// Responses are kept in memory, per instance: behind a load balancer a retry reaching another
+// instance is processed again. At most IDEMPOTENCY_MAX_ENTRIES responses are kept, evicting the
+// least recently used, and Run drops the expired ones in the background.
+type Idempotency struct {
+	window     time.Duration
+	maxEntries int
+	// responses indexes the elements of recent, which holds the stored responses
+	// from the most to the least recently used
+	responses map[string]*list.Element
+	recent    *list.List
+	// pending holds the request body fingerprints of the keys whose first request is running
+	pending map[string][sha256.Size]byte
+	mu      sync.Mutex
+}
+
+// NewIdempotency creates a new Idempotency middleware replaying responses for window and keeping
+// at most maxEntries of them, using the defaults for values that are not positive
+func NewIdempotency(window time.Duration, maxEntries int) *Idempotency {
+	if window <= 0 {
+		window = defaultIdempotencyWindow
+	}
+	if maxEntries <= 0 {
+		maxEntries = defaultIdempotencyMaxEntries
+	}
+
+	return &Idempotency{
+		window:     window,
+		maxEntries: maxEntries,
+		responses:  make(map[string]*list.Element),
+		recent:     list.New(),
+		pending:    make(map[string][sha256.Size]byte),
+	}
+}
+
+// Run drops the expired responses every minute until ctx is cancelled
+func (i *Idempotency) Run(ctx context.Context) {
+	ticker := time.NewTicker(idempotencySweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			i.sweep(time.Now())
+		}
+	}
+}
+
+// sweep drops the responses expired at now
+func (i *Idempotency) sweep(now time.Time) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	for e := i.recent.Front(); e != nil; {
+		next := e.Next()
+		if now.After(e.Value.(*storedResponse).expiresAt) {
+			i.remove(e)
+		}
+		e = next
+	}
+}
+
+// lookup returns the unexpired response stored for scope, marking it as the most recently used.
+// i.mu must be held.
+func (i *Idempotency) lookup(scope string, now time.Time) (*storedResponse, bool) {
+	e, ok := i.responses[scope]
+	if !ok {
+		return nil, false
+	}
+	stored := e.Value.(*storedResponse)
+	if now.After(stored.expiresAt) {
+		i.remove(e)
+		return nil, false
+	}
+	i.recent.MoveToFront(e)
+	return stored, true
+}
+
+// store stores a response, evicting the least recently used ones beyond maxEntries.
+// i.mu must be held.
+func (i *Idempotency) store(stored *storedResponse) {
+	if e, ok := i.responses[stored.scope]; ok {
+		i.remove(e)
+	}
+	i.responses[stored.scope] = i.recent.PushFront(stored)
+	for i.recent.Len() > i.maxEntries {
+		i.remove(i.recent.Back())
+	}
+}
+
+// remove drops a stored response. i.mu must be held.
+func (i *Idempotency) remove(e *list.Element) {
+	delete(i.responses, e.Value.(*storedResponse).scope)
+	i.recent.Remove(e)
+}
+
+// recorder captures the response written by the handler while passing it through
+type recorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (rec *recorder) WriteHeader(code int) {
+	rec.status = code
+	rec.ResponseWriter.WriteHeader(code)
+}
+
+func (rec *recorder) Write(b []byte) (int, error) {
+	rec.body.Write(b)
+	return rec.ResponseWriter.Write(b)
+}
+
+// Middleware applies the Idempotency-Key contract to POST requests
+func (i *Idempotency) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(IdempotencyKeyHeader)
+		if key == "" || r.Method != http.MethodPost {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			httperr.Write(w, r, apperr.Validation("Idempotency-Key must be at most 255 characters"))
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				httperr.Write(w, r, apperr.TooLarge(fmt.Sprintf("request body must be at most %d bytes", maxBytesErr.Limit)))
+				return
+			}
+			httperr.Write(w, r, apperr.Validation("invalid request body"))
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		fingerprint := sha256.Sum256(body)
+		scope := r.Method + " " + r.URL.Path + " " + key
+		if principal := PrincipalFrom(r.Context()); principal != nil {
+			scope = strconv.FormatInt(principal.KeyID, 10) + " " + scope
+		}
+
+		i.mu.Lock()
+		if pending, ok := i.pending[scope]; ok {
+			i.mu.Unlock()
+			if pending != fingerprint {
+				httperr.Write(w, r, apperr.Validation("Idempotency-Key was already used for a different request"))
+				return
+			}
+			httperr.Write(w, r, apperr.Conflict("a request with this Idempotency-Key is in progress"))
+			return
+		}
+		if stored, ok := i.lookup(scope, time.Now()); ok {
+			i.mu.Unlock()
+			if stored.fingerprint != fingerprint {
+				httperr.Write(w, r, apperr.Validation("Idempotency-Key was already used for a different request"))
+				return
+			}
+			if stored.contentType != "" {
+				w.Header().Set("Content-Type", stored.contentType)
+			}
+			w.Header().Set("Idempotent-Replayed", "true")
+			w.WriteHeader(stored.status)
+			w.Write(stored.body)
+			return
+		}
+		i.pending[scope] = fingerprint
+		i.mu.Unlock()
+
+		// The scope is released even when the handler panics, so the key can be retried
+		var response *storedResponse
+		defer func() {
+			i.mu.Lock()
+			defer i.mu.Unlock()
+			delete(i.pending, scope)
+			if response != nil {
+				i.store(response)
+			}
+		}()
+
+		rec := &recorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+
+		if rec.status >= http.StatusInternalServerError {
+			return
+		}
+		response = &storedResponse{
+			scope:       scope,
+			fingerprint: fingerprint,
+			status:      rec.status,
+			contentType: rec.Header().Get("Content-Type"),
+			body:        rec.body.Bytes(),
+			expiresAt:   time.Now().Add(i.window),
+		}
+	})
+}
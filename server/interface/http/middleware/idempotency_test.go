@@ -0,0 +1,121 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// idempotentPost sends a POST with an Idempotency-Key through handler and returns the response
+func idempotentPost(handler http.Handler, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/api/pricing/quote", strings.NewReader(body))
+	req.Header.Set(IdempotencyKeyHeader, key)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestIdempotencyEvictsLeastRecentlyUsed(t *testing.T) {
+	calls := 0
+	idempotency := NewIdempotency(time.Hour, 2)
+	handler := idempotency.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	idempotentPost(handler, "a", "{}")
+	idempotentPost(handler, "b", "{}")
+	// Replaying a makes b the least recently used
+	if rec := idempotentPost(handler, "a", "{}"); rec.Header().Get("Idempotent-Replayed") != "true" {
+		t.Fatal("retry of a was not replayed")
+	}
+	idempotentPost(handler, "c", "{}")
+	if calls != 3 {
+		t.Fatalf("handler called %d times, want 3", calls)
+	}
+
+	tests := []struct {
+		key      string
+		replayed bool
+	}{
+		{"a", true},
+		{"c", true},
+		{"b", false},
+	}
+	for _, tt := range tests {
+		rec := idempotentPost(handler, tt.key, "{}")
+		if replayed := rec.Header().Get("Idempotent-Replayed") == "true"; replayed != tt.replayed {
+			t.Errorf("retry of %s replayed = %v, want %v", tt.key, replayed, tt.replayed)
+		}
+	}
+}
+
+func TestIdempotencySweepDropsExpiredResponses(t *testing.T) {
+	idempotency := NewIdempotency(time.Minute, 10)
+	handler := idempotency.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	}))
+	idempotentPost(handler, "a", "{}")
+	idempotentPost(handler, "b", "{}")
+
+	idempotency.sweep(time.Now())
+	if n := len(idempotency.responses); n != 2 {
+		t.Fatalf("%d responses kept before expiry, want 2", n)
+	}
+	idempotency.sweep(time.Now().Add(2 * time.Minute))
+	if n, m := len(idempotency.responses), idempotency.recent.Len(); n != 0 || m != 0 {
+		t.Fatalf("%d responses (%d listed) kept after expiry, want none", n, m)
+	}
+}
+
+func TestIdempotencyRejectsKeyReuseWhileRunning(t *testing.T) {
+	idempotency := NewIdempotency(time.Hour, 10)
+	var nested *httptest.ResponseRecorder
+	var handler http.Handler
+	handler = idempotency.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if nested == nil {
+			nested = idempotentPost(handler, "a", `{"other":true}`)
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	idempotentPost(handler, "a", "{}")
+	if nested.Code != http.StatusBadRequest {
+		t.Errorf("reuse for another body while running returned %d, want 400", nested.Code)
+	}
+	if rec := idempotentPost(handler, "a", `{"other":true}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("reuse for another body returned %d, want 400", rec.Code)
+	}
+}
+
+func TestIdempotencyReleasesKeyWhenHandlerPanics(t *testing.T) {
+	idempotency := NewIdempotency(time.Hour, 10)
+	panics := true
+	handler := idempotency.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if panics {
+			panic("handler failed")
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("handler panic was not propagated")
+			}
+		}()
+		idempotentPost(handler, "a", "{}")
+	}()
+	if n := len(idempotency.pending); n != 0 {
+		t.Fatalf("%d requests still pending after the panic, want none", n)
+	}
+
+	panics = false
+	rec := idempotentPost(handler, "a", "{}")
+	if rec.Code != http.StatusCreated || rec.Header().Get("Idempotent-Replayed") == "true" {
+		t.Errorf("retry after the panic = %d (replayed %q), want a fresh %d",
+			rec.Code, rec.Header().Get("Idempotent-Replayed"), http.StatusCreated)
+	}
+}
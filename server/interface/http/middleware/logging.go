@@ -1,9 +1,11 @@
 package middleware
 
 import (
-	"log"
+	"log/slog"
 	"net/http"
 	"time"
+
+	"eventsure-server/infrastructure/logging"
 )
 
 // responseWriter is a wrapper around http.ResponseWriter that captures the status code
@@ -17,7 +19,8 @@ func (rw *responseWriter) WriteHeader(code int) {
 	rw.ResponseWriter.WriteHeader(code)
 }
 
-// LoggingMiddleware logs HTTP requests with method, path, status code, and duration
+// LoggingMiddleware logs HTTP requests with method, URI, status code and duration.
+// The record carries the request ID, and credentials in the query string are redacted.
 func LoggingMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		start := time.Now()
@@ -29,13 +32,11 @@ func LoggingMiddleware(next http.Handler) http.Handler {
 
 		next.ServeHTTP(wrapped, r)
 
-		duration := time.Since(start)
-		log.Printf(
-			"[%s] %s [%d] (%v)",
-			r.Method,
-			r.RequestURI,
-			wrapped.statusCode,
-			duration,
+		slog.InfoContext(r.Context(), "request",
+			"method", r.Method,
+			"uri", logging.Redact(r.RequestURI),
+			"status", wrapped.statusCode,
+			"duration_ms", float64(time.Since(start).Microseconds())/1000,
 		)
 	})
 }
@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"eventsure-server/infrastructure/metrics"
+
+	"github.com/gorilla/mux"
+)
+
+// unmatchedRoute is the route label of requests that matched no route
+const unmatchedRoute = "unmatched"
+
+// Metrics records the count and latency of requests per method, route template and status code
+func Metrics(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+
+		wrapped := &responseWriter{
+			ResponseWriter: w,
+			statusCode:     http.StatusOK,
+		}
+
+		next.ServeHTTP(wrapped, r)
+
+		route := unmatchedRoute
+		if current := mux.CurrentRoute(r); current != nil {
+			if template, err := current.GetPathTemplate(); err == nil {
+				route = template
+			}
+		}
+		metrics.ObserveHTTPRequest(r.Method, route, wrapped.statusCode, time.Since(start))
+	})
+}
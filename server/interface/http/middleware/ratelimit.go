@@ -0,0 +1,213 @@
+package middleware
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"eventsure-server/application/apperr"
+	"eventsure-server/infrastructure/config"
+	"eventsure-server/infrastructure/metrics"
+	"eventsure-server/interface/http/httperr"
+)
+
+// rateLimitWindow is the window of the per-minute limits; a bucket refills completely within it
+const rateLimitWindow = time.Minute
+
+// Rate limit tiers, used as the metrics label
+const (
+	tierKey       = "key"
+	tierAnonymous = "anonymous"
+	tierBrowser   = "browser"
+	// tierAuthFailure counts the failed authentications of a client IP
+	tierAuthFailure = "auth_failure"
+)
+
+// bucket is the token bucket of one client
+type bucket struct {
+	tokens  float64
+	limit   int
+	updated time.Time
+}
+
+// RateLimiter limits the requests of each client with a token bucket holding a minute of
+// requests and refilling continuously. Requests with an API key are limited per key, at the
+// key's own limit or RATE_LIMIT_KEY_PER_MINUTE; anonymous requests are limited per client IP,
+// browser requests (those with an Origin or Sec-Fetch-Mode header) at the lower
+// RATE_LIMIT_BROWSER_PER_MINUTE. Responses carry the RateLimit-* headers, and rejected
+// requests get 429 with Retry-After. Buckets are kept in memory, per instance.
+type RateLimiter struct {
+	cfg       config.RateLimit
+	buckets   map[string]*bucket
+	lastSweep time.Time
+	mu        sync.Mutex
+}
+
+// NewRateLimiter creates a new RateLimiter with the limits of cfg
+func NewRateLimiter(cfg config.RateLimit) *RateLimiter {
+	return &RateLimiter{
+		cfg:       cfg,
+		buckets:   make(map[string]*bucket),
+		lastSweep: time.Now(),
+	}
+}
+
+// LimitAuthFailures limits the failed authentications of each client IP, so API keys cannot be
+// guessed faster than RATE_LIMIT_AUTH_FAILURES_PER_MINUTE. It must run before Authenticate: every
+// request with an API key answered with 401 takes a token from the IP's bucket, and once it is
+// empty requests with an API key are rejected with 429 without looking the key up.
+// Requests without an API key are not affected.
+func (l *RateLimiter) LimitAuthFailures(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") == "" && r.Header.Get(APIKeyHeader) == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		key := tierAuthFailure + ":" + clientIP(r, l.cfg.TrustProxy)
+		limit := l.cfg.AuthFailuresPerMinute
+		if retryAfter, exhausted := l.exhausted(key, limit, time.Now()); exhausted {
+			metrics.ObserveRateLimited(tierAuthFailure)
+			w.Header().Set("Retry-After", strconv.Itoa(seconds(retryAfter)))
+			httperr.Write(w, r, apperr.RateLimited(fmt.Sprintf("more than %d failed authentications per minute", limit)))
+			return
+		}
+
+		wrapped := &responseWriter{
+			ResponseWriter: w,
+			statusCode:     http.StatusOK,
+		}
+		next.ServeHTTP(wrapped, r)
+		if wrapped.statusCode == http.StatusUnauthorized {
+			l.take(key, limit, time.Now())
+		}
+	})
+}
+
+// Middleware applies the rate limits; it must run after Authenticate to limit per API key
+func (l *RateLimiter) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tier, client, limit := l.classify(r)
+		allowed, remaining, reset, retryAfter := l.take(tier+":"+client, limit, time.Now())
+
+		h := w.Header()
+		h.Set("RateLimit-Limit", strconv.Itoa(limit))
+		h.Set("RateLimit-Remaining", strconv.Itoa(remaining))
+		h.Set("RateLimit-Reset", strconv.Itoa(seconds(reset)))
+		h.Set("RateLimit-Policy", fmt.Sprintf("%d;w=%d", limit, int(rateLimitWindow.Seconds())))
+
+		if !allowed {
+			metrics.ObserveRateLimited(tier)
+			h.Set("Retry-After", strconv.Itoa(seconds(retryAfter)))
+			httperr.Write(w, r, apperr.RateLimited(fmt.Sprintf("rate limit of %d requests per minute exceeded", limit)))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// classify returns the tier of the request, the client it is limited as and its limit
+func (l *RateLimiter) classify(r *http.Request) (tier, client string, limit int) {
+	if principal := PrincipalFrom(r.Context()); principal != nil {
+		limit = principal.RateLimit
+		if limit <= 0 {
+			limit = l.cfg.KeyPerMinute
+		}
+		return tierKey, strconv.FormatInt(principal.KeyID, 10), limit
+	}
+
+	ip := clientIP(r, l.cfg.TrustProxy)
+	if r.Header.Get("Origin") != "" || r.Header.Get("Sec-Fetch-Mode") != "" {
+		return tierBrowser, ip, l.cfg.BrowserPerMinute
+	}
+	return tierAnonymous, ip, l.cfg.AnonymousPerMinute
+}
+
+// take takes a token from the bucket of key, refilled up to limit at now. It returns whether
+// a token was taken, the whole tokens left, the time until the bucket is full again and,
+// when none was taken, the time until the next token.
+func (l *RateLimiter) take(key string, limit int, now time.Time) (allowed bool, remaining int, reset, retryAfter time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b, rate := l.refill(key, limit, now)
+	if b.tokens >= 1 {
+		b.tokens--
+		allowed = true
+	} else {
+		retryAfter = time.Duration((1 - b.tokens) / rate * float64(time.Second))
+	}
+	remaining = int(b.tokens)
+	reset = time.Duration((float64(limit) - b.tokens) / rate * float64(time.Second))
+	return allowed, remaining, reset, retryAfter
+}
+
+// exhausted reports whether the bucket of key, refilled up to limit at now, has no whole token
+// left, and if so the time until the next token. No token is taken.
+func (l *RateLimiter) exhausted(key string, limit int, now time.Time) (retryAfter time.Duration, exhausted bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b, rate := l.refill(key, limit, now)
+	if b.tokens >= 1 {
+		return 0, false
+	}
+	return time.Duration((1 - b.tokens) / rate * float64(time.Second)), true
+}
+
+// refill returns the bucket of key refilled up to limit at now, and its refill rate per second.
+// l.mu must be held.
+func (l *RateLimiter) refill(key string, limit int, now time.Time) (*bucket, float64) {
+	if now.Sub(l.lastSweep) >= rateLimitWindow {
+		l.sweep(now)
+	}
+
+	rate := float64(limit) / rateLimitWindow.Seconds()
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit), limit: limit, updated: now}
+		l.buckets[key] = b
+	}
+	b.tokens = math.Min(float64(limit), b.tokens+now.Sub(b.updated).Seconds()*rate)
+	b.limit = limit
+	b.updated = now
+	return b, rate
+}
+
+// sweep drops the buckets that have been idle long enough to be full again
+func (l *RateLimiter) sweep(now time.Time) {
+	for key, b := range l.buckets {
+		if now.Sub(b.updated) >= rateLimitWindow {
+			delete(l.buckets, key)
+		}
+	}
+	l.lastSweep = now
+}
+
+// clientIP returns the IP of the client. Behind a trusted proxy it is the last address of
+// X-Forwarded-For, the one the proxy added, since earlier ones can be set by the client.
+func clientIP(r *http.Request, trustProxy bool) string {
+	if trustProxy {
+		if forwarded := r.Header.Values("X-Forwarded-For"); len(forwarded) > 0 {
+			addresses := strings.Split(forwarded[len(forwarded)-1], ",")
+			if ip := strings.TrimSpace(addresses[len(addresses)-1]); ip != "" {
+				return ip
+			}
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// seconds rounds d up to whole seconds
+func seconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,67 @@
+package openapi
+
+import (
+	"crypto/sha256"
+	_ "embed"
+	"encoding/base64"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"sync"
+
+	"github.com/gorilla/mux"
+)
+
+// docsPage is the Swagger UI page; it loads the UI assets from unpkg and the document from openapi.json
+//
+//go:embed docs.html
+var docsPage []byte
+
+// Handler serves the document of the routes registered on router.
+// The document is generated on the first request, once all routes are registered.
+func (s *Spec) Handler(router *mux.Router) http.Handler {
+	var (
+		once sync.Once
+		body []byte
+		err  error
+	)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		once.Do(func() {
+			var doc *Document
+			if doc, err = s.Build(router); err == nil {
+				body, err = json.MarshalIndent(doc, "", "  ")
+			}
+			if checkErr := s.Check(router); checkErr != nil {
+				slog.Warn("OpenAPI document is incomplete", "problems", checkErr)
+			}
+		})
+		if err != nil {
+			http.Error(w, "failed to generate OpenAPI document", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(body)
+	})
+}
+
+// docsContentSecurityPolicy allows the Swagger UI assets from unpkg, the inline script of
+// docsPage by its hash, and requests to the document on this server
+var docsContentSecurityPolicy = func() string {
+	script := regexp.MustCompile(`(?s)<script>(.*?)</script>`).FindSubmatch(docsPage)
+	hash := sha256.Sum256(script[1])
+	return "default-src 'none'; " +
+		"script-src https://unpkg.com 'sha256-" + base64.StdEncoding.EncodeToString(hash[:]) + "'; " +
+		"style-src https://unpkg.com 'unsafe-inline'; " +
+		"img-src 'self' data:; " +
+		"connect-src 'self'; " +
+		"frame-ancestors 'none'"
+}()
+
+// DocsHandler serves the Swagger UI for the document served next to it at openapi.json
+func DocsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Content-Security-Policy", docsContentSecurityPolicy)
+	w.Write(docsPage)
+}
@@ -0,0 +1,146 @@
+package openapi
+
+import (
+	"encoding"
+	"reflect"
+	"strings"
+	"time"
+
+	"eventsure-server/domain/chain"
+)
+
+var (
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	typeOfAddress     = reflect.TypeOf(chain.Address{})
+)
+
+// knownSchemas are the schemas of types that do not encode as their Go structure
+var knownSchemas = map[reflect.Type]Schema{
+	typeOfAddress: {
+		Type:        "string",
+		Pattern:     "^0x[0-9a-fA-F]{40}$",
+		Description: "Ethereum address; mixed-case addresses must carry a valid EIP-55 checksum",
+	},
+	reflect.TypeOf(time.Time{}): {Type: "string", Format: "date-time"},
+}
+
+// schemas generates JSON schemas from Go types. Named structs are
+// added to components once and referenced with $ref.
+type schemas struct {
+	components map[string]*Schema
+	names      map[reflect.Type]string
+}
+
+func newSchemas() *schemas {
+	return &schemas{
+		components: make(map[string]*Schema),
+		names:      make(map[reflect.Type]string),
+	}
+}
+
+// of returns the schema of the type of v
+func (s *schemas) of(v interface{}) *Schema {
+	return s.schema(reflect.TypeOf(v))
+}
+
+func (s *schemas) schema(t reflect.Type) *Schema {
+	if known, ok := knownSchemas[t]; ok {
+		return &known
+	}
+	if t.Kind() == reflect.Ptr {
+		return s.schema(t.Elem())
+	}
+	if t.Kind() != reflect.Struct && t.Implements(textMarshalerType) {
+		return &Schema{Type: "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: s.schema(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: s.schema(t.Elem())}
+	case reflect.Interface:
+		return &Schema{}
+	case reflect.Struct:
+		return s.structRef(t)
+	}
+	return &Schema{}
+}
+
+// structRef adds the schema of a struct to components and returns a reference to it.
+// Anonymous structs are inlined.
+func (s *schemas) structRef(t reflect.Type) *Schema {
+	if t.Name() == "" {
+		return s.structSchema(t)
+	}
+
+	name, ok := s.names[t]
+	if !ok {
+		name = s.componentName(t)
+		s.names[t] = name
+		// Register before generating the fields so recursive types terminate
+		s.components[name] = &Schema{}
+		*s.components[name] = *s.structSchema(t)
+	}
+	return &Schema{Ref: "#/components/schemas/" + name}
+}
+
+// componentName names a struct after its (capitalised) type, prefixed with its package when
+// another package already uses the name (e.g. pricing and simulation DTOs)
+func (s *schemas) componentName(t reflect.Type) string {
+	name := strings.ToUpper(t.Name()[:1]) + t.Name()[1:]
+	if _, taken := s.components[name]; !taken {
+		return name
+	}
+	pkg := t.PkgPath()
+	pkg = pkg[strings.LastIndex(pkg, "/")+1:]
+	return strings.ToUpper(pkg[:1]) + pkg[1:] + name
+}
+
+// structSchema describes the JSON encoding of a struct: fields are named after their json tag,
+// and fields without omitempty are required
+func (s *schemas) structSchema(t reflect.Type) *Schema {
+	schema := &Schema{
+		Type:       "object",
+		Properties: make(map[string]*Schema),
+	}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name := field.Name
+		omitempty := false
+		if tag, ok := field.Tag.Lookup("json"); ok {
+			if tag == "-" {
+				continue
+			}
+			parts := strings.Split(tag, ",")
+			if parts[0] != "" {
+				name = parts[0]
+			}
+			for _, option := range parts[1:] {
+				if option == "omitempty" {
+					omitempty = true
+				}
+			}
+		}
+
+		schema.Properties[name] = s.schema(field.Type)
+		if !omitempty && field.Type.Kind() != reflect.Ptr {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+	return schema
+}
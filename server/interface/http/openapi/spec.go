@@ -0,0 +1,270 @@
+package openapi
+
+import (
+	"errors"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"eventsure-server/interface/http/httperr"
+
+	"github.com/gorilla/mux"
+)
+
+// Operation describes a route for the generated document.
+// Request and response bodies are example values of the DTO types; their schemas
+// are generated from the types' json tags.
+type Operation struct {
+	OperationID string
+	Summary     string
+	Description string
+	Tags        []string
+	Deprecated  bool
+	// Parameters lists the query and header parameters, and path parameters that need a description.
+	// Other path parameters of the route are added as required strings.
+	Parameters []Parameter
+	// Request is the JSON request body, or nil
+	Request interface{}
+	// Responses maps status codes to responses; error responses default to the error envelope
+	Responses map[int]Response
+}
+
+// Response describes one response of an Operation
+type Response struct {
+	Description string
+	// Body is the JSON body, or nil for no body
+	Body interface{}
+	// ContentType overrides application/json, e.g. for text or HTML responses
+	ContentType string
+	Headers     map[string]Header
+}
+
+// Query returns an optional string query parameter, restricted to enum when given
+func Query(name, description string, enum ...string) Parameter {
+	return Parameter{
+		Name:        name,
+		In:          "query",
+		Description: description,
+		Schema:      &Schema{Type: "string", Enum: enum},
+	}
+}
+
+// Path returns a path parameter with schema
+func Path(name, description string, schema Schema) Parameter {
+	return Parameter{
+		Name:        name,
+		In:          "path",
+		Description: description,
+		Required:    true,
+		Schema:      &schema,
+	}
+}
+
+// HeaderParam returns an optional request header parameter
+func HeaderParam(name, description string) Parameter {
+	return Parameter{
+		Name:        name,
+		In:          "header",
+		Description: description,
+		Schema:      &Schema{Type: "string"},
+	}
+}
+
+// AddressSchema is the schema of an Ethereum address parameter
+func AddressSchema() Schema {
+	return knownSchemas[typeOfAddress]
+}
+
+// Spec holds the operations of the API keyed by method and path template
+type Spec struct {
+	info       Info
+	operations map[string]Operation
+}
+
+// NewSpec creates a new empty Spec
+func NewSpec(info Info) *Spec {
+	return &Spec{
+		info:       info,
+		operations: make(map[string]Operation),
+	}
+}
+
+// Add describes the route registered for method and path (a mux path template such as /api/episodes/{episode}/events)
+func (s *Spec) Add(method, path string, op Operation) {
+	s.operations[operationKey(method, path)] = op
+}
+
+func operationKey(method, path string) string {
+	return strings.ToUpper(method) + " " + path
+}
+
+// routeKeys returns the method and path template of every route registered on router
+func routeKeys(router *mux.Router) ([]string, error) {
+	var keys []string
+	err := router.Walk(func(route *mux.Route, _ *mux.Router, _ []*mux.Route) error {
+		path, err := route.GetPathTemplate()
+		if err != nil {
+			return nil
+		}
+		methods, err := route.GetMethods()
+		if err != nil {
+			// Prefixes and subrouters have no methods of their own
+			return nil
+		}
+		for _, method := range methods {
+			keys = append(keys, operationKey(method, path))
+		}
+		return nil
+	})
+	return keys, err
+}
+
+// Check reports routes registered on router without an operation, operations
+// without a route, and missing or duplicate operation IDs
+func (s *Spec) Check(router *mux.Router) error {
+	keys, err := routeKeys(router)
+	if err != nil {
+		return err
+	}
+
+	var problems []string
+	routed := make(map[string]bool)
+	for _, key := range keys {
+		routed[key] = true
+		if _, ok := s.operations[key]; !ok {
+			problems = append(problems, "route "+key+" has no OpenAPI operation")
+		}
+	}
+
+	ids := make(map[string]string)
+	for key, op := range s.operations {
+		if !routed[key] {
+			problems = append(problems, "operation "+key+" has no route")
+		}
+		if op.OperationID == "" {
+			problems = append(problems, "operation "+key+" has no operationId")
+		} else if other, ok := ids[op.OperationID]; ok {
+			problems = append(problems, "operationId "+op.OperationID+" is used by "+other+" and "+key)
+		}
+		ids[op.OperationID] = key
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+	sort.Strings(problems)
+	return errors.New(strings.Join(problems, "\n"))
+}
+
+// pathParam matches the variables of a mux path template
+var pathParam = regexp.MustCompile(`\{([^}:]+)(?::[^}]*)?\}`)
+
+// Build generates the document for the routes registered on router.
+// Routes without an operation are left out; Check reports them.
+func (s *Spec) Build(router *mux.Router) (*Document, error) {
+	keys, err := routeKeys(router)
+	if err != nil {
+		return nil, err
+	}
+
+	gen := newSchemas()
+	errorRef := gen.of(httperr.Envelope{})
+	doc := &Document{
+		OpenAPI: Version,
+		Info:    s.info,
+		Paths:   make(map[string]PathItem),
+	}
+
+	for _, key := range keys {
+		op, ok := s.operations[key]
+		if !ok {
+			continue
+		}
+		method, path, _ := strings.Cut(key, " ")
+		openAPIPath := pathParam.ReplaceAllString(path, "{$1}")
+
+		object := &OperationObject{
+			OperationID: op.OperationID,
+			Summary:     op.Summary,
+			Description: op.Description,
+			Tags:        op.Tags,
+			Deprecated:  op.Deprecated,
+			Parameters:  pathParameters(path, op.Parameters),
+			Responses:   make(map[string]ResponseObject),
+		}
+		if op.Request != nil {
+			object.RequestBody = &RequestBodyObject{
+				Required: true,
+				Content:  map[string]MediaType{"application/json": {Schema: gen.of(op.Request)}},
+			}
+		}
+		for status, response := range op.Responses {
+			object.Responses[strconv.Itoa(status)] = responseObject(gen, status, response, errorRef)
+		}
+		object.Responses["default"] = ResponseObject{
+			Description: "Error",
+			Content:     map[string]MediaType{"application/json": {Schema: errorRef}},
+		}
+
+		item, ok := doc.Paths[openAPIPath]
+		if !ok {
+			item = make(PathItem)
+			doc.Paths[openAPIPath] = item
+		}
+		item[strings.ToLower(method)] = object
+	}
+
+	doc.Components.Schemas = gen.components
+	return doc, nil
+}
+
+// pathParameters returns params with a required string parameter added for
+// every variable of path that params does not describe
+func pathParameters(path string, params []Parameter) []Parameter {
+	described := make(map[string]bool)
+	for _, p := range params {
+		if p.In == "path" {
+			described[p.Name] = true
+		}
+	}
+
+	var result []Parameter
+	for _, match := range pathParam.FindAllStringSubmatch(path, -1) {
+		if !described[match[1]] {
+			result = append(result, Parameter{
+				Name:     match[1],
+				In:       "path",
+				Required: true,
+				Schema:   &Schema{Type: "string"},
+			})
+		}
+	}
+	return append(result, params...)
+}
+
+// responseObject converts a Response; error statuses without a body use the error envelope
+func responseObject(gen *schemas, status int, response Response, errorRef *Schema) ResponseObject {
+	object := ResponseObject{
+		Description: response.Description,
+		Headers:     response.Headers,
+	}
+	if object.Description == "" {
+		object.Description = http.StatusText(status)
+	}
+
+	contentType := response.ContentType
+	if contentType == "" {
+		contentType = "application/json"
+	}
+	switch {
+	case response.Body != nil:
+		object.Content = map[string]MediaType{contentType: {Schema: gen.of(response.Body)}}
+	case response.ContentType != "":
+		object.Content = map[string]MediaType{contentType: {Schema: &Schema{Type: "string"}}}
+	case status >= http.StatusBadRequest:
+		object.Content = map[string]MediaType{"application/json": {Schema: errorRef}}
+	}
+	return object
+}
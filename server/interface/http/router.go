@@ -1,10 +1,18 @@
 package http
 
 import (
+	"encoding/json"
 	"net/http"
+	"time"
 
+	apikeyusecase "eventsure-server/application/apikey"
+	domainapikey "eventsure-server/domain/apikey"
+	"eventsure-server/infrastructure/config"
+	"eventsure-server/infrastructure/metrics"
 	"eventsure-server/interface/http/controller"
+	"eventsure-server/interface/http/httperr"
 	"eventsure-server/interface/http/middleware"
+	"eventsure-server/interface/http/openapi"
 
 	"github.com/gorilla/mux"
 )
@@ -12,34 +20,215 @@ import (
 // Router sets up HTTP routes
 type Router struct {
 	episodeController *controller.EpisodeController
+	statsController   *controller.StatsController
+	pricingController *controller.PricingController
+	auditController   *controller.AuditController
+	healthController  *controller.HealthController
+	apiKeyController  *controller.APIKeyController
+	apiKeys           *apikeyusecase.UseCase
+	idempotency       *middleware.Idempotency
+	server            config.Server
+	security          config.Security
+	rateLimit         config.RateLimit
 }
 
-// NewRouter creates a new Router
-func NewRouter(episodeController *controller.EpisodeController) *Router {
+// NewRouter creates a new Router. apiKeys authenticates the API keys of requests and
+// idempotency replays retried POSTs; its Run must be started to drop expired responses.
+// server configures the v1 sunset and the body size limit;
+// security configures the security headers and rateLimit the per-client limits.
+// CORS wraps the router and is set up with middleware.NewCORS.
+func NewRouter(
+	episodeController *controller.EpisodeController,
+	statsController *controller.StatsController,
+	pricingController *controller.PricingController,
+	auditController *controller.AuditController,
+	healthController *controller.HealthController,
+	apiKeyController *controller.APIKeyController,
+	apiKeys *apikeyusecase.UseCase,
+	idempotency *middleware.Idempotency,
+	server config.Server,
+	security config.Security,
+	rateLimit config.RateLimit,
+) *Router {
 	return &Router{
 		episodeController: episodeController,
+		statsController:   statsController,
+		pricingController: pricingController,
+		auditController:   auditController,
+		healthController:  healthController,
+		apiKeyController:  apiKeyController,
+		apiKeys:           apiKeys,
+		idempotency:       idempotency,
+		server:            server,
+		security:          security,
+		rateLimit:         rateLimit,
 	}
 }
 
 // SetupRoutes sets up all routes
 func (r *Router) SetupRoutes(mux *mux.Router) {
+	// Every response carries an X-Request-ID, which error responses repeat as requestId
+	mux.Use(middleware.RequestID)
+	mux.NotFoundHandler = middleware.RequestID(http.HandlerFunc(httperr.NotFound))
+	mux.MethodNotAllowedHandler = middleware.RequestID(http.HandlerFunc(httperr.MethodNotAllowed))
+
+	// Security headers on every response, including errors for unmatched requests
+	securityHeaders := middleware.SecurityHeaders(r.security)
+	mux.Use(securityHeaders)
+	mux.NotFoundHandler = securityHeaders(mux.NotFoundHandler)
+	mux.MethodNotAllowedHandler = securityHeaders(mux.MethodNotAllowedHandler)
+
+	// Request count and latency per route template, including unmatched requests
+	mux.Use(middleware.Metrics)
+	mux.NotFoundHandler = middleware.Metrics(mux.NotFoundHandler)
+	mux.MethodNotAllowedHandler = middleware.Metrics(mux.MethodNotAllowedHandler)
+
+	// A server span per request, parent of the use case, Etherscan and Supabase spans
+	mux.Use(middleware.Tracing)
+
 	// Health check endpoint (for Railway/deployment health checks)
 	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
 		w.Write([]byte("OK"))
 	}).Methods("GET")
 
+	// Readiness (critical dependencies only) and the status of every dependency
+	mux.HandleFunc("/ready", r.healthController.GetReady).Methods("GET")
+	mux.HandleFunc("/health/details", r.healthController.GetHealthDetails).Methods("GET")
+
+	// Failed authentications are limited per IP before keys are looked up, so keys cannot be guessed
+	rateLimiter := middleware.NewRateLimiter(r.rateLimit)
+
+	// Prometheus metrics reveal routes, traffic and dependency errors, so scrapers need an admin key
+	mux.Handle("/metrics", rateLimiter.LimitAuthFailures(
+		middleware.Authenticate(r.apiKeys)(
+			middleware.RequireScope(domainapikey.ScopeAdmin)(metrics.Handler()),
+		),
+	)).Methods("GET")
+
 	api := mux.PathPrefix("/api").Subrouter()
 
 	// Apply logging middleware to all API routes
 	api.Use(middleware.LoggingMiddleware)
 
+	// Failed authentications are limited per IP before keys are looked up
+	api.Use(rateLimiter.LimitAuthFailures)
+
+	// API keys in Authorization: Bearer or X-API-Key identify partners; requests without one are anonymous
+	api.Use(middleware.Authenticate(r.apiKeys))
+
+	// Token bucket limits per API key, and lower ones per IP for anonymous and browser requests
+	api.Use(rateLimiter.Middleware)
+
+	// Request bodies of POST and PATCH routes are bounded
+	api.Use(middleware.BodyLimit(r.server.MaxBodyBytes))
+
+	// v1 keeps the response shapes of the unversioned API and is deprecated in favour of v2
+	v1Deprecation := middleware.NewDeprecation("v1", v1DeprecatedAt, r.server.APIV1Sunset, "/api/v2")
+
+	// API documentation generated from the routes and apiSpec
+	api.Handle("/openapi.json", apiSpec().Handler(mux)).Methods("GET")
+	api.HandleFunc("/docs", openapi.DocsHandler).Methods("GET")
+	api.HandleFunc("/versions", versionsHandler(v1Deprecation)).Methods("GET")
+
+	// Partner API keys are managed with a key having the admin scope, e.g. ADMIN_API_KEY
+	admin := api.PathPrefix("/admin").Subrouter()
+	admin.Use(middleware.RequireScope(domainapikey.ScopeAdmin))
+	r.setupAdminRoutes(admin)
+
+	v2 := api.PathPrefix("/v2").Subrouter()
+	r.setupV2Routes(v2, r.idempotency)
+
+	v1 := api.PathPrefix("/v1").Subrouter()
+	v1.Use(v1Deprecation.Middleware)
+	r.setupV1Routes(v1, r.idempotency)
+
+	// Unversioned routes are v1 routes kept for clients that predate versioning
+	unversioned := api.NewRoute().Subrouter()
+	unversioned.Use(v1Deprecation.Middleware)
+	r.setupV1Routes(unversioned, r.idempotency)
+}
+
+// v1DeprecatedAt is when v2 was introduced and v1 deprecated
+var v1DeprecatedAt = time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC)
+
+// setupAdminRoutes registers the routes that manage partner API keys, correct user episodes
+// and run on-demand audits
+func (r *Router) setupAdminRoutes(api *mux.Router) {
+	api.HandleFunc("/api-keys", r.apiKeyController.ListAPIKeys).Methods("GET")
+	api.HandleFunc("/api-keys", r.apiKeyController.IssueAPIKey).Methods("POST")
+	api.HandleFunc("/api-keys/{id}", r.apiKeyController.RevokeAPIKey).Methods("DELETE")
+
+	// Progress is derived from the indexed events; only admins override it by hand
+	api.HandleFunc("/user-episodes/{id}", r.episodeController.UpdateUserEpisode).Methods("PATCH")
+
+	// Audits read the chain many times, so only admins run them outside the background audit
+	api.HandleFunc("/audit/{episode}", r.auditController.RunEpisodeAudit).Methods("POST")
+}
+
+// setupV1Routes registers the v1 routes, which return the original response shapes
+func (r *Router) setupV1Routes(api *mux.Router, idempotency *middleware.Idempotency) {
 	// Episode endpoints
-	api.HandleFunc("/episodes", r.episodeController.GetEpisodes).Methods("GET")
+	api.HandleFunc("/episodes", r.episodeController.GetEpisodeAddresses).Methods("GET")
 	api.HandleFunc("/episodes/{episode}/events", r.episodeController.GetEpisodeEvents).Methods("GET")
 
 	// User Episode endpoints
-	api.HandleFunc("/user-episodes", r.episodeController.CreateUserEpisode).Methods("POST")
+	api.Handle("/user-episodes", idempotency.Middleware(http.HandlerFunc(r.episodeController.CreateUserEpisode))).Methods("POST")
 	api.HandleFunc("/user-episodes", r.episodeController.GetUserEpisodes).Methods("GET")
-	// TODO: User endpoints will be added later
+
+	r.setupSharedRoutes(api, idempotency)
+}
+
+// setupV2Routes registers the v2 routes: decoded events from the event store
+// and user episodes with their episode summaries
+func (r *Router) setupV2Routes(api *mux.Router, idempotency *middleware.Idempotency) {
+	// Episode endpoints
+	api.HandleFunc("/episodes", r.episodeController.GetEpisodes).Methods("GET")
+	api.HandleFunc("/episodes/{episode}/events", r.episodeController.GetIndexedEpisodeEvents).Methods("GET")
+
+	// User Episode endpoints
+	api.Handle("/user-episodes", idempotency.Middleware(http.HandlerFunc(r.episodeController.CreateUserEpisode))).Methods("POST")
+	api.HandleFunc("/user-episodes", r.episodeController.GetUserEpisodeDetails).Methods("GET")
+
+	r.setupSharedRoutes(api, idempotency)
+}
+
+// setupSharedRoutes registers the routes whose responses are the same in every version
+func (r *Router) setupSharedRoutes(api *mux.Router, idempotency *middleware.Idempotency) {
+	// Episode endpoints
+	api.HandleFunc("/episodes/{episode}/ledger", r.episodeController.GetEpisodeLedger).Methods("GET")
+
+	// User endpoints
+	api.HandleFunc("/users/{address}/portfolio", r.episodeController.GetUserPortfolio).Methods("GET")
+
+	// Stats endpoints
+	api.HandleFunc("/stats", r.statsController.GetStats).Methods("GET")
+
+	// Pricing endpoints
+	api.Handle("/pricing/quote", idempotency.Middleware(http.HandlerFunc(r.pricingController.Quote))).Methods("POST")
+
+	// Audit endpoints
+	api.HandleFunc("/audit/{episode}", r.auditController.GetEpisodeAudit).Methods("GET")
+}
+
+// VersionsResponse lists the current API version and the deprecated ones with their use
+type VersionsResponse struct {
+	Current    string                    `json:"current"`
+	Deprecated []middleware.VersionUsage `json:"deprecated"`
+}
+
+// versionsHandler handles GET /api/versions
+func versionsHandler(deprecations ...*middleware.Deprecation) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		response := VersionsResponse{
+			Current:    "v2",
+			Deprecated: make([]middleware.VersionUsage, len(deprecations)),
+		}
+		for i, d := range deprecations {
+			response.Deprecated[i] = d.Usage()
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response)
+	}
 }
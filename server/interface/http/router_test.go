@@ -0,0 +1,314 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	apikeyusecase "eventsure-server/application/apikey"
+	episodeusecase "eventsure-server/application/episode"
+	domainapikey "eventsure-server/domain/apikey"
+	eventsureepisode "eventsure-server/domain/episode"
+	"eventsure-server/infrastructure/config"
+	"eventsure-server/infrastructure/repository"
+	"eventsure-server/interface/http/controller"
+	"eventsure-server/interface/http/middleware"
+	"eventsure-server/interface/http/openapi"
+
+	"github.com/gorilla/mux"
+)
+
+// newTestRouter registers all routes; the handlers are not called
+func newTestRouter() *mux.Router {
+	return newTestRouterWithKeys(apikeyusecase.NewUseCase(nil, config.Default().Auth))
+}
+
+// newTestRouterWithKeys registers all routes, authenticating requests with apiKeys
+func newTestRouterWithKeys(apiKeys *apikeyusecase.UseCase) *mux.Router {
+	return newTestRouterWith(&controller.EpisodeController{}, apiKeys)
+}
+
+// newTestRouterWith registers all routes with episodeController, authenticating requests with apiKeys
+func newTestRouterWith(episodeController *controller.EpisodeController, apiKeys *apikeyusecase.UseCase) *mux.Router {
+	router := mux.NewRouter()
+	NewRouter(
+		episodeController,
+		&controller.StatsController{},
+		&controller.PricingController{},
+		&controller.AuditController{},
+		&controller.HealthController{},
+		&controller.APIKeyController{},
+		apiKeys,
+		middleware.NewIdempotency(config.Default().Server.IdempotencyWindow, config.Default().Server.IdempotencyMaxEntries),
+		config.Default().Server, config.Default().Security, config.Default().RateLimit,
+	).SetupRoutes(router)
+	return router
+}
+
+// issueTestKey stores an API key with scopes in repo and returns its secret
+func issueTestKey(t *testing.T, repo domainapikey.Repository, scopes ...domainapikey.Scope) string {
+	t.Helper()
+	key, secret, err := domainapikey.NewAPIKey("test", scopes, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.Create(context.Background(), key); err != nil {
+		t.Fatal(err)
+	}
+	return secret
+}
+
+func TestEveryRouteHasOpenAPIOperation(t *testing.T) {
+	if err := apiSpec().Check(newTestRouter()); err != nil {
+		t.Fatalf("routes and OpenAPI operations are out of sync:\n%v", err)
+	}
+}
+
+func TestOpenAPIDocument(t *testing.T) {
+	router := newTestRouter()
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/openapi.json", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET /api/openapi.json returned %d", rec.Code)
+	}
+
+	var doc openapi.Document
+	if err := json.Unmarshal(rec.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("invalid document: %v", err)
+	}
+	if doc.OpenAPI != openapi.Version {
+		t.Errorf("openapi = %q, want %q", doc.OpenAPI, openapi.Version)
+	}
+	if _, ok := doc.Paths["/api/episodes/{episode}/events"]["get"]; !ok {
+		t.Errorf("document has no GET /api/episodes/{episode}/events")
+	}
+
+	// Every $ref must point to a component
+	var refs []string
+	collectRefs(rec.Body.Bytes(), &refs)
+	for _, ref := range refs {
+		name := strings.TrimPrefix(ref, "#/components/schemas/")
+		if _, ok := doc.Components.Schemas[name]; !ok || name == ref {
+			t.Errorf("unresolved $ref %s", ref)
+		}
+	}
+}
+
+// collectRefs appends the $ref values found anywhere in the JSON document data
+func collectRefs(data []byte, refs *[]string) {
+	var walk func(v interface{})
+	walk = func(v interface{}) {
+		switch v := v.(type) {
+		case map[string]interface{}:
+			for key, value := range v {
+				if ref, ok := value.(string); ok && key == "$ref" {
+					*refs = append(*refs, ref)
+					continue
+				}
+				walk(value)
+			}
+		case []interface{}:
+			for _, value := range v {
+				walk(value)
+			}
+		}
+	}
+	var v interface{}
+	json.Unmarshal(data, &v)
+	walk(v)
+}
+
+func TestUserEpisodeCorrectionRequiresAdmin(t *testing.T) {
+	repo := repository.NewAPIKeyRepository()
+	router := newTestRouterWithKeys(apikeyusecase.NewUseCase(repo, config.Auth{}))
+	body := `{"progress":"claimed"}`
+
+	tests := []struct {
+		name   string
+		path   string
+		secret string
+		want   int
+	}{
+		{"anonymous", "/api/admin/user-episodes/1", "", http.StatusUnauthorized},
+		{"write scope", "/api/admin/user-episodes/1", issueTestKey(t, repo, domainapikey.ScopeRead, domainapikey.ScopeWrite), http.StatusForbidden},
+		{"unversioned path is gone", "/api/user-episodes/1", "", http.StatusNotFound},
+		{"v2 path is gone", "/api/v2/user-episodes/1", "", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPatch, tt.path, strings.NewReader(body))
+			if tt.secret != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.secret)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+			if rec.Code != tt.want {
+				t.Errorf("PATCH %s returned %d, want %d", tt.path, rec.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestMetricsRequireAdmin(t *testing.T) {
+	repo := repository.NewAPIKeyRepository()
+	router := newTestRouterWithKeys(apikeyusecase.NewUseCase(repo, config.Auth{}))
+
+	tests := []struct {
+		name   string
+		secret string
+		want   int
+	}{
+		{"anonymous", "", http.StatusUnauthorized},
+		{"write scope", issueTestKey(t, repo, domainapikey.ScopeRead, domainapikey.ScopeWrite), http.StatusForbidden},
+		{"admin scope", issueTestKey(t, repo, domainapikey.ScopeAdmin), http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+			if tt.secret != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.secret)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+			if rec.Code != tt.want {
+				t.Errorf("GET /metrics returned %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestAdminRoutesRejectAnonymousCallers(t *testing.T) {
+	repo := repository.NewAPIKeyRepository()
+	router := newTestRouterWithKeys(apikeyusecase.NewUseCase(repo, config.Auth{}))
+	readKey := issueTestKey(t, repo, domainapikey.ScopeRead, domainapikey.ScopeWrite)
+
+	var checked int
+	err := router.Walk(func(route *mux.Route, _ *mux.Router, _ []*mux.Route) error {
+		template, err := route.GetPathTemplate()
+		if err != nil || !strings.HasPrefix(template, "/api/admin/") {
+			return nil
+		}
+		methods, err := route.GetMethods()
+		if err != nil {
+			return nil
+		}
+		path := strings.NewReplacer("{id}", "1", "{episode}", "0x1234567890123456789012345678901234567890").Replace(template)
+
+		for _, method := range methods {
+			checked++
+			for _, tt := range []struct {
+				name   string
+				secret string
+				want   int
+			}{
+				{"anonymous", "", http.StatusUnauthorized},
+				{"without admin scope", readKey, http.StatusForbidden},
+			} {
+				req := httptest.NewRequest(method, path, strings.NewReader("{}"))
+				if tt.secret != "" {
+					req.Header.Set("Authorization", "Bearer "+tt.secret)
+				}
+				rec := httptest.NewRecorder()
+				router.ServeHTTP(rec, req)
+				if rec.Code != tt.want {
+					t.Errorf("%s %s %s returned %d, want %d", tt.name, method, path, rec.Code, tt.want)
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if checked == 0 {
+		t.Fatal("no admin routes found")
+	}
+}
+
+func TestKeyGuessingIsRateLimitedPerIP(t *testing.T) {
+	repo := repository.NewAPIKeyRepository()
+	router := newTestRouterWithKeys(apikeyusecase.NewUseCase(repo, config.Auth{}))
+	validKey := issueTestKey(t, repo, domainapikey.ScopeAdmin)
+	limit := config.Default().RateLimit.AuthFailuresPerMinute
+
+	// GET /api/versions needs no controller, so authenticated requests get through
+	request := func(secret, remoteAddr string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/versions", nil)
+		req.RemoteAddr = remoteAddr
+		if secret != "" {
+			req.Header.Set(middleware.APIKeyHeader, secret)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	const guesser = "192.0.2.1:1234"
+	for i := 0; i < limit; i++ {
+		if rec := request(fmt.Sprintf("esk_guess%d", i), guesser); rec.Code != http.StatusUnauthorized {
+			t.Fatalf("guess %d returned %d, want 401", i, rec.Code)
+		}
+	}
+
+	rec := request("esk_guess", guesser)
+	if rec.Code != http.StatusTooManyRequests || rec.Header().Get("Retry-After") == "" {
+		t.Errorf("guess after %d failures returned %d, want 429 with Retry-After", limit, rec.Code)
+	}
+	if rec := request(validKey, guesser); rec.Code != http.StatusTooManyRequests {
+		t.Errorf("valid key from the guessing IP returned %d, want 429 until the bucket refills", rec.Code)
+	}
+	if rec := request("", guesser); rec.Code != http.StatusOK {
+		t.Errorf("anonymous request from the guessing IP returned %d, want 200", rec.Code)
+	}
+	if rec := request(validKey, "198.51.100.7:1234"); rec.Code != http.StatusOK {
+		t.Errorf("valid key from another IP returned %d, want 200", rec.Code)
+	}
+}
+
+func TestV1EpisodesKeepLegacyShape(t *testing.T) {
+	summaries := repository.NewEpisodeSummaryRepository()
+	departure := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+	for i, address := range []string{
+		"0x605c39938bdec77f3ee744881753c3c458c69342",
+		"0xf4a02c1fa48dffb2721f29722ccedbf50497f940",
+		"0x9706f63f070787a0f91de69ba9399381bf1c2241",
+	} {
+		summary := eventsureepisode.NewSummary(address, "KE123", big.NewInt(1), big.NewInt(5),
+			departure, departure.Add(time.Hour), departure.Add(-time.Hour), departure, int64(100+i))
+		if err := summaries.Save(summary); err != nil {
+			t.Fatal(err)
+		}
+	}
+	episodes := controller.NewEpisodeController(episodeusecase.NewUseCase(nil, summaries, nil,
+		config.Etherscan{}, config.Contracts{}, config.Default().Summaries))
+	router := newTestRouterWith(episodes, apikeyusecase.NewUseCase(nil, config.Default().Auth))
+
+	// The unversioned and v1 routes return the addresses newest first, as before v2
+	const legacy = `{"episodes":["0x9706f63f070787a0f91de69ba9399381bf1c2241","0xf4a02c1fa48dffb2721f29722ccedbf50497f940","0x605c39938bdec77f3ee744881753c3c458c69342"]}` + "\n"
+	for _, path := range []string{"/api/episodes", "/api/v1/episodes"} {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Code != http.StatusOK || rec.Body.String() != legacy {
+			t.Errorf("GET %s = %d %s, want 200 %s", path, rec.Code, rec.Body.String(), legacy)
+		}
+		if rec.Header().Get("Deprecation") == "" {
+			t.Errorf("GET %s has no Deprecation header", path)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v2/episodes?limit=2", nil))
+	var v2 episodeusecase.ListEpisodesResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &v2); err != nil || rec.Code != http.StatusOK {
+		t.Fatalf("GET /api/v2/episodes = %d %s", rec.Code, rec.Body.String())
+	}
+	if len(v2.Episodes) != 2 || v2.NextCursor == "" {
+		t.Errorf("GET /api/v2/episodes returned %d summaries and cursor %q, want a page of 2 summaries", len(v2.Episodes), v2.NextCursor)
+	}
+}
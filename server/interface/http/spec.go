@@ -0,0 +1,440 @@
+package http
+
+import (
+	"net/http"
+
+	apikeyusecase "eventsure-server/application/apikey"
+	auditusecase "eventsure-server/application/audit"
+	episodeusecase "eventsure-server/application/episode"
+	healthusecase "eventsure-server/application/health"
+	pricingusecase "eventsure-server/application/pricing"
+	statsusecase "eventsure-server/application/stats"
+	eventsureepisode "eventsure-server/domain/episode"
+	"eventsure-server/domain/membership"
+	domainstats "eventsure-server/domain/stats"
+	"eventsure-server/interface/http/middleware"
+	"eventsure-server/interface/http/openapi"
+)
+
+// apiInfo describes the API in the generated OpenAPI document
+var apiInfo = openapi.Info{
+	Title:   "Eventsure API",
+	Version: "1.0.0",
+	Description: "Amounts are in wei and encoded as decimal strings. Addresses are returned in lowercase. " +
+		"Partners send their API key as Authorization: Bearer or X-API-Key; requests are rate limited per key, " +
+		"or per IP without one, and responses carry RateLimit-* headers.",
+}
+
+// progressValues are the user episode progress values accepted by the API
+var progressValues = []string{
+	string(membership.ProgressJoined),
+	string(membership.ProgressLocked),
+	string(membership.ProgressResolved),
+	string(membership.ProgressPayoutClaimable),
+	string(membership.ProgressSurplusClaimable),
+	string(membership.ProgressClaimed),
+	string(membership.ProgressWithdrawn),
+	string(membership.ProgressClosed),
+}
+
+// idempotencyKey documents the Idempotency-Key header of the POST endpoints
+var idempotencyKey = openapi.HeaderParam(middleware.IdempotencyKeyHeader,
+	"Replays the first response when the same request is retried with the same key (at most 255 characters)")
+
+// apiSpec describes every route registered by SetupRoutes.
+// Keep it in sync with the routes; router_test.go fails for routes without an operation.
+func apiSpec() *openapi.Spec {
+	spec := openapi.NewSpec(apiInfo)
+
+	spec.Add(http.MethodGet, "/health", openapi.Operation{
+		OperationID: "getHealth",
+		Summary:     "Health check",
+		Tags:        []string{"health"},
+		Responses: map[int]openapi.Response{
+			http.StatusOK: {Description: "The server is running", ContentType: "text/plain"},
+		},
+	})
+
+	spec.Add(http.MethodGet, "/ready", openapi.Operation{
+		OperationID: "getReady",
+		Summary:     "Readiness check",
+		Description: "Checks the configuration, storage and Etherscan. Details and errors are omitted; see /health/details.",
+		Tags:        []string{"health"},
+		Responses: map[int]openapi.Response{
+			http.StatusOK:                 {Description: "Every critical component is up", Body: healthusecase.HealthResponse{}},
+			http.StatusServiceUnavailable: {Description: "A critical component is down", Body: healthusecase.HealthResponse{}},
+		},
+	})
+
+	spec.Add(http.MethodGet, "/health/details", openapi.Operation{
+		OperationID: "getHealthDetails",
+		Summary:     "Status of every dependency",
+		Description: "Checks the configuration, storage, Etherscan (latest block) and indexer lag. " +
+			"status is down when a critical component is down and degraded when the indexer is.",
+		Tags: []string{"health"},
+		Responses: map[int]openapi.Response{
+			http.StatusOK:                 {Description: "Every critical component is up", Body: healthusecase.HealthResponse{}},
+			http.StatusServiceUnavailable: {Description: "A critical component is down", Body: healthusecase.HealthResponse{}},
+		},
+	})
+
+	spec.Add(http.MethodGet, "/metrics", openapi.Operation{
+		OperationID: "getMetrics",
+		Summary:     "Prometheus metrics",
+		Description: "Requires an API key with the admin scope. " +
+			"Request, Etherscan, Supabase, indexer and cache metrics in the Prometheus text exposition format.",
+		Tags: []string{"health"},
+		Responses: map[int]openapi.Response{
+			http.StatusOK:           {Description: "Metrics", ContentType: "text/plain"},
+			http.StatusUnauthorized: {Description: "Missing or invalid API key"},
+			http.StatusForbidden:    {Description: "The API key does not have the admin scope"},
+		},
+	})
+
+	spec.Add(http.MethodGet, "/api/openapi.json", openapi.Operation{
+		OperationID: "getOpenAPI",
+		Summary:     "This OpenAPI document",
+		Tags:        []string{"docs"},
+		Responses: map[int]openapi.Response{
+			http.StatusOK: {Description: "OpenAPI 3.1 document", Body: map[string]interface{}{}},
+		},
+	})
+
+	spec.Add(http.MethodGet, "/api/docs", openapi.Operation{
+		OperationID: "getDocs",
+		Summary:     "Swagger UI for this document",
+		Tags:        []string{"docs"},
+		Responses: map[int]openapi.Response{
+			http.StatusOK: {Description: "HTML page", ContentType: "text/html"},
+		},
+	})
+
+	spec.Add(http.MethodGet, "/api/versions", openapi.Operation{
+		OperationID: "getVersions",
+		Summary:     "List the API versions and the use of deprecated ones since the server started",
+		Tags:        []string{"docs"},
+		Responses: map[int]openapi.Response{
+			http.StatusOK: {Body: VersionsResponse{}},
+		},
+	})
+
+	spec.Add(http.MethodGet, "/api/admin/api-keys", openapi.Operation{
+		OperationID: "listAPIKeys",
+		Summary:     "List partner API keys, revoked ones included",
+		Description: "Requires an API key with the admin scope.",
+		Tags:        []string{"admin"},
+		Responses: map[int]openapi.Response{
+			http.StatusOK:           {Body: apikeyusecase.ListAPIKeysResponse{}},
+			http.StatusUnauthorized: {Description: "Missing or invalid API key"},
+			http.StatusForbidden:    {Description: "The API key does not have the admin scope"},
+		},
+	})
+
+	spec.Add(http.MethodPost, "/api/admin/api-keys", openapi.Operation{
+		OperationID: "issueAPIKey",
+		Summary:     "Issue an API key to a partner",
+		Description: "Requires an API key with the admin scope. key is the secret, returned only in this response.",
+		Tags:        []string{"admin"},
+		Request:     apikeyusecase.IssueAPIKeyRequest{},
+		Responses: map[int]openapi.Response{
+			http.StatusCreated:      {Description: "Issued", Body: apikeyusecase.IssueAPIKeyResponse{}},
+			http.StatusBadRequest:   {Description: "Invalid name, unknown scope or negative rate limit"},
+			http.StatusUnauthorized: {Description: "Missing or invalid API key"},
+			http.StatusForbidden:    {Description: "The API key does not have the admin scope"},
+		},
+	})
+
+	spec.Add(http.MethodDelete, "/api/admin/api-keys/{id}", openapi.Operation{
+		OperationID: "revokeAPIKey",
+		Summary:     "Revoke a partner API key",
+		Description: "Requires an API key with the admin scope. Revoking a revoked key returns it unchanged.",
+		Tags:        []string{"admin"},
+		Parameters:  []openapi.Parameter{openapi.Path("id", "API key ID", openapi.Schema{Type: "integer", Format: "int64"})},
+		Responses: map[int]openapi.Response{
+			http.StatusOK:           {Body: apikeyusecase.APIKeyDTO{}},
+			http.StatusBadRequest:   {Description: "Invalid ID"},
+			http.StatusUnauthorized: {Description: "Missing or invalid API key"},
+			http.StatusForbidden:    {Description: "The API key does not have the admin scope"},
+			http.StatusNotFound:     {Description: "No API key has the ID"},
+		},
+	})
+
+	spec.Add(http.MethodPatch, "/api/admin/user-episodes/{id}", openapi.Operation{
+		OperationID: "updateUserEpisode",
+		Summary:     "Correct the progress of a user episode",
+		Description: "Requires an API key with the admin scope. The correction holds until the next event of the episode is indexed.",
+		Tags:        []string{"admin"},
+		Parameters:  []openapi.Parameter{openapi.Path("id", "User episode ID", openapi.Schema{Type: "integer", Format: "int64"})},
+		Request:     episodeusecase.UpdateUserEpisodeRequest{},
+		Responses: map[int]openapi.Response{
+			http.StatusOK:           {Body: episodeusecase.UserEpisodeDTO{}},
+			http.StatusBadRequest:   {Description: "Invalid ID or unknown progress"},
+			http.StatusUnauthorized: {Description: "Missing or invalid API key"},
+			http.StatusForbidden:    {Description: "The API key does not have the admin scope"},
+			http.StatusNotFound:     {Description: "No user episode has the ID"},
+		},
+	})
+
+	spec.Add(http.MethodPost, "/api/admin/audit/{episode}", openapi.Operation{
+		OperationID: "runEpisodeAudit",
+		Summary:     "Audit an episode against its on-chain state now",
+		Description: "Requires an API key with the admin scope. Indexes the episode, replays its events and alerts new violations; " +
+			"GET /api/audit/{episode} returns the result afterwards.",
+		Tags:       []string{"admin"},
+		Parameters: []openapi.Parameter{openapi.Path("episode", "Episode contract address", openapi.AddressSchema())},
+		Responses: map[int]openapi.Response{
+			http.StatusOK:                 {Body: auditusecase.AuditResponse{}},
+			http.StatusBadRequest:         {Description: "Malformed address"},
+			http.StatusUnauthorized:       {Description: "Missing or invalid API key"},
+			http.StatusForbidden:          {Description: "The API key does not have the admin scope"},
+			http.StatusNotFound:           {Description: "The address is not an episode of the factory"},
+			http.StatusServiceUnavailable: {Description: "The chain could not be read"},
+		},
+	})
+
+	v1, v2 := v1Operations(), v2Operations()
+	addVersion(spec, "/api/v2", "", false, v2)
+	addVersion(spec, "/api/v1", "V1", true, v1)
+	addVersion(spec, "/api", "Unversioned", true, v1)
+	return spec
+}
+
+// route is an operation of a versioned route, with the path relative to the version prefix
+type route struct {
+	method    string
+	path      string
+	operation openapi.Operation
+}
+
+// addVersion adds routes under prefix. Operation IDs get suffix so they stay unique across versions.
+func addVersion(spec *openapi.Spec, prefix, suffix string, deprecated bool, routes []route) {
+	for _, rt := range routes {
+		op := rt.operation
+		op.OperationID += suffix
+		op.Deprecated = deprecated
+		spec.Add(rt.method, prefix+rt.path, op)
+	}
+}
+
+// v1Operations describes the routes registered by setupV1Routes
+func v1Operations() []route {
+	address := openapi.AddressSchema()
+	var routes []route
+
+	routes = append(routes, route{http.MethodGet, "/episodes", openapi.Operation{
+		OperationID: "listEpisodes",
+		Summary:     "List episode contract addresses",
+		Description: "Every episode address, newest first. /api/v2/episodes returns the summaries with filters, sorting and pagination.",
+		Tags:        []string{"episodes"},
+		Responses: map[int]openapi.Response{
+			http.StatusOK: {Body: episodeusecase.GetAllEpisodesResponse{}},
+		},
+	}})
+
+	routes = append(routes, route{http.MethodGet, "/episodes/{episode}/events", openapi.Operation{
+		OperationID: "getEpisodeEvents",
+		Summary:     "List the events of an episode",
+		Tags:        []string{"episodes"},
+		Parameters:  []openapi.Parameter{openapi.Path("episode", "Episode contract address", address)},
+		Responses: map[int]openapi.Response{
+			http.StatusOK:         {Body: episodeusecase.GetEpisodeEventsResponse{}},
+			http.StatusBadRequest: {Description: "Malformed address"},
+			http.StatusNotFound:   {Description: "The address is not an episode of the factory"},
+		},
+	}})
+
+	routes = append(routes, route{http.MethodPost, "/user-episodes", openapi.Operation{
+		OperationID: "createUserEpisode",
+		Summary:     "Record that a user joined an episode",
+		Tags:        []string{"user-episodes"},
+		Parameters:  []openapi.Parameter{idempotencyKey},
+		Request:     episodeusecase.CreateUserEpisodeRequest{},
+		Responses: map[int]openapi.Response{
+			http.StatusOK:         {Description: "The user already joined the episode", Body: episodeusecase.CreateUserEpisodeResponse{}},
+			http.StatusCreated:    {Description: "Created", Body: episodeusecase.CreateUserEpisodeResponse{}},
+			http.StatusBadRequest: {Description: "Malformed address, or the episode is not an episode of the factory"},
+			http.StatusConflict:   {Description: "A request with the same Idempotency-Key is in progress"},
+		},
+	}})
+
+	routes = append(routes, route{http.MethodGet, "/user-episodes", openapi.Operation{
+		OperationID: "getUserEpisodes",
+		Summary:     "List the episodes of a user or the users of an episode",
+		Description: "Exactly one of user and episode is required. With user the response has episodes, with episode it has users.",
+		Tags:        []string{"user-episodes"},
+		Parameters: []openapi.Parameter{
+			openapi.Query("user", "User address"),
+			openapi.Query("episode", "Episode address"),
+			openapi.Query("progress", "Only user episodes at this progress", progressValues...),
+		},
+		Responses: map[int]openapi.Response{
+			http.StatusOK:         {Description: "GetUserEpisodesResponse for user, GetEpisodeUsersResponse for episode", Body: userEpisodesResponse{}},
+			http.StatusBadRequest: {Description: "Missing or malformed user/episode, or unknown progress"},
+		},
+	}})
+
+	return append(routes, sharedOperations()...)
+}
+
+// v2Operations describes the routes registered by setupV2Routes
+func v2Operations() []route {
+	address := openapi.AddressSchema()
+	var routes []route
+
+	routes = append(routes, route{http.MethodGet, "/episodes", openapi.Operation{
+		OperationID: "listEpisodes",
+		Summary:     "List episode summaries",
+		Description: "Filters, sorts and pages the episode summaries refreshed from chain in the background; summaries overdue for a refresh are marked stale.",
+		Tags:        []string{"episodes"},
+		Parameters: []openapi.Parameter{
+			openapi.Query("state", "Episode state",
+				string(eventsureepisode.StateCreated), string(eventsureepisode.StateOpen), string(eventsureepisode.StateLocked),
+				string(eventsureepisode.StateResolved), string(eventsureepisode.StateSettled), string(eventsureepisode.StateClosed)),
+			openapi.Query("category", "Episode category",
+				string(eventsureepisode.CategoryFlightDelay), string(eventsureepisode.CategoryWeather), string(eventsureepisode.CategoryTripCancel)),
+			openapi.Query("flight", "Flight name"),
+			openapi.Query("departureFrom", "Earliest departure (RFC 3339 or YYYY-MM-DD)"),
+			openapi.Query("departureTo", "Latest departure (RFC 3339, or YYYY-MM-DD for the whole day)"),
+			openapi.Query("sort", "Sort field (default created)",
+				string(eventsureepisode.SortByCreationBlock), string(eventsureepisode.SortByDeparture), string(eventsureepisode.SortByTVL)),
+			openapi.Query("order", "Sort order (default desc)", "asc", "desc"),
+			openapi.Query("cursor", "nextCursor of the previous page, with the same sort and order"),
+			openapi.Query("limit", "Page size"),
+		},
+		Responses: map[int]openapi.Response{
+			http.StatusOK:         {Body: episodeusecase.ListEpisodesResponse{}},
+			http.StatusBadRequest: {Description: "Invalid filter, sort or cursor"},
+		},
+	}})
+
+	routes = append(routes, route{http.MethodGet, "/episodes/{episode}/events", openapi.Operation{
+		OperationID: "getEpisodeEvents",
+		Summary:     "List the decoded events of an episode",
+		Description: "Events come from the indexed event store and appear once the indexer has reached them.",
+		Tags:        []string{"episodes"},
+		Parameters:  []openapi.Parameter{openapi.Path("episode", "Episode contract address", address)},
+		Responses: map[int]openapi.Response{
+			http.StatusOK:         {Body: episodeusecase.GetIndexedEpisodeEventsResponse{}},
+			http.StatusBadRequest: {Description: "Malformed address"},
+			http.StatusNotFound:   {Description: "The address is not an episode of the factory"},
+		},
+	}})
+
+	routes = append(routes, route{http.MethodPost, "/user-episodes", openapi.Operation{
+		OperationID: "createUserEpisode",
+		Summary:     "Record that a user joined an episode",
+		Tags:        []string{"user-episodes"},
+		Parameters:  []openapi.Parameter{idempotencyKey},
+		Request:     episodeusecase.CreateUserEpisodeRequest{},
+		Responses: map[int]openapi.Response{
+			http.StatusOK:         {Description: "The user already joined the episode", Body: episodeusecase.CreateUserEpisodeResponse{}},
+			http.StatusCreated:    {Description: "Created", Body: episodeusecase.CreateUserEpisodeResponse{}},
+			http.StatusBadRequest: {Description: "Malformed address, or the episode is not an episode of the factory"},
+			http.StatusConflict:   {Description: "A request with the same Idempotency-Key is in progress"},
+		},
+	}})
+
+	routes = append(routes, route{http.MethodGet, "/user-episodes", openapi.Operation{
+		OperationID: "getUserEpisodes",
+		Summary:     "List user episodes with their episode summaries",
+		Description: "At least one of user and episode is required. summary is null until the episode summary has been synced.",
+		Tags:        []string{"user-episodes"},
+		Parameters: []openapi.Parameter{
+			openapi.Query("user", "User address"),
+			openapi.Query("episode", "Episode address"),
+			openapi.Query("progress", "Only user episodes at this progress", progressValues...),
+		},
+		Responses: map[int]openapi.Response{
+			http.StatusOK:         {Body: episodeusecase.GetUserEpisodeDetailsResponse{}},
+			http.StatusBadRequest: {Description: "Missing or malformed user/episode, or unknown progress"},
+		},
+	}})
+
+	return append(routes, sharedOperations()...)
+}
+
+// sharedOperations describes the routes registered by setupSharedRoutes
+func sharedOperations() []route {
+	address := openapi.AddressSchema()
+	var routes []route
+
+	routes = append(routes, route{http.MethodGet, "/episodes/{episode}/ledger", openapi.Operation{
+		OperationID: "getEpisodeLedger",
+		Summary:     "Export the ledger of an episode",
+		Description: "Premiums in, payouts and surplus withdrawals out, in chain order with amounts in wei and ETH and the running balance, " +
+			"reconciled with the on-chain balance of the contract. Entries come from the indexed event store. " +
+			"With format=csv the ledger is a text/csv download whose last line, of type reconciliation, has the difference " +
+			"to the on-chain balance as amount and the on-chain balance as balance.",
+		Tags: []string{"episodes"},
+		Parameters: []openapi.Parameter{
+			openapi.Path("episode", "Episode contract address", address),
+			openapi.Query("format", "Response format (default json)", "json", "csv"),
+		},
+		Responses: map[int]openapi.Response{
+			http.StatusOK:                 {Body: episodeusecase.GetEpisodeLedgerResponse{}},
+			http.StatusBadRequest:         {Description: "Malformed address or unknown format"},
+			http.StatusNotFound:           {Description: "The address is not an episode of the factory"},
+			http.StatusServiceUnavailable: {Description: "The on-chain balance could not be read"},
+		},
+	}})
+
+	routes = append(routes, route{http.MethodGet, "/users/{address}/portfolio", openapi.Operation{
+		OperationID: "getUserPortfolio",
+		Summary:     "Get what a wallet paid and can currently claim",
+		Tags:        []string{"users"},
+		Parameters:  []openapi.Parameter{openapi.Path("address", "User address", address)},
+		Responses: map[int]openapi.Response{
+			http.StatusOK:         {Body: episodeusecase.GetUserPortfolioResponse{}},
+			http.StatusBadRequest: {Description: "Malformed address"},
+		},
+	}})
+
+	routes = append(routes, route{http.MethodGet, "/stats", openapi.Operation{
+		OperationID: "getStats",
+		Summary:     "Get protocol statistics",
+		Tags:        []string{"stats"},
+		Parameters: []openapi.Parameter{
+			openapi.Query("interval", "Series bucket size (default daily)", string(domainstats.IntervalDaily), string(domainstats.IntervalWeekly)),
+		},
+		Responses: map[int]openapi.Response{
+			http.StatusOK:         {Body: statsusecase.GetStatsResponse{}},
+			http.StatusBadRequest: {Description: "Unknown interval"},
+		},
+	}})
+
+	routes = append(routes, route{http.MethodPost, "/pricing/quote", openapi.Operation{
+		OperationID: "quotePremium",
+		Summary:     "Quote a premium/payout pair from the historical delay rate",
+		Tags:        []string{"pricing"},
+		Parameters:  []openapi.Parameter{idempotencyKey},
+		Request:     pricingusecase.QuoteRequest{},
+		Responses: map[int]openapi.Response{
+			http.StatusOK:                 {Body: pricingusecase.QuoteResponse{}},
+			http.StatusBadRequest:         {Description: "Invalid request"},
+			http.StatusConflict:           {Description: "A request with the same Idempotency-Key is in progress"},
+			http.StatusServiceUnavailable: {Description: "No pricing dataset is loaded"},
+		},
+	}})
+
+	routes = append(routes, route{http.MethodGet, "/audit/{episode}", openapi.Operation{
+		OperationID: "getEpisodeAudit",
+		Summary:     "Get the latest invariant audit of an episode",
+		Description: "Returns the result of the background audit that runs every AUDIT_INTERVAL; it does not read the chain.",
+		Tags:        []string{"audit"},
+		Parameters:  []openapi.Parameter{openapi.Path("episode", "Episode contract address", address)},
+		Responses: map[int]openapi.Response{
+			http.StatusOK:         {Body: auditusecase.AuditResponse{}},
+			http.StatusBadRequest: {Description: "Malformed address"},
+			http.StatusNotFound:   {Description: "The episode has not been audited, or is not an episode of the factory"},
+		},
+	}})
+
+	return routes
+}
+
+// userEpisodesResponse documents GET /api/user-episodes, which returns
+// either GetUserEpisodesResponse or GetEpisodeUsersResponse
+type userEpisodesResponse struct {
+	Episodes []episodeusecase.UserEpisodeDTO `json:"episodes,omitempty"`
+	Users    []episodeusecase.UserEpisodeDTO `json:"users,omitempty"`
+}
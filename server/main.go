@@ -1,67 +1,171 @@
 package main
 
 import (
-	"log"
-	"net/http"
+	"context"
+	"log/slog"
 	"os"
-	"path/filepath"
 
+	apikeyusecase "eventsure-server/application/apikey"
+	auditusecase "eventsure-server/application/audit"
 	episodeusecase "eventsure-server/application/episode"
+	healthusecase "eventsure-server/application/health"
+	"eventsure-server/application/indexer"
+	pricingusecase "eventsure-server/application/pricing"
+	progressusecase "eventsure-server/application/progress"
+	statsusecase "eventsure-server/application/stats"
+	"eventsure-server/domain/audit"
+	"eventsure-server/domain/pricing"
+	"eventsure-server/infrastructure/alert"
+	"eventsure-server/infrastructure/config"
+	"eventsure-server/infrastructure/flightdata"
+	"eventsure-server/infrastructure/logging"
+	"eventsure-server/infrastructure/runner"
+	"eventsure-server/infrastructure/storage"
+	"eventsure-server/infrastructure/tracing"
 	httprouter "eventsure-server/interface/http"
 	"eventsure-server/interface/http/controller"
+	"eventsure-server/interface/http/middleware"
 
 	"github.com/gorilla/mux"
-	"github.com/joho/godotenv"
-	"github.com/rs/cors"
 )
 
 func main() {
-	// Load .env file
-	workDir, err := os.Getwd()
-	if err == nil {
-		// 가능한 .env 파일 경로들
-		possiblePaths := []string{
-			filepath.Join(workDir, ".env"),             // 현재 디렉토리
-			filepath.Join(workDir, "..", ".env"),       // 상위 디렉토리
-			filepath.Join(workDir, "..", "..", ".env"), // 상위 상위 디렉토리
-		}
+	// Settings come from the environment, .env and CONFIG_FILE (see 설정 in API_SPEC.md)
+	cfg, err := config.Load(config.Options{})
+	if err != nil {
+		slog.Error("invalid configuration", "error", err)
+		os.Exit(1)
+	}
+
+	// JSON logs at LOG_LEVEL
+	if err := logging.Setup(cfg.Log.Level); err != nil {
+		slog.Warn(err.Error())
+	}
+	if file := cfg.DotEnvFile(); file != "" {
+		slog.Info("loaded .env file", "path", file)
+	}
+	if file := cfg.File(); file != "" {
+		slog.Info("loaded config file", "path", file)
+	}
+
+	// Traces go to the exporter selected by OTEL_TRACES_EXPORTER (see 트레이싱 in API_SPEC.md)
+	exporter, shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing)
+	if err != nil {
+		slog.Warn("tracing is disabled", "error", err)
+	}
+	slog.Info("using trace exporter", "exporter", exporter)
+
+	// Initialize repositories (see STORAGE in API_SPEC.md)
+	repos, err := storage.Open(cfg.Storage, cfg.Supabase)
+	if err != nil {
+		slog.Error("failed to open storage", "error", err)
+		os.Exit(1)
+	}
+	slog.Info("using storage", "backend", repos.Backend)
+
+	// Start event indexer
+	var eventIndexer *indexer.Indexer
+	if cfg.Contracts.EpisodeFactory != "" {
+		eventIndexer = indexer.NewIndexer(repos.Events, repos.Checkpoints, cfg.Indexer, cfg.Etherscan, cfg.Contracts)
+	} else {
+		slog.Warn("EPISODE_CONTRACT_FACTORY is not set, event indexer is disabled")
+	}
 
-		for _, envPath := range possiblePaths {
-			if err := godotenv.Load(envPath); err == nil {
-				log.Printf("Loaded .env file from: %s\n", envPath)
-				break
-			}
+	// Dependency checks for /ready and /health/details
+	healthUseCase := healthusecase.NewUseCase(
+		healthusecase.ConfigCheck(cfg, repos.Backend),
+		healthusecase.StorageCheck(repos.Backend, repos),
+		healthusecase.EtherscanCheck(cfg.Etherscan),
+		healthusecase.IndexerCheck(eventIndexer, cfg.Health.MaxIndexerLag),
+	)
+
+	// Fail fast on misconfiguration, unless DEGRADED_MODE allows serving with critical components down
+	health := healthUseCase.Details(context.Background())
+	for name, component := range health.Components {
+		if component.Status == healthusecase.StatusDown {
+			slog.Warn("component is down", "component", name, "critical", component.Critical, "error", component.Error)
+		}
+	}
+	if !health.Ready() {
+		if !cfg.Server.DegradedMode {
+			slog.Error("critical components are down, set DEGRADED_MODE=true to start anyway")
+			os.Exit(1)
 		}
+		slog.Warn("starting in degraded mode, /ready returns 503 until critical components are up")
 	}
 
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "3000"
+	// Keep user_episodes progress in step with the indexed events
+	progressUseCase := progressusecase.NewUseCase(repos.Events, repos.Checkpoints, repos.UserEpisodes)
+
+	// Invariant alerts go to the log, and to a webhook when configured
+	var alerter audit.Alerter = alert.NewLogAlerter()
+	if webhookAlerter, err := alert.NewWebhookAlerter(cfg.Alert); err == nil {
+		alerter = alert.NewMultiAlerter(alerter, webhookAlerter)
+	}
+
+	// Load historical flight arrivals for pricing
+	var pricingModel *pricing.Model
+	if path := cfg.Pricing.Dataset; path != "" {
+		result, err := flightdata.LoadArrivalsCSV(path)
+		if err != nil {
+			slog.Error("failed to load pricing dataset", "path", path, "error", err)
+		} else {
+			pricingModel = pricing.NewModel(result.Arrivals)
+			slog.Info("loaded pricing dataset", "path", path, "arrivals", len(result.Arrivals), "skipped", result.Skipped)
+		}
 	}
 
 	// Initialize use cases
-	episodeUseCase := episodeusecase.NewUseCase()
+	episodeUseCase := episodeusecase.NewUseCase(repos.UserEpisodes, repos.Summaries, repos.Events, cfg.Etherscan, cfg.Contracts, cfg.Summaries)
+	statsUseCase := statsusecase.NewUseCase(repos.Events)
+	pricingUseCase := pricingusecase.NewUseCase(pricingModel)
+	auditUseCase := auditusecase.NewUseCase(repos.Events, eventIndexer, alerter, cfg.Etherscan, cfg.Contracts)
+	apiKeyUseCase := apikeyusecase.NewUseCase(repos.APIKeys, cfg.Auth)
 
 	// Initialize controllers
 	episodeController := controller.NewEpisodeController(episodeUseCase)
+	statsController := controller.NewStatsController(statsUseCase)
+	pricingController := controller.NewPricingController(pricingUseCase)
+	auditController := controller.NewAuditController(auditUseCase)
+	healthController := controller.NewHealthController(healthUseCase)
+	apiKeyController := controller.NewAPIKeyController(apiKeyUseCase)
+
+	// Retried POSTs with the same Idempotency-Key replay the first response, per instance
+	idempotency := middleware.NewIdempotency(cfg.Server.IdempotencyWindow, cfg.Server.IdempotencyMaxEntries)
 
 	// Initialize router
-	router := httprouter.NewRouter(episodeController)
+	router := httprouter.NewRouter(episodeController, statsController, pricingController, auditController, healthController,
+		apiKeyController, apiKeyUseCase, idempotency, cfg.Server, cfg.Security, cfg.RateLimit)
 
 	// Setup mux
 	r := mux.NewRouter()
 	router.SetupRoutes(r)
 
-	// CORS configuration
-	c := cors.New(cors.Options{
-		AllowedOrigins:   []string{"*"},
-		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"*"},
-		AllowCredentials: true,
-	})
+	// Browser origins allowed by CORS_ALLOWED_ORIGINS (see 설정 in API_SPEC.md)
+	handler := middleware.NewCORS(cfg.CORS).Handler(r)
 
-	handler := c.Handler(r)
+	// Serve until SIGTERM/SIGINT, then drain requests, stop and flush the workers and release resources
+	app := runner.New(handler, cfg.Server)
+	app.Go(runner.Worker{Name: "idempotency", Run: idempotency.Run})
+	if eventIndexer != nil {
+		app.Go(runner.Worker{Name: "indexer", Run: eventIndexer.Run})
+		// The final sync carries over the events the indexer stored while shutting down
+		app.Go(runner.Worker{
+			Name:  "progress",
+			Run:   func(ctx context.Context) { progressUseCase.Run(ctx, cfg.Indexer.Interval) },
+			Flush: progressUseCase.SyncOnce,
+		})
+	}
+	if cfg.Contracts.EpisodeFactory != "" {
+		// The listing serves the summaries as last refreshed by this worker
+		app.Go(runner.Worker{Name: "summaries", Run: func(ctx context.Context) { episodeUseCase.RunSummarySync(ctx, cfg.Summaries.Interval) }})
+		app.Go(runner.Worker{Name: "audit", Run: func(ctx context.Context) { auditUseCase.Run(ctx, cfg.Audit.Interval) }})
+	}
+	app.OnShutdown("tracing", shutdownTracing)
+	app.OnShutdown("storage", func(context.Context) error { return repos.Close() })
 
-	log.Printf("Server starting on port %s", port)
-	log.Fatal(http.ListenAndServe(":"+port, handler))
+	if err := app.Run(context.Background()); err != nil {
+		slog.Error("server stopped", "error", err)
+		os.Exit(1)
+	}
 }